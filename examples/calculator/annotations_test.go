@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestWithToolAnnotation_RoundTripsOntoTool(t *testing.T) {
+	tool := mcp.NewTool("widget",
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Widget",
+			ReadOnlyHint:    true,
+			DestructiveHint: false,
+			IdempotentHint:  true,
+		}),
+	)
+
+	if tool.Annotations.Title != "Widget" {
+		t.Errorf("Title = %q, want %q", tool.Annotations.Title, "Widget")
+	}
+	if !tool.Annotations.ReadOnlyHint {
+		t.Error("ReadOnlyHint = false, want true")
+	}
+	if tool.Annotations.DestructiveHint != false {
+		t.Error("DestructiveHint = true, want false")
+	}
+	if !tool.Annotations.IdempotentHint {
+		t.Error("IdempotentHint = false, want true")
+	}
+}
+
+func TestWithToolAnnotation_UnsetOpenWorldHintOmitted(t *testing.T) {
+	tool := mcp.NewTool("widget")
+
+	if tool.Annotations.OpenWorldHint {
+		t.Error("OpenWorldHint should be false when no annotation was given")
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerDescribeResultTool wires up describe_result, render_result's
+// counterpart for mixed text+image content.
+//
+// This was originally written to also return a short synthesized tone as
+// an mcp.AudioContent block, but that type doesn't exist in the pinned
+// mcp-go version — mcp.Content here is limited to what the library
+// actually exports (mcp.TextContent, mcp.ImageContent, mcp.EmbeddedResource),
+// so describe_result sticks to text+image, the same pair
+// handleRenderResultTool in image.go already returns.
+func registerDescribeResultTool(s *server.MCPServer) {
+	tool := mcp.NewTool("describe_result",
+		mcp.WithDescription("Perform a calculation and return the result as text and an image swatch"),
+		mcp.WithString("operation",
+			mcp.Required(),
+			mcp.Description("The operation to perform (add, subtract, multiply, divide)"),
+			mcp.Enum("add", "subtract", "multiply", "divide"),
+		),
+		mcp.WithNumber("x", mcp.Required()),
+		mcp.WithNumber("y", mcp.Required()),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Describe Result",
+			ReadOnlyHint:    true,
+			DestructiveHint: false,
+			IdempotentHint:  true,
+		}),
+	)
+	addToolWithMiddleware(s, tool, handleDescribeResultTool, recoverMiddleware)
+}
+
+func handleDescribeResultTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var args calculateArgs
+	if err := bindArguments(request, &args); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result, err := calculate(args.Operation, args.X, args.Y)
+	if err != nil {
+		if pe, ok := err.(*protocolError); ok {
+			return nil, pe
+		}
+		return toolResultErrorf("%s", err.Error()), nil
+	}
+
+	png, err := renderResultPNG(result)
+	if err != nil {
+		return nil, internalErrorf("rendering result: %v", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{Type: "text", Text: fmt.Sprintf("%v", result)},
+			mcp.ImageContent{Type: "image", Data: base64.StdEncoding.EncodeToString(png), MIMEType: "image/png"},
+		},
+	}, nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleDescribeResultTool_ReturnsTextAndImageContent(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"operation": "multiply", "x": 3.0, "y": 4.0}
+
+	res, err := handleDescribeResultTool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(res.Content) != 2 {
+		t.Fatalf("len(Content) = %d, want 2", len(res.Content))
+	}
+
+	text, ok := res.Content[0].(mcp.TextContent)
+	if !ok || text.Text != "12" {
+		t.Fatalf("Content[0] = %v, want text \"12\"", res.Content[0])
+	}
+
+	img, ok := res.Content[1].(mcp.ImageContent)
+	if !ok || img.MIMEType != "image/png" {
+		t.Fatalf("Content[1] = %v, want an image/png ImageContent", res.Content[1])
+	}
+}
+
+func TestHandleDescribeResultTool_DivideByZeroIsToolDomainError(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"operation": "divide", "x": 1.0, "y": 0.0}
+
+	res, err := handleDescribeResultTool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsError {
+		t.Errorf("IsError = false, want true")
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// requireBearerAuth wraps next with a bearer-token check: requests missing
+// an "Authorization: Bearer <token>" header matching token get a 401
+// without ever reaching next (and therefore never reaching the MCP
+// session/JSON-RPC layer). An empty token disables the check entirely,
+// since that's the zero value of transportConfig.AuthToken and we don't
+// want auth silently required just because someone touched this file.
+//
+// This only guards the HTTP-based transports (sse, http, both); stdio has
+// no request/response cycle to authenticate and is unaffected.
+func requireBearerAuth(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !bearerTokenMatches(r.Header.Get("Authorization"), token) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(authErrorBody{Error: "missing or invalid bearer token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authErrorBody is the JSON body requireBearerAuth writes alongside a 401.
+type authErrorBody struct {
+	Error string `json:"error"`
+}
+
+// bearerTokenMatches compares in constant time so a client probing for
+// the right token can't learn anything from how long the comparison
+// takes.
+func bearerTokenMatches(header, token string) bool {
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return false
+	}
+	presented := header[len(prefix):]
+	return subtle.ConstantTimeCompare([]byte(presented), []byte(token)) == 1
+}
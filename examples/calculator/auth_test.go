@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestRequireBearerAuth_EmptyTokenDisablesCheck(t *testing.T) {
+	srv := httptest.NewServer(requireBearerAuth("", okHandler()))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestRequireBearerAuth_MissingHeaderIsUnauthorized(t *testing.T) {
+	srv := httptest.NewServer(requireBearerAuth("secret", okHandler()))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+	if got := resp.Header.Get("WWW-Authenticate"); got != "Bearer" {
+		t.Errorf("WWW-Authenticate = %q, want %q", got, "Bearer")
+	}
+	var body authErrorBody
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding JSON error body: %v", err)
+	}
+	if body.Error == "" {
+		t.Error("expected a non-empty error message in the JSON body")
+	}
+}
+
+func TestRequireBearerAuth_WrongTokenIsUnauthorized(t *testing.T) {
+	srv := httptest.NewServer(requireBearerAuth("secret", okHandler()))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestRequireBearerAuth_MatchingTokenPassesThrough(t *testing.T) {
+	srv := httptest.NewServer(requireBearerAuth("secret", okHandler()))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+// TestServe_StdioIgnoresAuthToken proves -auth-token has no effect on the
+// stdio transport: serveStdioTolerant's signature never even receives a
+// transportConfig, so a request with no Authorization header at all still
+// completes the handshake when AuthToken is set.
+func TestServe_StdioIgnoresAuthToken(t *testing.T) {
+	origStdin, origStdout := os.Stdin, os.Stdout
+	defer func() { os.Stdin, os.Stdout = origStdin, origStdout }()
+
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdin): %v", err)
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdout): %v", err)
+	}
+	os.Stdin, os.Stdout = stdinR, stdoutW
+
+	srv := newTestServer()
+	cfg := transportConfig{AuthToken: "secret"}
+	done := make(chan error, 1)
+	go func() { done <- serve(context.Background(), srv, "stdio", cfg) }()
+
+	writeLine(t, stdinW, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`)
+
+	scanner := bufio.NewScanner(stdoutR)
+	if !scanner.Scan() {
+		t.Fatalf("expected an initialize response, scan error: %v", scanner.Err())
+	}
+	var resp map[string]any
+	if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshaling initialize response: %v", err)
+	}
+	if _, ok := resp["result"]; !ok {
+		t.Fatalf("expected a result despite no Authorization header, got %v", resp)
+	}
+
+	stdinW.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serve returned %v after EOF, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("serve did not return after stdin closed")
+	}
+	stdoutW.Close()
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxBatchSize caps the length of calculate_batch's operations argument, so
+// one call can't tie up the worker pool (or the response payload)
+// indefinitely. main wires it to --max-batch.
+var maxBatchSize = 1000
+
+// batchWorkers bounds how many operations run concurrently within a single
+// calculate_batch call.
+const batchWorkers = 8
+
+// registerCalculateBatchTool wires up calculate_batch, which runs a list of
+// calculate-shaped operations concurrently and reports each one's outcome
+// inline instead of failing the whole call on the first bad operand.
+func registerCalculateBatchTool(s *server.MCPServer) {
+	tool := mcp.NewTool("calculate_batch",
+		mcp.WithDescription("Run a batch of arithmetic operations, reporting each result or error inline"),
+		withArray("operations", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"operation": map[string]any{"type": "string", "enum": []string{"add", "subtract", "multiply", "divide"}},
+				"x":         map[string]any{"type": "number"},
+				"y":         map[string]any{"type": "number"},
+			},
+			"required": []string{"operation", "x", "y"},
+		}, required()),
+	)
+	finalizeRequired(&tool)
+	addToolWithMiddleware(s, tool, handleCalculateBatchTool, recoverMiddleware)
+}
+
+// batchOperation is one element of calculate_batch's operations argument.
+type batchOperation struct {
+	Operation string
+	X, Y      float64
+}
+
+// batchResult is one element of calculate_batch's structured content: one
+// of Result or Error is set, never both.
+type batchResult struct {
+	Result float64 `json:"result,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
+
+func handleCalculateBatchTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	raw, ok := request.Params.Arguments["operations"].([]any)
+	if !ok {
+		return nil, invalidParamsf("missing required argument: operations")
+	}
+	if len(raw) > maxBatchSize {
+		return nil, invalidParamsf("operations: got %d entries, exceeds the limit of %d", len(raw), maxBatchSize)
+	}
+
+	ops := make([]batchOperation, len(raw))
+	for i, item := range raw {
+		op, err := parseBatchOperation(item)
+		if err != nil {
+			return nil, invalidParamsf("operations[%d]: %v", i, err)
+		}
+		ops[i] = op
+	}
+
+	results := make([]batchResult, len(ops))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchWorkers)
+
+	for i, op := range ops {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, op batchOperation) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = runBatchOperation(ctx, op)
+		}(i, op)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(results)
+	if err != nil {
+		return nil, internalErrorf("encoding batch results: %v", err)
+	}
+
+	return mcp.NewToolResultText(string(encoded)), nil
+}
+
+func runBatchOperation(ctx context.Context, op batchOperation) batchResult {
+	if err := ctx.Err(); err != nil {
+		return batchResult{Error: err.Error()}
+	}
+	result, err := calculate(op.Operation, op.X, op.Y)
+	if err != nil {
+		return batchResult{Error: err.Error()}
+	}
+	return batchResult{Result: result}
+}
+
+func parseBatchOperation(item any) (batchOperation, error) {
+	obj, ok := item.(map[string]any)
+	if !ok {
+		return batchOperation{}, fmt.Errorf("expected an object, got %T", item)
+	}
+
+	op, ok := obj["operation"].(string)
+	if !ok {
+		return batchOperation{}, fmt.Errorf("missing required property: operation")
+	}
+	x, ok := obj["x"].(float64)
+	if !ok {
+		return batchOperation{}, fmt.Errorf("missing required property: x")
+	}
+	y, ok := obj["y"].(float64)
+	if !ok {
+		return batchOperation{}, fmt.Errorf("missing required property: y")
+	}
+
+	return batchOperation{Operation: op, X: x, Y: y}, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func decodeBatchResults(t *testing.T, res *mcp.CallToolResult) []batchResult {
+	t.Helper()
+
+	text, ok := res.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("Content[0] = %T, want mcp.TextContent", res.Content[0])
+	}
+
+	var results []batchResult
+	if err := json.Unmarshal([]byte(text.Text), &results); err != nil {
+		t.Fatalf("unmarshal batch results: %v", err)
+	}
+	return results
+}
+
+func batchRequest(ops []any) mcp.CallToolRequest {
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "calculate_batch"
+	req.Params.Arguments = map[string]any{"operations": ops}
+	return req
+}
+
+func TestHandleCalculateBatchTool_MixedSuccessAndFailure(t *testing.T) {
+	ops := []any{
+		map[string]any{"operation": "add", "x": 1.0, "y": 2.0},
+		map[string]any{"operation": "divide", "x": 1.0, "y": 0.0},
+		map[string]any{"operation": "bogus", "x": 1.0, "y": 2.0},
+	}
+
+	res, err := handleCalculateBatchTool(context.Background(), batchRequest(ops))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results := decodeBatchResults(t, res)
+	if len(results) != 3 {
+		t.Fatalf("len(results) = %d, want 3", len(results))
+	}
+	if results[0].Result != 3 || results[0].Error != "" {
+		t.Errorf("results[0] = %+v, want Result=3", results[0])
+	}
+	if results[1].Error == "" {
+		t.Errorf("results[1] = %+v, want a divide-by-zero error", results[1])
+	}
+	if results[2].Error == "" {
+		t.Errorf("results[2] = %+v, want a bad-operation error", results[2])
+	}
+}
+
+func TestHandleCalculateBatchTool_EmptyArray(t *testing.T) {
+	res, err := handleCalculateBatchTool(context.Background(), batchRequest([]any{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results := decodeBatchResults(t, res)
+	if len(results) != 0 {
+		t.Fatalf("results = %v, want an empty []batchResult", results)
+	}
+}
+
+func TestHandleCalculateBatchTool_OverLimitIsRejected(t *testing.T) {
+	old := maxBatchSize
+	maxBatchSize = 2
+	defer func() { maxBatchSize = old }()
+
+	ops := []any{
+		map[string]any{"operation": "add", "x": 1.0, "y": 1.0},
+		map[string]any{"operation": "add", "x": 1.0, "y": 1.0},
+		map[string]any{"operation": "add", "x": 1.0, "y": 1.0},
+	}
+
+	_, err := handleCalculateBatchTool(context.Background(), batchRequest(ops))
+	pe, ok := err.(*protocolError)
+	if !ok {
+		t.Fatalf("err = %T, want *protocolError", err)
+	}
+	if pe.Code() != mcp.INVALID_PARAMS {
+		t.Errorf("code = %d, want %d", pe.Code(), mcp.INVALID_PARAMS)
+	}
+}
+
+func TestHandleCalculateBatchTool_MissingOperationsIsInvalidParams(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "calculate_batch"
+
+	_, err := handleCalculateBatchTool(context.Background(), req)
+	if _, ok := err.(*protocolError); !ok {
+		t.Fatalf("err = %T, want *protocolError", err)
+	}
+}
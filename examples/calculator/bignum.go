@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerCalculateBigTool wires up calculate_big, calculate's arbitrary-
+// precision counterpart: operands and the result travel as strings so
+// neither side loses precision to float64 along the way (calculate's
+// inputSchema takes numbers, and the JSON-RPC layer decodes all JSON
+// numbers to float64 before a handler ever sees them — see
+// handleEchoTool's doc comment for the same float64 boundary).
+func registerCalculateBigTool(s *server.MCPServer) {
+	tool := mcp.NewTool("calculate_big",
+		mcp.WithDescription("Arbitrary-precision arithmetic; operands and result are exact decimal strings"),
+		mcp.WithString("operation",
+			mcp.Required(),
+			mcp.Description("The operation to perform (add, subtract, multiply, divide)"),
+			mcp.Enum("add", "subtract", "multiply", "divide"),
+		),
+		mcp.WithString("x", mcp.Required(), mcp.Description("First operand, as a decimal string")),
+		mcp.WithString("y", mcp.Required(), mcp.Description("Second operand, as a decimal string")),
+		mcp.WithNumber("scale",
+			mcp.Description("Decimal places in the result (relevant mainly to divide, which is the one operation that can be non-terminating)"),
+			mcp.Min(0),
+			mcp.Max(100),
+			mcp.DefaultNumber(10),
+		),
+	)
+	addToolWithMiddleware(s, tool, handleCalculateBigTool, newDefaultsMiddleware(tool), recoverMiddleware)
+}
+
+type calculateBigArgs struct {
+	Operation string  `json:"operation"`
+	X         string  `json:"x"`
+	Y         string  `json:"y"`
+	Scale     float64 `json:"scale" mcp:"optional"`
+}
+
+// calculateBig mirrors calculate's operation set, but over big.Rat so
+// operands of any size and decimals of any precision round-trip exactly;
+// the result is formatted to scale decimal places, which only discards
+// information for divide (the one operation that can be non-terminating).
+//
+// As with calculate, an operation outside the declared enum or an
+// unparseable operand returns a *protocolError (the request itself was
+// malformed); dividing by zero returns a plain error (the request was
+// fine, the computation wasn't).
+func calculateBig(op, xs, ys string, scale int) (string, error) {
+	x, ok := new(big.Rat).SetString(xs)
+	if !ok {
+		return "", invalidParamsf("x: %q is not a valid decimal number", xs)
+	}
+	y, ok := new(big.Rat).SetString(ys)
+	if !ok {
+		return "", invalidParamsf("y: %q is not a valid decimal number", ys)
+	}
+
+	z := new(big.Rat)
+	switch canonicalOperation(op) {
+	case "add":
+		z.Add(x, y)
+	case "subtract":
+		z.Sub(x, y)
+	case "multiply":
+		z.Mul(x, y)
+	case "divide":
+		if y.Sign() == 0 {
+			return "", fmt.Errorf("cannot divide by zero")
+		}
+		z.Quo(x, y)
+	default:
+		return "", invalidParamsf("operation %s isn't supported", op)
+	}
+
+	return z.FloatString(scale), nil
+}
+
+func handleCalculateBigTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var args calculateBigArgs
+	if err := bindArguments(request, &args); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result, err := calculateBig(args.Operation, args.X, args.Y, int(args.Scale))
+	if err != nil {
+		if pe, ok := err.(*protocolError); ok {
+			return nil, pe
+		}
+		return toolResultErrorf("%s", err.Error()), nil
+	}
+
+	encoded, err := json.Marshal(map[string]string{"result": result})
+	if err != nil {
+		return nil, internalErrorf("encoding result: %v", err)
+	}
+
+	return mcp.NewToolResultText(string(encoded)), nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func bigRequest(x, y string, scale float64) mcp.CallToolRequest {
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "calculate_big"
+	req.Params.Arguments = map[string]any{
+		"operation": "add",
+		"x":         x,
+		"y":         y,
+		"scale":     scale,
+	}
+	return req
+}
+
+func TestCalculateBig_FortyDigitIntegersAddExactly(t *testing.T) {
+	x := "12345678901234567890123456789012345678901"
+	y := "1"
+	want := "12345678901234567890123456789012345678902"
+
+	got, err := calculateBig("add", x, y, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != want {
+		t.Errorf("calculateBig(add, %s, %s) = %s, want %s", x, y, got, want)
+	}
+}
+
+func TestCalculateBig_DivideIsExactToTheRequestedScale(t *testing.T) {
+	got, err := calculateBig("divide", "1", "3", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "0.3333333333" {
+		t.Errorf("calculateBig(divide, 1, 3) = %s, want 0.3333333333", got)
+	}
+}
+
+func TestCalculateBig_DivideByZeroIsAToolDomainFailure(t *testing.T) {
+	_, err := calculateBig("divide", "1", "0", 10)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, ok := err.(*protocolError); ok {
+		t.Errorf("err = %T, want a plain error (divide by zero is a tool-domain failure)", err)
+	}
+}
+
+func TestCalculateBig_BeyondFloat64PrecisionStillExact(t *testing.T) {
+	// 2^60 + 1 has no exact float64 representation, but round-trips exactly
+	// through big.Rat since it arrives as a decimal string, not a JSON number.
+	x := "1152921504606846977"
+	got, err := calculateBig("add", x, "0", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != x {
+		t.Errorf("calculateBig(add, %s, 0) = %s, want %s", x, got, x)
+	}
+}
+
+func TestCalculateBig_UnparseableOperandIsInvalidParams(t *testing.T) {
+	_, err := calculateBig("add", "not-a-number", "1", 0)
+	pe, ok := err.(*protocolError)
+	if !ok {
+		t.Fatalf("err = %T, want *protocolError", err)
+	}
+	if pe.Code() != mcp.INVALID_PARAMS {
+		t.Errorf("code = %d, want %d", pe.Code(), mcp.INVALID_PARAMS)
+	}
+}
+
+func TestCalculateBig_UnsupportedOperationIsInvalidParams(t *testing.T) {
+	_, err := calculateBig("bogus", "1", "1", 0)
+	if _, ok := err.(*protocolError); !ok {
+		t.Fatalf("err = %T, want *protocolError", err)
+	}
+}
+
+func TestHandleCalculateBigTool_ReturnsExactStringResult(t *testing.T) {
+	res, err := handleCalculateBigTool(context.Background(), bigRequest("10", "20", 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text, ok := res.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("Content[0] = %T, want mcp.TextContent", res.Content[0])
+	}
+	var structured map[string]string
+	if err := json.Unmarshal([]byte(text.Text), &structured); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if structured["result"] != "30" {
+		t.Errorf("result = %s, want 30", structured["result"])
+	}
+}
+
+func TestHandleCalculateBigTool_DivideByZeroIsAToolResultError(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "calculate_big"
+	req.Params.Arguments = map[string]any{"operation": "divide", "x": "1", "y": "0", "scale": 10.0}
+
+	res, err := handleCalculateBigTool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsError {
+		t.Errorf("result.IsError = false, want true")
+	}
+}
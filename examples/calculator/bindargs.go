@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// bindArguments decodes request.Params.Arguments into dst, a pointer to a
+// struct whose fields carry `json` tags matching the tool's argument names.
+// It exists because mcp-go v0.23.1 has no equivalent of the proposed
+// mcp.BindArguments/server.AddTypedTool helpers yet; once the dependency is
+// bumped past that, handlers can switch to the upstream version directly.
+//
+// A missing argument for any field not tagged `mcp:"optional"` is reported
+// as a descriptive error instead of a panic, and JSON numbers
+// (always float64 once decoded) are coerced into int, int64, and float64
+// struct fields as needed.
+func bindArguments(request mcp.CallToolRequest, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bindArguments: dst must be a pointer to a struct")
+	}
+
+	elem := v.Elem()
+	t := elem.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := field.Tag.Get("json")
+		name := field.Name
+		if tag != "" && tag != "-" {
+			for j, c := range tag {
+				if c == ',' {
+					tag = tag[:j]
+					break
+				}
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+
+		raw, ok := request.Params.Arguments[name]
+		if !ok {
+			optional := field.Tag.Get("mcp") == "optional"
+			if !optional {
+				return fmt.Errorf("missing required argument: %s", name)
+			}
+			continue
+		}
+
+		if err := assignArgument(elem.Field(i), name, raw); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func assignArgument(field reflect.Value, name string, raw any) error {
+	switch field.Kind() {
+	case reflect.String:
+		s, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("argument %s: expected string, got %T", name, raw)
+		}
+		field.SetString(s)
+	case reflect.Float64, reflect.Float32:
+		n, ok := raw.(float64)
+		if !ok {
+			return fmt.Errorf("argument %s: expected number, got %T", name, raw)
+		}
+		field.SetFloat(n)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := raw.(float64)
+		if !ok {
+			return fmt.Errorf("argument %s: expected number, got %T", name, raw)
+		}
+		field.SetInt(int64(n))
+	case reflect.Bool:
+		b, ok := raw.(bool)
+		if !ok {
+			return fmt.Errorf("argument %s: expected boolean, got %T", name, raw)
+		}
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("argument %s: unsupported field kind %s", name, field.Kind())
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestBindArguments_MissingRequired(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"operation": "add",
+		"x":         1.0,
+	}
+
+	var args calculateArgs
+	err := bindArguments(req, &args)
+	if err == nil {
+		t.Fatal("expected an error for the missing y argument")
+	}
+
+	if err.Error() != "missing required argument: y" {
+		t.Errorf("error = %q, want %q", err.Error(), "missing required argument: y")
+	}
+}
+
+func TestBindArguments_WrongType(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"operation": "add",
+		"x":         "5",
+		"y":         1.0,
+	}
+
+	var args calculateArgs
+	if err := bindArguments(req, &args); err == nil {
+		t.Fatal("expected an error for a string where a number was expected")
+	}
+}
+
+func TestBindArguments_NullValue(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"operation": "add",
+		"x":         nil,
+		"y":         1.0,
+	}
+
+	var args calculateArgs
+	if err := bindArguments(req, &args); err == nil {
+		t.Fatal("expected an error for a null value where a number was expected")
+	}
+}
+
+func TestBindArguments_ExtraUnknownKeys(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"operation": "add",
+		"x":         2.0,
+		"y":         3.0,
+		"comment":   "please add these for me",
+	}
+
+	var args calculateArgs
+	if err := bindArguments(req, &args); err != nil {
+		t.Fatalf("unexpected error with an extra unknown key: %v", err)
+	}
+
+	if args.X != 2 || args.Y != 3 {
+		t.Errorf("got %+v, want x=2 y=3", args)
+	}
+}
+
+func TestHandleCalculateTool_MalformedArgumentsDoNotPanic(t *testing.T) {
+	cases := []struct {
+		name string
+		args map[string]any
+	}{
+		{
+			name: "missing field",
+			args: map[string]any{"operation": "add", "x": 1.0},
+		},
+		{
+			name: "wrong type",
+			args: map[string]any{"operation": "add", "x": "5", "y": 1.0},
+		},
+		{
+			name: "null value",
+			args: map[string]any{"operation": "add", "x": nil, "y": 1.0},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Name = "calculate"
+			req.Params.Arguments = tc.args
+
+			res, err := handle_calculate_tool(context.Background(), req)
+			if err != nil {
+				t.Fatalf("unexpected transport-level error: %v", err)
+			}
+
+			if !res.IsError {
+				t.Fatalf("expected a tool error result for malformed arguments")
+			}
+		})
+	}
+}
+
+func TestBindArguments_BoolField(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"x":       1.0,
+		"y":       2.0,
+		"verbose": true,
+	}
+
+	var args slowAddArgs
+	if err := bindArguments(req, &args); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !args.Verbose {
+		t.Errorf("Verbose = false, want true")
+	}
+}
+
+func TestBindArguments_Success(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"operation": "add",
+		"x":         2.0,
+		"y":         3.0,
+	}
+
+	var args calculateArgs
+	if err := bindArguments(req, &args); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if args.Operation != "add" || args.X != 2 || args.Y != 3 {
+		t.Errorf("got %+v, want {add 2 3}", args)
+	}
+}
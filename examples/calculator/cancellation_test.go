@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleCalculateTool_AbortsOnCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"operation": "add", "x": 1.0, "y": 1.0}
+
+	res, err := handle_calculate_tool(ctx, req)
+	if err == nil {
+		t.Fatal("expected a cancellation error")
+	}
+	if res != nil {
+		t.Fatalf("expected no result for a cancelled request, got %v", res)
+	}
+}
+
+// TestHandleSlowAddTool_CancellationAbortsPromptly stands in for a client
+// disconnecting mid-call (or sending notifications/cancelled): whichever
+// mechanism the mcp-go dispatcher uses to cancel a request's ctx, the
+// handler's job is the same, and slow_add's increment loop already selects
+// on ctx.Done() between steps. A long configured duration that actually
+// completes would mean that select isn't doing its job.
+func TestHandleSlowAddTool_CancellationAbortsPromptly(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"x": 1.0, "y": 1.0, "duration_ms": 60000.0}
+
+	start := time.Now()
+	_, err := handleSlowAddTool(ctx, req)
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("expected a cancellation error")
+	}
+	if elapsed > time.Second {
+		t.Errorf("handleSlowAddTool took %v to abort, want well under the configured 60s duration", elapsed)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+// capabilitiesEnabled holds the on/off state of each capability surface
+// this fixture advertises. main wires these to --no-tools,
+// --no-resources, --no-prompts, and --no-logging; all default to true so
+// the flags are purely opt-out. Disabling one both drops it from the
+// server.With*Capabilities options passed to server.NewMCPServer (so it's
+// absent from the initialize response) and gates its methods through
+// methodAvailableForEnabledCapabilities (so calling them anyway gets
+// method-not-found instead of a real dispatch).
+var capabilitiesEnabled = struct {
+	Tools     bool
+	Resources bool
+	Prompts   bool
+	Logging   bool
+}{Tools: true, Resources: true, Prompts: true, Logging: true}
+
+// capabilityGatedMethods maps each method this fixture dispatches to the
+// capabilitiesEnabled field that must be true for it to be answered. A
+// method absent from this map (ping, initialize, notifications/*) is
+// never gated by a capability toggle.
+var capabilityGatedMethods = map[string]*bool{
+	"tools/list":               &capabilitiesEnabled.Tools,
+	"tools/call":               &capabilitiesEnabled.Tools,
+	"resources/list":           &capabilitiesEnabled.Resources,
+	"resources/read":           &capabilitiesEnabled.Resources,
+	"resources/subscribe":      &capabilitiesEnabled.Resources,
+	"resources/unsubscribe":    &capabilitiesEnabled.Resources,
+	"resources/templates/list": &capabilitiesEnabled.Resources,
+	"prompts/list":             &capabilitiesEnabled.Prompts,
+	"prompts/get":              &capabilitiesEnabled.Prompts,
+	"logging/setLevel":         &capabilitiesEnabled.Logging,
+}
+
+// methodAvailableForEnabledCapabilities reports whether method should be
+// dispatched given capabilitiesEnabled, the same shape as
+// methodAvailableAtPinnedVersion for protocol-version gating.
+func methodAvailableForEnabledCapabilities(method string) bool {
+	enabled, gated := capabilityGatedMethods[method]
+	if !gated {
+		return true
+	}
+	return *enabled
+}
+
+// effectiveCapabilitySet renders capabilitiesEnabled for the startup log,
+// e.g. "tools,resources,prompts" when logging alone is disabled.
+func effectiveCapabilitySet() string {
+	var on []string
+	if capabilitiesEnabled.Tools {
+		on = append(on, "tools")
+	}
+	if capabilitiesEnabled.Resources {
+		on = append(on, "resources")
+	}
+	if capabilitiesEnabled.Prompts {
+		on = append(on, "prompts")
+	}
+	if capabilitiesEnabled.Logging {
+		on = append(on, "logging")
+	}
+	if len(on) == 0 {
+		return "(none)"
+	}
+	result := on[0]
+	for _, c := range on[1:] {
+		result += "," + c
+	}
+	return result
+}
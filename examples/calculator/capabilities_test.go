@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// capabilityTestServer mirrors main's serverOpts construction for the
+// capabilities currently enabled, so tests exercise the same wiring
+// main uses rather than a hand-rolled stand-in.
+func capabilityTestServer() *server.MCPServer {
+	var opts []server.ServerOption
+	if capabilitiesEnabled.Logging {
+		opts = append(opts, server.WithLogging())
+	}
+	if capabilitiesEnabled.Resources {
+		opts = append(opts, server.WithResourceCapabilities(true, true))
+	}
+	if capabilitiesEnabled.Tools {
+		opts = append(opts, server.WithToolCapabilities(true))
+	}
+	if capabilitiesEnabled.Prompts {
+		opts = append(opts, server.WithPromptCapabilities(true))
+	}
+	s := server.NewMCPServer("Calculator", "0.1.0", opts...)
+	tool := mcp.NewTool("calculate",
+		mcp.WithDescription("Perform basic arithmetic operations"),
+		mcp.WithString("operation", mcp.Required(), mcp.Enum("add", "subtract", "multiply", "divide")),
+		mcp.WithNumber("x", mcp.Required()),
+		mcp.WithNumber("y", mcp.Required()),
+	)
+	s.AddTool(tool, handle_calculate_tool)
+	return s
+}
+
+func resetCapabilitiesEnabled() {
+	capabilitiesEnabled.Tools = true
+	capabilitiesEnabled.Resources = true
+	capabilitiesEnabled.Prompts = true
+	capabilitiesEnabled.Logging = true
+}
+
+func initializeResultCapabilities(t *testing.T, s *server.MCPServer) map[string]json.RawMessage {
+	t.Helper()
+
+	req, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "initialize",
+		"params": map[string]any{
+			"protocolVersion": "2025-06-18",
+			"capabilities":    map[string]any{},
+			"clientInfo":      map[string]any{"name": "test", "version": "0.0.1"},
+		},
+	})
+	resp := s.HandleMessage(context.Background(), req)
+	b, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("marshaling initialize response: %v", err)
+	}
+
+	var envelope struct {
+		Result struct {
+			Capabilities map[string]json.RawMessage `json:"capabilities"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		t.Fatalf("unmarshaling initialize response: %v", err)
+	}
+	return envelope.Result.Capabilities
+}
+
+func TestCapabilitiesEnabled_DefaultsToAllOn(t *testing.T) {
+	resetCapabilitiesEnabled()
+	if got := effectiveCapabilitySet(); got != "tools,resources,prompts,logging" {
+		t.Errorf("effectiveCapabilitySet() = %q, want all four", got)
+	}
+}
+
+func TestInitializeResponse_OmitsDisabledCapabilities(t *testing.T) {
+	resetCapabilitiesEnabled()
+	capabilitiesEnabled.Tools = false
+	defer resetCapabilitiesEnabled()
+
+	caps := initializeResultCapabilities(t, capabilityTestServer())
+	if _, ok := caps["tools"]; ok {
+		t.Error("expected tools to be absent from the initialize response")
+	}
+	if _, ok := caps["resources"]; !ok {
+		t.Error("expected resources to remain present")
+	}
+}
+
+func TestInitializeResponse_CapabilitiesCompose(t *testing.T) {
+	resetCapabilitiesEnabled()
+	capabilitiesEnabled.Tools = false
+	capabilitiesEnabled.Prompts = false
+	defer resetCapabilitiesEnabled()
+
+	caps := initializeResultCapabilities(t, capabilityTestServer())
+	if _, ok := caps["tools"]; ok {
+		t.Error("expected tools to be absent")
+	}
+	if _, ok := caps["prompts"]; ok {
+		t.Error("expected prompts to be absent")
+	}
+	if _, ok := caps["resources"]; !ok {
+		t.Error("expected resources to remain present when not disabled")
+	}
+}
+
+func TestMethodAvailableForEnabledCapabilities_GatesDisabledSurfaces(t *testing.T) {
+	resetCapabilitiesEnabled()
+	capabilitiesEnabled.Resources = false
+	defer resetCapabilitiesEnabled()
+
+	if methodAvailableForEnabledCapabilities("resources/read") {
+		t.Error("expected resources/read to be unavailable with resources disabled")
+	}
+	if !methodAvailableForEnabledCapabilities("tools/call") {
+		t.Error("expected tools/call to remain available")
+	}
+	if !methodAvailableForEnabledCapabilities("ping") {
+		t.Error("expected an ungated method like ping to always be available")
+	}
+}
+
+func TestCheckProtocolPreconditions_MethodNotFoundForDisabledCapability(t *testing.T) {
+	resetCapabilitiesEnabled()
+	capabilitiesEnabled.Prompts = false
+	defer resetCapabilitiesEnabled()
+
+	violation := checkProtocolPreconditions("sess-caps", jsonrpcEnvelope{ID: float64(9), Method: "prompts/get"})
+	if violation == nil {
+		t.Fatal("expected a method-not-found violation for prompts/get with prompts disabled")
+	}
+	if violation.Error.Code != mcp.METHOD_NOT_FOUND {
+		t.Errorf("code = %d, want METHOD_NOT_FOUND", violation.Error.Code)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// chaosEnabled gates simulate_crash and simulate_hang (and panic_tool). They
+// exist purely to let a client's robustness tests induce failures a real
+// calculator server would never produce on its own, so they stay off by
+// default; main wires this to --enable-chaos.
+var chaosEnabled = false
+
+// registerChaosTools wires up the tools chaosEnabled gates. Call only when
+// chaosEnabled is true; main does so conditionally rather than having each
+// handler check the flag, so the tools don't even show up in tools/list on
+// the default binary.
+func registerChaosTools(s *server.MCPServer) {
+	registerSimulateCrashTool(s)
+	registerSimulateHangTool(s)
+	registerPanicTool(s)
+}
+
+// registerPanicTool wires up panic_tool, a deliberate, unconditional panic
+// used to exercise recoverMiddleware (and, transitively, the server's
+// ability to keep answering every other session after one handler blows
+// up) without reaching for raise_error's "panic" kind, which exists for a
+// different purpose (covering every error shape a client's error mapping
+// needs to handle, not specifically chaos testing).
+func registerPanicTool(s *server.MCPServer) {
+	tool := mcp.NewTool("panic_tool",
+		mcp.WithDescription("Panic unconditionally, to exercise panic recovery without taking the process down"),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:        "Panic Tool",
+			ReadOnlyHint: true,
+		}),
+	)
+	addToolWithMiddleware(s, tool, handlePanicTool, recoverMiddleware)
+}
+
+func handlePanicTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	panic("panic_tool: deliberate panic")
+}
+
+// registerSimulateCrashTool wires up simulate_crash.
+//
+// Ordering guarantee: the CallToolResult for this call is never sent — the
+// process exits from inside the handler, before the transport has anything
+// to write for this request's id. Every response the transport already
+// wrote for earlier requests is unaffected, since those writes completed
+// before this handler ever ran. os.Stdout.Sync is attempted first on a
+// best-effort basis, for the stdio transport's benefit, in case the
+// underlying file descriptor has anything buffered at the OS level; it's a
+// no-op (and its error is ignored) when stdout isn't a syncable file, which
+// is the common case for a pipe.
+func registerSimulateCrashTool(s *server.MCPServer) {
+	tool := mcp.NewTool("simulate_crash",
+		mcp.WithDescription("Flush pending output and exit the process immediately, for crash-recovery tests"),
+		mcp.WithNumber("exit_code", mcp.Description("Process exit code (default 1)")),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Simulate Crash",
+			ReadOnlyHint:    false,
+			DestructiveHint: true,
+			IdempotentHint:  false,
+		}),
+	)
+	addToolWithMiddleware(s, tool, handleSimulateCrashTool)
+}
+
+func handleSimulateCrashTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	code := 1
+	if raw, ok := request.Params.Arguments["exit_code"]; ok {
+		n, ok := raw.(float64)
+		if !ok {
+			return nil, invalidParamsf("exit_code must be a number")
+		}
+		code = int(n)
+	}
+
+	_ = os.Stdout.Sync()
+	os.Exit(code)
+	panic("unreachable")
+}
+
+// registerSimulateHangTool wires up simulate_hang: it accepts the request
+// and blocks forever without responding, while leaving the rest of the
+// session free to answer other concurrent requests (mcp-go dispatches
+// tools/call handlers independently, so one hung handler doesn't occupy any
+// shared lock this fixture holds). The handler only returns once its
+// context is cancelled, which happens on server shutdown or the underlying
+// connection closing — never as an ordinary response a client would see.
+func registerSimulateHangTool(s *server.MCPServer) {
+	tool := mcp.NewTool("simulate_hang",
+		mcp.WithDescription("Accept the request and never respond, for dead-connection detection tests"),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:        "Simulate Hang",
+			ReadOnlyHint: true,
+		}),
+	)
+	addToolWithMiddleware(s, tool, handleSimulateHangTool)
+}
+
+func handleSimulateHangTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// simulateCrashSubprocessEnv, when set, tells TestMain to run
+// handleSimulateCrashTool directly and exit instead of running the package's
+// tests. TestSimulateCrashTool_ExitsWithTheRequestedCode re-execs this same
+// test binary with it set, since os.Exit inside the handler would otherwise
+// kill the real `go test` process.
+const simulateCrashSubprocessEnv = "CALCULATOR_SIMULATE_CRASH_SUBPROCESS"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(simulateCrashSubprocessEnv) != "" {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "simulate_crash"
+		req.Params.Arguments = map[string]any{"exit_code": float64(3)}
+		handleSimulateCrashTool(context.Background(), req)
+		os.Exit(0) // unreachable if handleSimulateCrashTool behaves correctly
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestSimulateCrashTool_ExitsWithTheRequestedCode(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = append(os.Environ(), simulateCrashSubprocessEnv+"=1")
+
+	err := cmd.Run()
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		t.Fatalf("expected an *exec.ExitError, got %v (%T)", err, err)
+	}
+	if got := exitErr.ExitCode(); got != 3 {
+		t.Errorf("exit code = %d, want 3", got)
+	}
+}
+
+func TestHandlePanicTool_ServerStillAnswersAFollowUpCalculateCall(t *testing.T) {
+	recoverEnabled = true
+
+	wrapped := recoverMiddleware(handlePanicTool)
+	if _, err := wrapped(context.Background(), mcp.CallToolRequest{}); err == nil {
+		t.Fatal("expected panic_tool to come back as an error, not a panic")
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "calculate"
+	req.Params.Arguments = map[string]any{"operation": "add", "x": 1.0, "y": 1.0}
+	if _, err := handle_calculate_tool(context.Background(), req); err != nil {
+		t.Fatalf("calculate failed after panic_tool panicked: %v", err)
+	}
+}
+
+func TestHandleSimulateHangTool_LeavesConcurrentCallsUnaffected(t *testing.T) {
+	hangCtx, cancelHang := context.WithCancel(context.Background())
+	defer cancelHang()
+
+	hangDone := make(chan struct{})
+	go func() {
+		handleSimulateHangTool(hangCtx, mcp.CallToolRequest{})
+		close(hangDone)
+	}()
+
+	select {
+	case <-hangDone:
+		t.Fatal("simulate_hang returned before its context was cancelled")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "calculate"
+	req.Params.Arguments = map[string]any{"operation": "add", "x": 2.0, "y": 3.0}
+	if _, err := handle_calculate_tool(context.Background(), req); err != nil {
+		t.Fatalf("concurrent calculate call failed while simulate_hang was outstanding: %v", err)
+	}
+
+	cancelHang()
+	select {
+	case <-hangDone:
+	case <-time.After(time.Second):
+		t.Fatal("simulate_hang did not return after its context was cancelled")
+	}
+}
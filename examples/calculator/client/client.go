@@ -0,0 +1,145 @@
+// Package client provides a thin wrapper around mcp-go's client
+// implementations for orchestrators that need to call the calculator
+// fixture (or any other MCP server) from Go.
+package client
+
+import (
+	"context"
+	"fmt"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// transportClient is the subset of *mcpclient.Client's methods Client
+// actually calls. Defining it ourselves (rather than depending on the
+// concrete type everywhere) lets a backend other than mcp-go's own
+// transports — see NewInMemoryClient — stand in for it.
+type transportClient interface {
+	Initialize(ctx context.Context, request mcp.InitializeRequest) (*mcp.InitializeResult, error)
+	ListTools(ctx context.Context, request mcp.ListToolsRequest) (*mcp.ListToolsResult, error)
+	CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	ListResources(ctx context.Context, request mcp.ListResourcesRequest) (*mcp.ListResourcesResult, error)
+	ReadResource(ctx context.Context, request mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error)
+	ListPrompts(ctx context.Context, request mcp.ListPromptsRequest) (*mcp.ListPromptsResult, error)
+	GetPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error)
+	OnNotification(handler func(notification mcp.JSONRPCNotification))
+	Close() error
+}
+
+// Client wraps an mcp-go client transport with the initialize handshake
+// and capability tracking every caller needs.
+type Client struct {
+	transport    transportClient
+	capabilities mcp.ServerCapabilities
+	roots        rootsState
+	changes      changeHandlers
+}
+
+// NewStdioClient launches cmd as a subprocess and speaks MCP over its
+// stdin/stdout.
+func NewStdioClient(ctx context.Context, cmd string, args []string, opts ...ClientOption) (*Client, error) {
+	c, err := mcpclient.NewStdioMCPClient(cmd, nil, args...)
+	if err != nil {
+		return nil, fmt.Errorf("start stdio client: %w", err)
+	}
+	return newClient(ctx, c, opts...)
+}
+
+// NewSSEClient connects to an MCP server exposing the SSE transport at url.
+func NewSSEClient(ctx context.Context, url string, opts ...ClientOption) (*Client, error) {
+	c, err := mcpclient.NewSSEMCPClient(url)
+	if err != nil {
+		return nil, fmt.Errorf("dial SSE client: %w", err)
+	}
+	return newClient(ctx, c, opts...)
+}
+
+func newClient(ctx context.Context, transport transportClient, opts ...ClientOption) (*Client, error) {
+	var cfg clientConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	initReq := mcp.InitializeRequest{}
+	initReq.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initReq.Params.ClientInfo = mcp.Implementation{Name: "calculator-client", Version: "0.1.0"}
+	if len(cfg.roots) > 0 {
+		initReq.Params.Capabilities.Roots = &struct {
+			ListChanged bool `json:"listChanged,omitempty"`
+		}{ListChanged: true}
+	}
+
+	result, err := transport.Initialize(ctx, initReq)
+	if err != nil {
+		transport.Close()
+		return nil, fmt.Errorf("initialize: %w", err)
+	}
+
+	client := &Client{transport: transport, capabilities: result.Capabilities}
+	client.roots.roots = cfg.roots
+	client.roots.supported = len(cfg.roots) > 0
+	transport.OnNotification(client.dispatchNotification)
+	return client, nil
+}
+
+// ServerCapabilities returns the capabilities the server advertised during
+// initialize.
+func (c *Client) ServerCapabilities() mcp.ServerCapabilities {
+	return c.capabilities
+}
+
+// Close shuts the underlying transport down.
+func (c *Client) Close() error {
+	return c.transport.Close()
+}
+
+func (c *Client) ListTools(ctx context.Context) ([]mcp.Tool, error) {
+	result, err := c.transport.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return result.Tools, nil
+}
+
+// CallTool invokes name with args; context cancellation aborts the
+// in-flight request.
+func (c *Client) CallTool(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
+	req := mcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	return c.transport.CallTool(ctx, req)
+}
+
+func (c *Client) ListResources(ctx context.Context) ([]mcp.Resource, error) {
+	result, err := c.transport.ListResources(ctx, mcp.ListResourcesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return result.Resources, nil
+}
+
+func (c *Client) ReadResource(ctx context.Context, uri string) ([]mcp.ResourceContents, error) {
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = uri
+	result, err := c.transport.ReadResource(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return result.Contents, nil
+}
+
+func (c *Client) ListPrompts(ctx context.Context) ([]mcp.Prompt, error) {
+	result, err := c.transport.ListPrompts(ctx, mcp.ListPromptsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return result.Prompts, nil
+}
+
+func (c *Client) GetPrompt(ctx context.Context, name string, args map[string]string) (*mcp.GetPromptResult, error) {
+	req := mcp.GetPromptRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	return c.transport.GetPrompt(ctx, req)
+}
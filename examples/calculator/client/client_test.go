@@ -0,0 +1,17 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNewStdioClient_InvalidCommandErrors(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_, err := NewStdioClient(ctx, "definitely-not-a-real-binary-xyz", nil)
+	if err == nil {
+		t.Fatal("expected an error launching a nonexistent command")
+	}
+}
@@ -0,0 +1,27 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// NewInMemoryClient wires a Client directly to s over mcp-go's in-process
+// transport, with no subprocess and no network listener. It exists so
+// tests can initialize, list tools, and call tools against a
+// *server.MCPServer in a single `go test` process instead of spawning the
+// compiled binary and talking to it over stdio (NewStdioClient) or a real
+// SSE/HTTP listener.
+func NewInMemoryClient(ctx context.Context, s *server.MCPServer, opts ...ClientOption) (*Client, error) {
+	c, err := mcpclient.NewInProcessClient(s)
+	if err != nil {
+		return nil, fmt.Errorf("start in-memory client: %w", err)
+	}
+	if err := c.Start(ctx); err != nil {
+		return nil, fmt.Errorf("start in-memory client: %w", err)
+	}
+
+	return newClient(ctx, c, opts...)
+}
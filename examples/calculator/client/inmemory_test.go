@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func echoServer() *server.MCPServer {
+	s := server.NewMCPServer("echo-fixture", "0.0.0")
+	tool := mcp.NewTool("ping", mcp.WithDescription("Always returns pong"))
+	s.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("pong"), nil
+	})
+	return s
+}
+
+func TestNewInMemoryClient_InitializesAndListsTools(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := NewInMemoryClient(ctx, echoServer())
+	if err != nil {
+		t.Fatalf("NewInMemoryClient: %v", err)
+	}
+	defer c.Close()
+
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	if len(tools) != 1 || tools[0].Name != "ping" {
+		t.Fatalf("tools = %v, want exactly [ping]", tools)
+	}
+}
+
+func TestNewInMemoryClient_CallTool(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := NewInMemoryClient(ctx, echoServer())
+	if err != nil {
+		t.Fatalf("NewInMemoryClient: %v", err)
+	}
+	defer c.Close()
+
+	res, err := c.CallTool(ctx, "ping", nil)
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	text, ok := res.Content[0].(mcp.TextContent)
+	if !ok || text.Text != "pong" {
+		t.Errorf("content = %v, want \"pong\"", res.Content)
+	}
+}
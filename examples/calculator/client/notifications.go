@@ -0,0 +1,83 @@
+package client
+
+import (
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// changeHandlers holds the callbacks OnToolsChanged, OnResourcesChanged,
+// and OnPromptsChanged register. Client installs a single OnNotification
+// handler (see newClient) that fans a notifications/*/list_changed
+// message out to whichever of these lists applies.
+type changeHandlers struct {
+	mu        sync.Mutex
+	tools     []func()
+	resources []func()
+	prompts   []func()
+}
+
+// OnToolsChanged registers fn to run, on its own goroutine, whenever the
+// server sends notifications/tools/list_changed — the signal that a
+// subsequent ListTools call would return a different result than before.
+// Multiple registered callbacks all run; there's no way to unregister one,
+// the same one-way registration shape WithRoots commits to for roots.
+//
+// There's no client-side "tools changed" capability to advertise during
+// initialize: MCP's ClientCapabilities only covers roots and sampling (see
+// initReq.Params.Capabilities.Roots in client.go) — listChanged for tools,
+// resources, and prompts is exclusively a server capability
+// (server.WithToolCapabilities(true) and friends in main.go), and a server
+// that advertises it sends the notification unconditionally rather than
+// gating it on anything the client declared. So OnToolsChanged only needs
+// to subscribe; there's nothing this wrapper could add to the handshake.
+func (c *Client) OnToolsChanged(fn func()) {
+	c.changes.mu.Lock()
+	defer c.changes.mu.Unlock()
+	c.changes.tools = append(c.changes.tools, fn)
+}
+
+// OnResourcesChanged registers fn to run, on its own goroutine, whenever
+// the server sends notifications/resources/list_changed. See
+// OnToolsChanged for the registration and capability-advertising caveats,
+// which apply identically here.
+func (c *Client) OnResourcesChanged(fn func()) {
+	c.changes.mu.Lock()
+	defer c.changes.mu.Unlock()
+	c.changes.resources = append(c.changes.resources, fn)
+}
+
+// OnPromptsChanged registers fn to run, on its own goroutine, whenever the
+// server sends notifications/prompts/list_changed. See OnToolsChanged for
+// the registration and capability-advertising caveats, which apply
+// identically here.
+func (c *Client) OnPromptsChanged(fn func()) {
+	c.changes.mu.Lock()
+	defer c.changes.mu.Unlock()
+	c.changes.prompts = append(c.changes.prompts, fn)
+}
+
+// dispatchNotification is registered with the transport via OnNotification
+// in newClient. It runs every callback registered for notification's
+// method, each on its own goroutine, so a callback that calls back into
+// the client (e.g. ListTools from inside an OnToolsChanged handler) never
+// deadlocks against whatever goroutine the transport delivered the
+// notification on.
+func (c *Client) dispatchNotification(notification mcp.JSONRPCNotification) {
+	var handlers []func()
+
+	c.changes.mu.Lock()
+	switch notification.Method {
+	case "notifications/tools/list_changed":
+		handlers = c.changes.tools
+	case "notifications/resources/list_changed":
+		handlers = c.changes.resources
+	case "notifications/prompts/list_changed":
+		handlers = c.changes.prompts
+	}
+	c.changes.mu.Unlock()
+
+	for _, fn := range handlers {
+		go fn()
+	}
+}
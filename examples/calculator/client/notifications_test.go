@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func listChangedServer() *server.MCPServer {
+	s := server.NewMCPServer("list-changed-fixture", "0.0.0", server.WithToolCapabilities(true))
+	tool := mcp.NewTool("ping", mcp.WithDescription("Always returns pong"))
+	s.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("pong"), nil
+	})
+	return s
+}
+
+// TestClient_OnToolsChanged_FiresWhenTheServerAddsATool registers a second
+// tool after the handshake — s.AddTool emits notifications/tools/list_changed
+// on its own, per dynamic_tools.go's doc comment in the calculator fixture —
+// and asserts the registered callback observes it.
+func TestClient_OnToolsChanged_FiresWhenTheServerAddsATool(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s := listChangedServer()
+	c, err := NewInMemoryClient(ctx, s)
+	if err != nil {
+		t.Fatalf("NewInMemoryClient: %v", err)
+	}
+	defer c.Close()
+
+	fired := make(chan struct{}, 1)
+	c.OnToolsChanged(func() { fired <- struct{}{} })
+
+	extra := mcp.NewTool("pong", mcp.WithDescription("Always returns ping"))
+	s.AddTool(extra, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ping"), nil
+	})
+
+	select {
+	case <-fired:
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnToolsChanged callback did not fire after the server added a tool")
+	}
+}
+
+// TestClient_OnToolsChanged_CallbackCanCallListToolsWithoutDeadlocking
+// exercises the one invariant the request cares about most: the callback
+// runs on its own goroutine, so it can safely call back into the client
+// (here, ListTools) instead of deadlocking against the goroutine that
+// delivered the notification.
+func TestClient_OnToolsChanged_CallbackCanCallListToolsWithoutDeadlocking(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s := listChangedServer()
+	c, err := NewInMemoryClient(ctx, s)
+	if err != nil {
+		t.Fatalf("NewInMemoryClient: %v", err)
+	}
+	defer c.Close()
+
+	result := make(chan []mcp.Tool, 1)
+	c.OnToolsChanged(func() {
+		tools, err := c.ListTools(ctx)
+		if err != nil {
+			t.Errorf("ListTools from within OnToolsChanged: %v", err)
+			return
+		}
+		result <- tools
+	})
+
+	extra := mcp.NewTool("pong", mcp.WithDescription("Always returns ping"))
+	s.AddTool(extra, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ping"), nil
+	})
+
+	select {
+	case tools := <-result:
+		if len(tools) != 2 {
+			t.Errorf("ListTools = %v, want 2 tools", tools)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnToolsChanged callback did not complete its ListTools call")
+	}
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ClientOption configures a Client before it sends its initialize request,
+// the same "functional option applied before the handshake" shape
+// NewStdioClient and NewSSEClient already commit to at construction.
+type ClientOption func(*clientConfig)
+
+type clientConfig struct {
+	roots []mcp.Root
+}
+
+// WithRoots sets the client's filesystem roots. Configuring any roots is
+// what makes newClient advertise the roots capability during initialize —
+// a client with no roots configured doesn't claim to support the
+// capability at all. The client does not answer a server's roots/list
+// request: transportClient has no way to register a handler for
+// server-to-client requests, so the configured list is only readable
+// locally through Roots.
+func WithRoots(roots ...mcp.Root) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.roots = append(cfg.roots, roots...)
+	}
+}
+
+// rootsState is the portion of Client's state this file owns: the current
+// root list and whether the capability was ever advertised.
+type rootsState struct {
+	mu        sync.Mutex
+	roots     []mcp.Root
+	supported bool
+}
+
+// SetRoots replaces the client's local root list. It returns an error for
+// a client that did not advertise the roots capability during initialize.
+//
+// SetRoots does not send notifications/roots/list_changed to the server:
+// none of the methods transportClient declares (Initialize, ListTools,
+// CallTool, ...) sends a client-to-server notification.
+func (c *Client) SetRoots(ctx context.Context, roots []mcp.Root) error {
+	if !c.roots.supported {
+		return fmt.Errorf("client was constructed without WithRoots; the roots capability was never advertised")
+	}
+
+	c.roots.mu.Lock()
+	c.roots.roots = roots
+	c.roots.mu.Unlock()
+
+	return nil
+}
+
+// Roots returns a copy of the client's current root list.
+func (c *Client) Roots() []mcp.Root {
+	c.roots.mu.Lock()
+	defer c.roots.mu.Unlock()
+
+	out := make([]mcp.Root, len(c.roots.roots))
+	copy(out, c.roots.roots)
+	return out
+}
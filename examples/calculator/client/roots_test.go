@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestNewInMemoryClient_WithRootsExposesTheConfiguredList(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := NewInMemoryClient(ctx, echoServer(), WithRoots(mcp.Root{URI: "file:///project", Name: "project"}))
+	if err != nil {
+		t.Fatalf("NewInMemoryClient: %v", err)
+	}
+	defer c.Close()
+
+	roots := c.Roots()
+	if len(roots) != 1 || roots[0].URI != "file:///project" {
+		t.Fatalf("Roots() = %v, want a single file:///project root", roots)
+	}
+}
+
+func TestNewInMemoryClient_WithoutWithRootsHasNoRoots(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := NewInMemoryClient(ctx, echoServer())
+	if err != nil {
+		t.Fatalf("NewInMemoryClient: %v", err)
+	}
+	defer c.Close()
+
+	if roots := c.Roots(); len(roots) != 0 {
+		t.Errorf("Roots() = %v, want none without WithRoots", roots)
+	}
+}
+
+func TestClient_SetRoots_RejectedWithoutWithRoots(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := NewInMemoryClient(ctx, echoServer())
+	if err != nil {
+		t.Fatalf("NewInMemoryClient: %v", err)
+	}
+	defer c.Close()
+
+	if err := c.SetRoots(ctx, []mcp.Root{{URI: "file:///late", Name: "late"}}); err == nil {
+		t.Fatal("expected SetRoots to fail for a client that never advertised the roots capability")
+	}
+}
+
+func TestClient_SetRoots_UpdatesTheLocalRootList(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := NewInMemoryClient(ctx, echoServer(), WithRoots(mcp.Root{URI: "file:///project", Name: "project"}))
+	if err != nil {
+		t.Fatalf("NewInMemoryClient: %v", err)
+	}
+	defer c.Close()
+
+	updated := []mcp.Root{{URI: "file:///other", Name: "other"}}
+	if err := c.SetRoots(ctx, updated); err != nil {
+		t.Fatalf("SetRoots: %v", err)
+	}
+
+	roots := c.Roots()
+	if len(roots) != 1 || roots[0].URI != "file:///other" {
+		t.Fatalf("Roots() = %v, want [file:///other]", roots)
+	}
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ToolCallError is returned by CallToolTyped when the tool answered with
+// isError: true. Text is the error message the server sent back (see the
+// calculator fixture's toolResultErrorf for the server-side counterpart
+// this is meant to surface), rather than a generic "tool call failed".
+type ToolCallError struct {
+	Tool string
+	Text string
+}
+
+func (e *ToolCallError) Error() string {
+	return fmt.Sprintf("tool %q returned an error: %s", e.Tool, e.Text)
+}
+
+// CallToolTyped JSON-encodes in as name's arguments, calls it through c,
+// and decodes the result into Out from the tool's last TextContent block
+// parsed as JSON — the block handlers following the calculator fixture's
+// own convention append alongside a human-readable result (see
+// handle_calculate_tool in main.go), or the tool's only block when it
+// doesn't. It returns *ToolCallError, not a bare error, when the result
+// has isError: true, so a caller can type-switch on it to recover the
+// server's error text instead of just a failure.
+func CallToolTyped[In, Out any](ctx context.Context, c *Client, name string, in In) (Out, error) {
+	var zero Out
+
+	argsJSON, err := json.Marshal(in)
+	if err != nil {
+		return zero, fmt.Errorf("encoding arguments for %q: %w", name, err)
+	}
+	var args map[string]any
+	if err := json.Unmarshal(argsJSON, &args); err != nil {
+		return zero, fmt.Errorf("arguments for %q must encode as a JSON object: %w", name, err)
+	}
+
+	result, err := c.CallTool(ctx, name, args)
+	if err != nil {
+		return zero, err
+	}
+
+	if result.IsError {
+		return zero, &ToolCallError{Tool: name, Text: toolResultText(result)}
+	}
+
+	text := lastTextContent(result)
+	if text == "" {
+		return zero, fmt.Errorf("tool %q returned no text content to decode", name)
+	}
+	var out Out
+	if err := json.Unmarshal([]byte(text), &out); err != nil {
+		return zero, fmt.Errorf("decoding tool %q's content as JSON: %w", name, err)
+	}
+	return out, nil
+}
+
+// toolResultText returns the text of result's first TextContent block, or
+// "" if it has none.
+func toolResultText(result *mcp.CallToolResult) string {
+	for _, c := range result.Content {
+		if text, ok := c.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
+
+// lastTextContent returns the text of result's last TextContent block, or
+// "" if it has none.
+func lastTextContent(result *mcp.CallToolResult) string {
+	for i := len(result.Content) - 1; i >= 0; i-- {
+		if text, ok := result.Content[i].(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
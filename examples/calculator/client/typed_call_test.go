@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type sumArgs struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type sumResult struct {
+	Sum int `json:"sum"`
+}
+
+// sumArgsFromRequest decodes x and y straight out of request's arguments.
+// mcp.CallToolRequest has no BindArguments method in this mcp-go version
+// (the calculator package's own bindArguments helper in bindargs.go exists
+// specifically because the library lacks one, per that file's own doc
+// comment) — and that helper is unexported in package main, so this test
+// fixture's two-field case decodes directly instead of duplicating it.
+func sumArgsFromRequest(request mcp.CallToolRequest) sumArgs {
+	x, _ := request.Params.Arguments["x"].(float64)
+	y, _ := request.Params.Arguments["y"].(float64)
+	return sumArgs{X: int(x), Y: int(y)}
+}
+
+// sumServer offers "sum" (a human-readable block followed by a structured
+// JSON block, the calculator fixture's own convention) and "sum-as-text"
+// (the same answer, but only as a single TextContent JSON blob) so
+// CallToolTyped's two decode paths can each be exercised directly, plus
+// "sum-fails" to cover the isError: true path.
+func sumServer() *server.MCPServer {
+	s := server.NewMCPServer("sum-fixture", "0.0.0")
+
+	s.AddTool(mcp.NewTool("sum", mcp.WithDescription("Adds two numbers, returning structured content")),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args := sumArgsFromRequest(request)
+			encoded, err := json.Marshal(sumResult{Sum: args.X + args.Y})
+			if err != nil {
+				return nil, err
+			}
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{Type: "text", Text: "done"},
+					mcp.TextContent{Type: "text", Text: string(encoded)},
+				},
+			}, nil
+		})
+
+	s.AddTool(mcp.NewTool("sum-as-text", mcp.WithDescription("Adds two numbers, returning only a JSON text block")),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			args := sumArgsFromRequest(request)
+			return mcp.NewToolResultText(`{"sum":` + strconv.Itoa(args.X+args.Y) + `}`), nil
+		})
+
+	s.AddTool(mcp.NewTool("sum-fails", mcp.WithDescription("Always returns isError: true")),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultError("sum-fails: refusing to add anything"), nil
+		})
+
+	return s
+}
+
+func TestCallToolTyped_DecodesStructuredContent(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := NewInMemoryClient(ctx, sumServer())
+	if err != nil {
+		t.Fatalf("NewInMemoryClient: %v", err)
+	}
+	defer c.Close()
+
+	out, err := CallToolTyped[sumArgs, sumResult](ctx, c, "sum", sumArgs{X: 3, Y: 4})
+	if err != nil {
+		t.Fatalf("CallToolTyped: %v", err)
+	}
+	if out.Sum != 7 {
+		t.Errorf("Sum = %d, want 7", out.Sum)
+	}
+}
+
+func TestCallToolTyped_FallsBackToTextContentAsJSON(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := NewInMemoryClient(ctx, sumServer())
+	if err != nil {
+		t.Fatalf("NewInMemoryClient: %v", err)
+	}
+	defer c.Close()
+
+	out, err := CallToolTyped[sumArgs, sumResult](ctx, c, "sum-as-text", sumArgs{X: 10, Y: 5})
+	if err != nil {
+		t.Fatalf("CallToolTyped: %v", err)
+	}
+	if out.Sum != 15 {
+		t.Errorf("Sum = %d, want 15", out.Sum)
+	}
+}
+
+func TestCallToolTyped_ReturnsToolCallErrorWhenIsError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := NewInMemoryClient(ctx, sumServer())
+	if err != nil {
+		t.Fatalf("NewInMemoryClient: %v", err)
+	}
+	defer c.Close()
+
+	_, err = CallToolTyped[sumArgs, sumResult](ctx, c, "sum-fails", sumArgs{X: 1, Y: 1})
+	if err == nil {
+		t.Fatal("expected an error from sum-fails")
+	}
+	tce, ok := err.(*ToolCallError)
+	if !ok {
+		t.Fatalf("err = %T, want *ToolCallError", err)
+	}
+	if tce.Text != "sum-fails: refusing to add anything" {
+		t.Errorf("Text = %q, want the tool's own error message", tce.Text)
+	}
+}
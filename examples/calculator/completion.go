@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// calculateOperationValues is the enum calculate's operation argument
+// advertises; completion/complete filters this list by prefix instead of
+// duplicating it.
+var calculateOperationValues = []string{"add", "subtract", "multiply", "divide"}
+
+// maxCompletionValues caps how many candidates a single completion/complete
+// response returns, per the spec's 100-item guidance; completions beyond
+// the cap are dropped and HasMore is set instead.
+const maxCompletionValues = 100
+
+// handleCompletion answers completion/complete for the calculate tool's
+// operation argument, the explain_calculation prompt's operation argument,
+// and the calc://history/{index} resource template's index variable. Any
+// other ref/argument combination returns an empty completion list rather
+// than an error, matching the spec's guidance that completions are always
+// best-effort.
+func handleCompletion(ctx context.Context, request mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+	var refName, refURI string
+	switch ref := request.Params.Ref.(type) {
+	case mcp.PromptReference:
+		refName = ref.Name
+	case mcp.ResourceReference:
+		refURI = ref.URI
+	}
+	arg := request.Params.Argument
+
+	switch {
+	case (refName == "calculate" || refName == "explain_calculation") && arg.Name == "operation":
+		return completeFromValues(calculateOperationValues, arg.Value, caseInsensitivePrefix), nil
+	case refURI == historyTemplate && arg.Name == "index":
+		return completeHistoryIndex(arg.Value), nil
+	default:
+		return emptyCompletion(), nil
+	}
+}
+
+func caseInsensitivePrefix(v, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(v), strings.ToLower(prefix))
+}
+
+// completeFromValues filters values by matches(v, prefix), caps the result
+// at maxCompletionValues, and reports whether anything was dropped.
+func completeFromValues(values []string, prefix string, matches func(v, prefix string) bool) *mcp.CompleteResult {
+	var filtered []string
+	for _, v := range values {
+		if matches(v, prefix) {
+			filtered = append(filtered, v)
+		}
+	}
+
+	hasMore := len(filtered) > maxCompletionValues
+	if hasMore {
+		filtered = filtered[:maxCompletionValues]
+	}
+
+	result := &mcp.CompleteResult{}
+	result.Completion.Values = filtered
+	result.Completion.Total = len(filtered)
+	result.Completion.HasMore = hasMore
+	return result
+}
+
+// completeHistoryIndex offers the most recent recorded history indices
+// (highest first, since that's what a caller exploring calc://history/N is
+// almost always after) filtered by prefix, capped at maxCompletionValues.
+func completeHistoryIndex(prefix string) *mcp.CompleteResult {
+	n := historyLen()
+	var values []string
+	for i := n; i >= 1; i-- {
+		values = append(values, strconv.Itoa(i))
+	}
+	return completeFromValues(values, prefix, strings.HasPrefix)
+}
+
+func emptyCompletion() *mcp.CompleteResult {
+	result := &mcp.CompleteResult{}
+	result.Completion.Values = []string{}
+	return result
+}
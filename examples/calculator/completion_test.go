@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func completeCalculateOperation(t *testing.T, value string) []string {
+	t.Helper()
+
+	req := mcp.CompleteRequest{}
+	req.Params.Ref = mcp.PromptReference{Name: "calculate"}
+	req.Params.Argument.Name = "operation"
+	req.Params.Argument.Value = value
+
+	res, err := handleCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	values := append([]string(nil), res.Completion.Values...)
+	sort.Strings(values)
+	return values
+}
+
+func TestHandleCompletion_EmptyPrefixReturnsEverything(t *testing.T) {
+	got := completeCalculateOperation(t, "")
+	want := []string{"add", "divide", "multiply", "subtract"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestHandleCompletion_PartialPrefix(t *testing.T) {
+	got := completeCalculateOperation(t, "d")
+	if len(got) != 1 || got[0] != "divide" {
+		t.Errorf("got %v, want [divide]", got)
+	}
+}
+
+func TestHandleCompletion_CaseInsensitive(t *testing.T) {
+	got := completeCalculateOperation(t, "D")
+	if len(got) != 1 || got[0] != "divide" {
+		t.Errorf("got %v, want [divide]", got)
+	}
+}
+
+func TestHandleCompletion_UnknownArgumentReturnsEmptyNotError(t *testing.T) {
+	req := mcp.CompleteRequest{}
+	req.Params.Ref = mcp.PromptReference{Name: "calculate"}
+	req.Params.Argument.Name = "not_a_real_argument"
+
+	res, err := handleCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Completion.Values) != 0 {
+		t.Errorf("Values = %v, want empty", res.Completion.Values)
+	}
+	if res.Completion.Total != 0 || res.Completion.HasMore {
+		t.Errorf("Total/HasMore = %d/%v, want 0/false", res.Completion.Total, res.Completion.HasMore)
+	}
+}
+
+func TestHandleCompletion_PromptArgumentCompletesTheSameEnum(t *testing.T) {
+	req := mcp.CompleteRequest{}
+	req.Params.Ref = mcp.PromptReference{Name: "explain_calculation"}
+	req.Params.Argument.Name = "operation"
+	req.Params.Argument.Value = "mul"
+
+	res, err := handleCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Completion.Values) != 1 || res.Completion.Values[0] != "multiply" {
+		t.Errorf("Values = %v, want [multiply]", res.Completion.Values)
+	}
+}
+
+func TestHandleCompletion_HistoryIndexCompletesRecordedEntries(t *testing.T) {
+	before := historyLen()
+	recordCalculation("add", 1, 1, 2, time.Now())
+	after := historyLen()
+
+	req := mcp.CompleteRequest{}
+	req.Params.Ref = mcp.ResourceReference{URI: historyTemplate}
+	req.Params.Argument.Name = "index"
+
+	res, err := handleCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Completion.Total != after {
+		t.Errorf("Total = %d, want %d", res.Completion.Total, after)
+	}
+	if res.Completion.Values[0] != strconv.Itoa(after) {
+		t.Errorf("Values[0] = %q, want the most recent index %d", res.Completion.Values[0], after)
+	}
+	_ = before
+}
+
+func TestHandleCompletion_HistoryIndexCapsAtMaxCompletionValues(t *testing.T) {
+	for i := 0; i < maxCompletionValues+5; i++ {
+		recordCalculation("add", 1, 1, 2, time.Now())
+	}
+
+	req := mcp.CompleteRequest{}
+	req.Params.Ref = mcp.ResourceReference{URI: historyTemplate}
+	req.Params.Argument.Name = "index"
+
+	res, err := handleCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Completion.Values) != maxCompletionValues {
+		t.Errorf("len(Values) = %d, want %d", len(res.Completion.Values), maxCompletionValues)
+	}
+	if !res.Completion.HasMore {
+		t.Error("HasMore = false, want true once candidates exceed the cap")
+	}
+}
+
+func TestHandleCompletion_UnknownToolReturnsEmptyNotError(t *testing.T) {
+	req := mcp.CompleteRequest{}
+	req.Params.Ref = mcp.PromptReference{Name: "not_a_real_tool"}
+	req.Params.Argument.Name = "operation"
+
+	res, err := handleCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Completion.Values) != 0 {
+		t.Errorf("Values = %v, want empty", res.Completion.Values)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+)
+
+// compressionThresholdBytes is the minimum buffered response size
+// withCompression will gzip-encode; anything smaller is sent as-is, since
+// gzip's own overhead can make a tiny body bigger, not smaller. Zero (the
+// default) disables compression entirely: main wires this to
+// -compression-threshold-bytes. It only ever applies to the sse/http/both
+// transports (see transport.go) — stdio has no HTTP response to encode,
+// so it's unaffected by definition, not by an extra check here.
+var compressionThresholdBytes = 0
+
+// withCompression wraps next for the sse/http/both transports' POST
+// endpoints (the SSE transport's message endpoint), gzip-encoding
+// the response body when the client's Accept-Encoding says it can
+// decode gzip and the body is at least compressionThresholdBytes.
+//
+// It only buffers POST responses, never GET: the SSE event stream is a
+// GET request held open for the connection's life, and buffering it
+// until "done" would mean never flushing a single event — there is no
+// "done" to flush at. Per-event SSE compression (gzip one event at a
+// time, only if it alone exceeds the threshold) would need to sit inside
+// mcp-go's own SSEServer write loop, which owns that http.ResponseWriter
+// end to end; there's no confirmed hook to intercept an individual event
+// write from out here, the same gap sse_keepalive.go's doc comment
+// describes for injecting a raw keep-alive comment line into that same
+// stream. So GET passes through uncompressed, and only the POST
+// request/response bodies this fixture can fully buffer get gzipped.
+func withCompression(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if compressionThresholdBytes <= 0 || r.Method != http.MethodPost || !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &compressingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		rec.flush()
+	})
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, enc := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		if strings.TrimSpace(enc) == "gzip" {
+			return true
+		}
+	}
+	return false
+}
+
+// compressingResponseWriter buffers a POST response so its final size can
+// be compared against compressionThresholdBytes before anything reaches
+// the real client, the same buffer-then-decide approach strict.go's
+// initializeResponseRewriter uses to rewrite an initialize response.
+type compressingResponseWriter struct {
+	http.ResponseWriter
+	buf        bytes.Buffer
+	statusCode int
+}
+
+func (w *compressingResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+}
+
+func (w *compressingResponseWriter) Write(b []byte) (int, error) {
+	return w.buf.Write(b)
+}
+
+func (w *compressingResponseWriter) flush() {
+	body := w.buf.Bytes()
+	// Whatever next.ServeHTTP set this to was based on the uncompressed
+	// body: possibly still right for the buffered-uncompressed case,
+	// definitely wrong once gzipped. Drop it in both cases and let the
+	// transport chunk or recompute it rather than risk a stale value.
+	w.ResponseWriter.Header().Del("Content-Length")
+
+	if len(body) < compressionThresholdBytes {
+		w.ResponseWriter.WriteHeader(w.statusCode)
+		_, _ = w.ResponseWriter.Write(body)
+		return
+	}
+
+	w.ResponseWriter.Header().Set("Content-Encoding", "gzip")
+	w.ResponseWriter.Header().Set("Vary", "Accept-Encoding")
+	w.ResponseWriter.WriteHeader(w.statusCode)
+
+	gw := gzip.NewWriter(w.ResponseWriter)
+	_, _ = gw.Write(body)
+	_ = gw.Close()
+}
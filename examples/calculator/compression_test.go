@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func withTestCompressionThreshold(t *testing.T, threshold int) {
+	t.Helper()
+	orig := compressionThresholdBytes
+	compressionThresholdBytes = threshold
+	t.Cleanup(func() { compressionThresholdBytes = orig })
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	cases := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"deflate", false},
+		{"gzip, deflate", true},
+		{"deflate, gzip", true},
+		{"br", false},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest(http.MethodGet, "http://example.invalid", nil)
+		req.Header.Set("Accept-Encoding", c.header)
+		if got := acceptsGzip(req); got != c.want {
+			t.Errorf("acceptsGzip(%q) = %v, want %v", c.header, got, c.want)
+		}
+	}
+}
+
+func TestWithCompression_BelowThresholdIsNotEncoded(t *testing.T) {
+	withTestCompressionThreshold(t, 1<<20)
+
+	handler := withCompression(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("small"))
+	}))
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL, nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if enc := resp.Header.Get("Content-Encoding"); enc != "" {
+		t.Errorf("Content-Encoding = %q, want empty below threshold", enc)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "small" {
+		t.Errorf("body = %q, want %q", body, "small")
+	}
+}
+
+func TestWithCompression_DisabledWhenThresholdIsZero(t *testing.T) {
+	withTestCompressionThreshold(t, 0)
+
+	handler := withCompression(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("anything"))
+	}))
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL, nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if enc := resp.Header.Get("Content-Encoding"); enc != "" {
+		t.Errorf("Content-Encoding = %q, want empty with compression disabled", enc)
+	}
+}
+
+func TestWithCompression_GETIsNeverBuffered(t *testing.T) {
+	withTestCompressionThreshold(t, 1)
+
+	handler := withCompression(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("streamed"))
+	}))
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if enc := resp.Header.Get("Content-Encoding"); enc != "" {
+		t.Errorf("Content-Encoding = %q, want GET to pass through uncompressed", enc)
+	}
+}
+
+func TestWithCompression_AboveThresholdWithAcceptEncodingIsGzipped(t *testing.T) {
+	withTestCompressionThreshold(t, 10)
+
+	large := make([]byte, 4096)
+	for i := range large {
+		large[i] = byte('a' + i%26)
+	}
+
+	handler := withCompression(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(large)
+	}))
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL, nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if enc := resp.Header.Get("Content-Encoding"); enc != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", enc)
+	}
+	gr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	defer gr.Close()
+	got, err := io.ReadAll(gr)
+	if err != nil {
+		t.Fatalf("reading gzip body: %v", err)
+	}
+	if string(got) != string(large) {
+		t.Error("decompressed body did not round-trip")
+	}
+}
+
+func TestWithCompression_WithoutAcceptEncodingIsNotEncoded(t *testing.T) {
+	withTestCompressionThreshold(t, 1)
+
+	handler := withCompression(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("no accept-encoding here"))
+	}))
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if enc := resp.Header.Get("Content-Encoding"); enc != "" {
+		t.Errorf("Content-Encoding = %q, want empty without Accept-Encoding: gzip", enc)
+	}
+}
+
+// TestWithCompression_LargeToolsListRoundTripsGzipped is skipped: mcp-go
+// v0.23.1 has no Streamable HTTP server at all (server.NewStreamableHTTPServer
+// and server.WithEndpointPath don't exist in this version — it ships SSE
+// only, see serveHTTP's doc comment in transport.go).
+func TestWithCompression_LargeToolsListRoundTripsGzipped(t *testing.T) {
+	t.Skip("mcp-go v0.23.1 has no Streamable HTTP server; see serveHTTP's doc comment in transport.go")
+}
+
+func jsonBody(t *testing.T, body map[string]any) io.Reader {
+	t.Helper()
+	raw, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("marshal request: %v", err)
+	}
+	return bytes.NewReader(raw)
+}
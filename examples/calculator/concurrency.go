@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxConcurrentToolCalls caps how many tools/call invocations run at
+// once, process-wide rather than per-session (unlike sessionRateLimit):
+// one session flooding the server exhausts the same budget every other
+// session draws from. 0 (the default) disables the limiter entirely.
+// main wires this to --max-concurrent and calls ensureConcurrencyLimiter
+// once, before serve starts.
+var maxConcurrentToolCalls = 0
+
+// concurrencyQueueSize is how many additional calls may wait for a free
+// slot once --max-concurrent is saturated, instead of getting
+// serverBusyCode back immediately. 0 (the default) means no queueing.
+// main wires this to --queue-size.
+var concurrencyQueueSize = 0
+
+// serverBusyCode is the JSON-RPC error code a refused call gets back,
+// whether it's refused outright (no -queue-size) or after the queue
+// itself is full.
+const serverBusyCode = -32008
+
+// serverBusyRetryAfter is the fixed hint newConcurrencyMiddleware puts in
+// a busy error's data — not a measured estimate (there's no meaningful
+// one to compute for "the limiter is saturated right now"), just enough
+// for a client backoff loop to have a number to start from.
+const serverBusyRetryAfter = 250 * time.Millisecond
+
+// concurrencySlots is the process-wide semaphore newConcurrencyMiddleware
+// acquires from, sized by ensureConcurrencyLimiter. nil means the
+// limiter is disabled (maxConcurrentToolCalls <= 0).
+var concurrencySlots chan struct{}
+
+// concurrencyQueued counts callers currently waiting for a slot (not yet
+// holding one), so the queue itself can be bounded at concurrencyQueueSize
+// instead of growing without limit.
+var concurrencyQueued atomic.Int64
+
+// ensureConcurrencyLimiter (re)builds concurrencySlots from
+// maxConcurrentToolCalls. Call it once after main wires the flag (or
+// directly in a test, the same way openRecordFile is called directly
+// rather than through main's flag wiring) and before any call reaches
+// newConcurrencyMiddleware.
+func ensureConcurrencyLimiter() {
+	if maxConcurrentToolCalls <= 0 {
+		concurrencySlots = nil
+		return
+	}
+	slots := make(chan struct{}, maxConcurrentToolCalls)
+	for i := 0; i < maxConcurrentToolCalls; i++ {
+		slots <- struct{}{}
+	}
+	concurrencySlots = slots
+	concurrencyQueued.Store(0)
+}
+
+// tryEnterConcurrencyQueue reserves one of concurrencyQueueSize queue
+// spots, or reports false if they're all taken.
+func tryEnterConcurrencyQueue() bool {
+	for {
+		cur := concurrencyQueued.Load()
+		if cur >= int64(concurrencyQueueSize) {
+			return false
+		}
+		if concurrencyQueued.CompareAndSwap(cur, cur+1) {
+			return true
+		}
+	}
+}
+
+// newConcurrencyMiddleware enforces maxConcurrentToolCalls: a call that
+// finds a slot free takes it immediately; one that doesn't either waits
+// in a bounded FIFO (concurrencyQueueSize > 0, first-come-first-served
+// since concurrencySlots is a channel and Go delivers to the oldest
+// blocked receiver) or is refused right away with serverBusyError.
+// Holding a slot happens with a plain defer around next, so a panicking
+// or cancelled handler still releases it — the defer runs during the
+// panic's unwind before recoverMiddleware (registered outside this one,
+// closer to the transport) ever gets to recover it, and a handler that
+// returns after ctx is cancelled still reaches the defer the same as any
+// other return.
+func newConcurrencyMiddleware() toolMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if concurrencySlots == nil {
+				return next(ctx, request)
+			}
+
+			select {
+			case <-concurrencySlots:
+				defer func() { concurrencySlots <- struct{}{} }()
+				return next(ctx, request)
+			default:
+			}
+
+			if concurrencyQueueSize <= 0 || !tryEnterConcurrencyQueue() {
+				return nil, serverBusyError()
+			}
+			defer concurrencyQueued.Add(-1)
+
+			select {
+			case <-concurrencySlots:
+				defer func() { concurrencySlots <- struct{}{} }()
+				return next(ctx, request)
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+}
+
+func serverBusyError() *protocolError {
+	return &protocolError{
+		code:    serverBusyCode,
+		message: fmt.Sprintf("server busy, retry after %s", serverBusyRetryAfter),
+		data:    map[string]any{"retry_after_ms": serverBusyRetryAfter.Milliseconds()},
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func withTestConcurrencyLimit(t *testing.T, maxConcurrent, queueSize int) {
+	t.Helper()
+	origMax, origQueue := maxConcurrentToolCalls, concurrencyQueueSize
+	maxConcurrentToolCalls, concurrencyQueueSize = maxConcurrent, queueSize
+	ensureConcurrencyLimiter()
+	t.Cleanup(func() {
+		maxConcurrentToolCalls, concurrencyQueueSize = origMax, origQueue
+		ensureConcurrencyLimiter()
+	})
+}
+
+// blockingHandler returns a handler that signals started, then waits for
+// release before returning, so a test can hold a slot open deterministically
+// instead of racing a real tool's own duration.
+func blockingHandler(started chan struct{}, release <-chan struct{}) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		started <- struct{}{}
+		<-release
+		return mcp.NewToolResultText("done"), nil
+	}
+}
+
+func TestNewConcurrencyMiddleware_RefusesBeyondMaxConcurrentWithoutQueue(t *testing.T) {
+	withTestConcurrencyLimit(t, 2, 0)
+
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	handler := newConcurrencyMiddleware()(blockingHandler(started, release))
+
+	done := make(chan struct{}, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, _ = handler(context.Background(), mcp.CallToolRequest{})
+			done <- struct{}{}
+		}()
+	}
+	<-started
+	<-started
+
+	_, err := handler(context.Background(), mcp.CallToolRequest{})
+	if err == nil {
+		t.Fatal("expected a busy error with both slots held and no queue configured")
+	}
+	pe, ok := err.(*protocolError)
+	if !ok || pe.Code() != serverBusyCode {
+		t.Fatalf("err = %v, want a *protocolError with code %d", err, serverBusyCode)
+	}
+
+	close(release)
+	<-done
+	<-done
+}
+
+func TestNewConcurrencyMiddleware_QueuesUpToQueueSizeThenRefuses(t *testing.T) {
+	withTestConcurrencyLimit(t, 1, 1)
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	handler := newConcurrencyMiddleware()(blockingHandler(started, release))
+
+	firstDone := make(chan struct{})
+	go func() {
+		_, _ = handler(context.Background(), mcp.CallToolRequest{})
+		close(firstDone)
+	}()
+	<-started
+
+	secondResult := make(chan error, 1)
+	go func() {
+		_, err := handler(context.Background(), mcp.CallToolRequest{})
+		secondResult <- err
+	}()
+	// Give the second call time to enter the queue before the third is sent.
+	time.Sleep(20 * time.Millisecond)
+
+	_, err := handler(context.Background(), mcp.CallToolRequest{})
+	if err == nil {
+		t.Fatal("expected a busy error once the queue itself is full")
+	}
+	if pe, ok := err.(*protocolError); !ok || pe.Code() != serverBusyCode {
+		t.Fatalf("err = %v, want a *protocolError with code %d", err, serverBusyCode)
+	}
+
+	close(release)
+	<-firstDone
+	if err := <-secondResult; err != nil {
+		t.Fatalf("queued call returned %v, want it to eventually succeed", err)
+	}
+}
+
+func TestNewConcurrencyMiddleware_ReleasesSlotOnPanic(t *testing.T) {
+	withTestConcurrencyLimit(t, 1, 0)
+
+	panicking := newConcurrencyMiddleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		panic("boom")
+	})
+
+	func() {
+		defer func() { _ = recover() }()
+		_, _ = panicking(context.Background(), mcp.CallToolRequest{})
+	}()
+
+	select {
+	case <-concurrencySlots:
+	default:
+		t.Fatal("slot was not released after the handler panicked")
+	}
+}
+
+func TestNewConcurrencyMiddleware_ReleasesQueueSlotOnContextCancel(t *testing.T) {
+	withTestConcurrencyLimit(t, 1, 1)
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	handler := newConcurrencyMiddleware()(blockingHandler(started, release))
+	defer close(release)
+
+	firstDone := make(chan struct{})
+	go func() {
+		_, _ = handler(context.Background(), mcp.CallToolRequest{})
+		close(firstDone)
+	}()
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	secondResult := make(chan error, 1)
+	go func() {
+		_, err := handler(ctx, mcp.CallToolRequest{})
+		secondResult <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-secondResult:
+		if err != context.Canceled {
+			t.Fatalf("err = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("queued call did not return after its context was cancelled")
+	}
+
+	if got := concurrencyQueued.Load(); got != 0 {
+		t.Errorf("concurrencyQueued = %d, want 0 after the cancelled waiter left the queue", got)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// corsConfig controls the CORS headers the sse and http transports answer
+// preflight and regular requests with, so browser-based clients can reach
+// them across origins. A wildcard AllowedOrigins entry and
+// AllowCredentials are mutually exclusive per the CORS spec (browsers
+// reject "Access-Control-Allow-Origin: *" together with
+// "Access-Control-Allow-Credentials: true"), so newCORSConfig rejects that
+// combination up front instead of producing headers no browser will honor.
+type corsConfig struct {
+	AllowedOrigins   []string
+	AllowCredentials bool
+	// AllowedHeaders is echoed back as Access-Control-Allow-Headers on a
+	// preflight response. Empty means defaultCORSAllowedHeaders.
+	AllowedHeaders []string
+}
+
+// defaultCORSAllowedHeaders is what withCORS answers preflight with when
+// -cors-headers isn't set: the headers this fixture's own endpoints
+// actually read (Content-Type, Authorization) plus the two MCP-specific
+// ones a browser client needs to resend on follow-up requests
+// (Mcp-Session-Id) or a GET resume (Last-Event-ID).
+var defaultCORSAllowedHeaders = []string{"Content-Type", "Authorization", "Mcp-Session-Id", "Last-Event-ID"}
+
+func newCORSConfig(allowedOrigins []string, allowCredentials bool, allowedHeaders []string) (*corsConfig, error) {
+	if allowCredentials {
+		for _, origin := range allowedOrigins {
+			if origin == "*" {
+				return nil, fmt.Errorf("cors: AllowedOrigins cannot include \"*\" when AllowCredentials is true")
+			}
+		}
+	}
+	return &corsConfig{AllowedOrigins: allowedOrigins, AllowCredentials: allowCredentials, AllowedHeaders: allowedHeaders}, nil
+}
+
+func (c *corsConfig) allows(origin string) bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// withCORS wraps next so that requests from an allowed Origin get the
+// matching Access-Control-Allow-* response headers, and OPTIONS preflight
+// requests are answered directly without reaching next. A nil cfg (no
+// -cors-allowed-origins configured) is a no-op passthrough.
+func withCORS(cfg *corsConfig, next http.Handler) http.Handler {
+	if cfg == nil {
+		return next
+	}
+
+	allowedHeaders := cfg.AllowedHeaders
+	if len(allowedHeaders) == 0 {
+		allowedHeaders = defaultCORSAllowedHeaders
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin != "" && cfg.allows(origin) {
+			if cfg.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			}
+			w.Header().Set("Vary", "Origin")
+			// Exposed so a browser client's JS can read the session id the
+			// sse/http/both transports hand back on initialize.
+			w.Header().Set("Access-Control-Expose-Headers", "Mcp-Session-Id")
+		}
+
+		if r.Method == http.MethodOptions {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(allowedHeaders, ", "))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
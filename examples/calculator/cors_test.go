@@ -0,0 +1,151 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewCORSConfig_WildcardWithCredentialsIsAnError(t *testing.T) {
+	_, err := newCORSConfig([]string{"*"}, true, nil)
+	if err == nil {
+		t.Fatal("expected an error for wildcard origin + credentials")
+	}
+}
+
+func TestNewCORSConfig_WildcardWithoutCredentialsIsFine(t *testing.T) {
+	cfg, err := newCORSConfig([]string{"*"}, false, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.allows("https://anything.example.com") {
+		t.Error("expected the wildcard config to allow any origin")
+	}
+}
+
+func TestWithCORS_NilConfigIsPassthrough(t *testing.T) {
+	srv := httptest.NewServer(withCORS(nil, okHandler()))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	if resp.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Error("expected no CORS headers with a nil config")
+	}
+}
+
+func TestWithCORS_AllowedOriginGetsHeader(t *testing.T) {
+	cfg, err := newCORSConfig([]string{"https://app.example.com"}, true, nil)
+	if err != nil {
+		t.Fatalf("newCORSConfig: %v", err)
+	}
+
+	srv := httptest.NewServer(withCORS(cfg, okHandler()))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Origin", "https://app.example.com")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want the echoed origin", got)
+	}
+	if got := resp.Header.Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Access-Control-Allow-Credentials = %q, want \"true\"", got)
+	}
+}
+
+func TestWithCORS_DisallowedOriginGetsNoHeader(t *testing.T) {
+	cfg, err := newCORSConfig([]string{"https://app.example.com"}, false, nil)
+	if err != nil {
+		t.Fatalf("newCORSConfig: %v", err)
+	}
+
+	srv := httptest.NewServer(withCORS(cfg, okHandler()))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	if resp.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Error("expected no CORS headers for a disallowed origin")
+	}
+}
+
+func TestWithCORS_AllowedOriginExposesSessionIDHeader(t *testing.T) {
+	cfg, err := newCORSConfig([]string{"https://app.example.com"}, false, nil)
+	if err != nil {
+		t.Fatalf("newCORSConfig: %v", err)
+	}
+
+	srv := httptest.NewServer(withCORS(cfg, okHandler()))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Origin", "https://app.example.com")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	if got := resp.Header.Get("Access-Control-Expose-Headers"); got != "Mcp-Session-Id" {
+		t.Errorf("Access-Control-Expose-Headers = %q, want %q", got, "Mcp-Session-Id")
+	}
+}
+
+func TestWithCORS_PreflightUsesCustomAllowedHeaders(t *testing.T) {
+	cfg, err := newCORSConfig([]string{"https://app.example.com"}, false, []string{"X-Custom-Header"})
+	if err != nil {
+		t.Fatalf("newCORSConfig: %v", err)
+	}
+
+	srv := httptest.NewServer(withCORS(cfg, okHandler()))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodOptions, srv.URL, nil)
+	req.Header.Set("Origin", "https://app.example.com")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("OPTIONS: %v", err)
+	}
+	if got := resp.Header.Get("Access-Control-Allow-Headers"); got != "X-Custom-Header" {
+		t.Errorf("Access-Control-Allow-Headers = %q, want %q", got, "X-Custom-Header")
+	}
+}
+
+func TestWithCORS_PreflightAnsweredWithoutReachingNext(t *testing.T) {
+	cfg, err := newCORSConfig([]string{"https://app.example.com"}, false, nil)
+	if err != nil {
+		t.Fatalf("newCORSConfig: %v", err)
+	}
+
+	reached := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { reached = true })
+
+	srv := httptest.NewServer(withCORS(cfg, next))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodOptions, srv.URL, nil)
+	req.Header.Set("Origin", "https://app.example.com")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("OPTIONS: %v", err)
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+	}
+	if reached {
+		t.Error("expected the preflight request to be answered without reaching next")
+	}
+}
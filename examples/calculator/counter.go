@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// counterKey is the sessionStore key counter.go reads and writes, so two
+// concurrently connected clients never see each other's count. Entries
+// are created lazily on first use and dropped when the owning session
+// disconnects; see sessionstore.go for the backing SessionStore.
+const counterKey = "counter"
+
+// registerCounterTools wires up counter_increment and counter_get.
+func registerCounterTools(s *server.MCPServer) {
+	incrementTool := mcp.NewTool("counter_increment",
+		mcp.WithDescription("Increment this session's counter and return its new value"),
+	)
+	addToolWithMiddleware(s, incrementTool, handleCounterIncrementTool, recoverMiddleware)
+
+	getTool := mcp.NewTool("counter_get",
+		mcp.WithDescription("Return this session's current counter value without incrementing it"),
+	)
+	addToolWithMiddleware(s, getTool, handleCounterGetTool, recoverMiddleware)
+}
+
+// registerCounterHooks drops a session's counter once it disconnects, so
+// long-lived processes don't accumulate an entry per client forever.
+// sessionStore.DeleteSession also drops any other per-session key a
+// future feature stores alongside it.
+func registerCounterHooks(hooks *server.Hooks) {
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		sessionStore.DeleteSession(session.SessionID())
+	})
+}
+
+func incrementCounter(sessionID string) int {
+	value := getCounter(sessionID) + 1
+	sessionStore.Set(sessionID, counterKey, strconv.Itoa(value))
+	return value
+}
+
+func getCounter(sessionID string) int {
+	raw, ok := sessionStore.Get(sessionID, counterKey)
+	if !ok {
+		return 0
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+func deleteCounter(sessionID string) {
+	sessionStore.DeleteSession(sessionID)
+}
+
+// sessionOrStdioID returns the request's MCP session ID, or a fixed
+// placeholder for stdio, which has no session concept of its own but
+// exactly one "connection" for the process's lifetime.
+func sessionOrStdioID(ctx context.Context) string {
+	if id := sessionIDFromContext(ctx); id != "" {
+		return id
+	}
+	return "stdio"
+}
+
+func handleCounterIncrementTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	value := incrementCounter(sessionOrStdioID(ctx))
+	return mcp.NewToolResultText(fmt.Sprintf("%d", value)), nil
+}
+
+func handleCounterGetTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	value := getCounter(sessionOrStdioID(ctx))
+	return mcp.NewToolResultText(fmt.Sprintf("%d", value)), nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestIncrementCounter_IsMonotonicPerSession(t *testing.T) {
+	const session = "counter-session-1"
+	t.Cleanup(func() { deleteCounter(session) })
+
+	for i := 1; i <= 3; i++ {
+		if got := incrementCounter(session); got != i {
+			t.Fatalf("incrementCounter() = %d, want %d", got, i)
+		}
+	}
+	if got := getCounter(session); got != 3 {
+		t.Errorf("getCounter() = %d, want 3", got)
+	}
+}
+
+func TestIncrementCounter_SessionsDoNotBleedIntoEachOther(t *testing.T) {
+	const sessionA, sessionB = "counter-session-a", "counter-session-b"
+	t.Cleanup(func() { deleteCounter(sessionA); deleteCounter(sessionB) })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() { defer wg.Done(); incrementCounter(sessionA) }()
+		go func() { defer wg.Done(); incrementCounter(sessionB) }()
+	}
+	wg.Wait()
+
+	if got := getCounter(sessionA); got != 50 {
+		t.Errorf("counter for sessionA = %d, want 50", got)
+	}
+	if got := getCounter(sessionB); got != 50 {
+		t.Errorf("counter for sessionB = %d, want 50", got)
+	}
+}
+
+func TestDeleteCounter_ResetsToZero(t *testing.T) {
+	const session = "counter-session-2"
+
+	incrementCounter(session)
+	deleteCounter(session)
+
+	if got := getCounter(session); got != 0 {
+		t.Errorf("getCounter() after delete = %d, want 0", got)
+	}
+}
+
+func TestHandleCounterGetTool_StartsAtZero(t *testing.T) {
+	deleteCounter("stdio")
+	t.Cleanup(func() { deleteCounter("stdio") })
+
+	res, err := handleCounterGetTool(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.IsError {
+		t.Fatalf("expected a successful result")
+	}
+	text, ok := res.Content[0].(mcp.TextContent)
+	if !ok || text.Text != "0" {
+		t.Errorf("content = %v, want \"0\"", res.Content)
+	}
+}
+
+func TestHandleCounterIncrementTool_ReturnsIncrementedValue(t *testing.T) {
+	deleteCounter("stdio")
+	t.Cleanup(func() { deleteCounter("stdio") })
+
+	if _, err := handleCounterIncrementTool(context.Background(), mcp.CallToolRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res, err := handleCounterIncrementTool(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text, ok := res.Content[0].(mcp.TextContent)
+	if !ok || text.Text != "2" {
+		t.Errorf("content = %v, want \"2\"", res.Content)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// debugEnabled gates logDebugMessage. It defaults to off: this fixture's
+// protocol-level tracing is deliberately opt-in, since dumping every
+// request/response as JSON to stderr on every run would drown out the
+// log.Printf lines operators already rely on. main wires it to --debug.
+var debugEnabled = false
+
+// debugMaxBytes caps how much of a message's raw JSON debugLogger logs
+// before truncating, so a generate_text call with a multi-megabyte
+// size_bytes argument doesn't itself become the thing that floods the
+// debug stream. main wires this to --debug-max-bytes; 0 or negative
+// disables truncation entirely.
+var debugMaxBytes = 2048
+
+// debugLogHandlerOptions enables slog.LevelDebug: the default JSON handler
+// level is Info, which would otherwise silently drop every
+// debugLogger.Debug call below.
+var debugLogHandlerOptions = &slog.HandlerOptions{Level: slog.LevelDebug}
+
+// debugLogger is the sink logDebugMessage writes to: JSON lines on stderr,
+// optionally duplicated to a --log-file. It's never os.Stdout, which the
+// stdio transport owns end to end — any debug output on stdout would be
+// indistinguishable from a real JSON-RPC response to a stdio client. It
+// starts pointed at stderr alone; configureDebugLogging (called from main)
+// replaces it once --log-file is known.
+var debugLogger = slog.New(slog.NewJSONHandler(os.Stderr, debugLogHandlerOptions))
+
+// configureDebugLogging points debugLogger at stderr, or at stderr and
+// logFilePath together when logFilePath is non-empty, opening the file in
+// append mode so successive runs against the same --log-file (e.g. across
+// a CI job's retries) don't clobber earlier output. The returned
+// io.Closer is the opened file, nil when logFilePath is empty; main defers
+// closing it (best-effort — a debug log losing its last buffered line on a
+// hard kill isn't worth failing the run over).
+func configureDebugLogging(logFilePath string) (io.Closer, error) {
+	if logFilePath == "" {
+		debugLogger = slog.New(slog.NewJSONHandler(os.Stderr, debugLogHandlerOptions))
+		return nil, nil
+	}
+
+	file, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	debugLogger = slog.New(slog.NewJSONHandler(io.MultiWriter(os.Stderr, file), debugLogHandlerOptions))
+	return file, nil
+}
+
+// truncateForDebug renders raw as a string, cut to debugMaxBytes with a
+// trailing marker when it's longer.
+func truncateForDebug(raw []byte) string {
+	if debugMaxBytes <= 0 || len(raw) <= debugMaxBytes {
+		return string(raw)
+	}
+	return string(raw[:debugMaxBytes]) + "...(truncated)"
+}
+
+// debugMessagePeek is enough of a JSON-RPC message's shape to surface
+// method, id, and any error code as queryable log fields, in the same
+// peek-before-trust spirit as jsonrpcEnvelope (strict.go) — it decodes
+// requests, responses, and errors alike rather than assuming which one raw
+// is.
+type debugMessagePeek struct {
+	ID     any    `json:"id"`
+	Method string `json:"method"`
+	Error  *struct {
+		Code int `json:"code"`
+	} `json:"error"`
+}
+
+// logDebugMessage records one JSON-RPC message crossing the wire in either
+// direction. direction is "in" or "out"; duration is the time spent
+// producing an "out" message and is omitted (zero) for "in". method, id,
+// and error_code are extracted from raw itself rather than threaded in by
+// the caller, so every call site logs the same fields regardless of
+// whether raw is a request, a notification, or a response.
+func logDebugMessage(ctx context.Context, direction string, raw []byte, duration time.Duration) {
+	if !debugEnabled {
+		return
+	}
+
+	var peek debugMessagePeek
+	_ = json.Unmarshal(raw, &peek)
+
+	attrs := []any{
+		slog.String("direction", direction),
+		slog.String("session_id", sessionOrStdioID(ctx)),
+	}
+	if peek.Method != "" {
+		attrs = append(attrs, slog.String("method", peek.Method))
+	}
+	if peek.ID != nil {
+		attrs = append(attrs, slog.Any("id", peek.ID))
+	}
+	if peek.Error != nil {
+		attrs = append(attrs, slog.Int("error_code", peek.Error.Code))
+	}
+	if duration > 0 {
+		attrs = append(attrs, slog.Duration("duration", duration))
+	}
+	attrs = append(attrs, slog.String("payload", truncateForDebug(raw)))
+
+	debugLogger.Debug("mcp message", attrs...)
+}
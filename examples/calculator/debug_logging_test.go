@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func withTestDebugLogging(t *testing.T, logFilePath string) {
+	t.Helper()
+	origEnabled, origMaxBytes := debugEnabled, debugMaxBytes
+	debugEnabled, debugMaxBytes = true, 2048
+	closer, err := configureDebugLogging(logFilePath)
+	if err != nil {
+		t.Fatalf("configureDebugLogging: %v", err)
+	}
+	t.Cleanup(func() {
+		if closer != nil {
+			closer.Close()
+		}
+		debugEnabled, debugMaxBytes = origEnabled, origMaxBytes
+		_, _ = configureDebugLogging("")
+	})
+}
+
+// TestServeStdioTolerant_DebugLoggingKeepsStdoutPristine runs an
+// initialize/tools-call exchange over stdio with -debug pointed at a
+// --log-file, and asserts the stdio transport's stdout contains only valid
+// JSON-RPC response lines (no interleaved debug output, since debugLogger
+// never writes there) while the log file captures both directions with
+// method, id, and duration fields.
+func TestServeStdioTolerant_DebugLoggingKeepsStdoutPristine(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "debug.log")
+	withTestDebugLogging(t, logPath)
+
+	origStdin, origStdout := os.Stdin, os.Stdout
+	defer func() { os.Stdin, os.Stdout = origStdin, origStdout }()
+
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdin): %v", err)
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdout): %v", err)
+	}
+	os.Stdin, os.Stdout = stdinR, stdoutW
+
+	srv := newTestServer()
+	done := make(chan error, 1)
+	go func() { done <- serveStdioTolerant(context.Background(), srv) }()
+
+	writeLine(t, stdinW, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`)
+	writeLine(t, stdinW, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"calculate","arguments":{"operation":"add","x":1,"y":2}}}`)
+
+	scanner := bufio.NewScanner(stdoutR)
+	for i, wantID := range []float64{1, 2} {
+		if !scanner.Scan() {
+			t.Fatalf("expected response %d, scan error: %v", i, scanner.Err())
+		}
+		var resp map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("stdout line %q is not valid JSON: %v", scanner.Text(), err)
+		}
+		if _, ok := resp["result"]; !ok {
+			t.Fatalf("expected a result in response %d, got %v", i, resp)
+		}
+		if id, ok := resp["id"].(float64); !ok || id != wantID {
+			t.Errorf("response %d id = %v, want %v", i, resp["id"], wantID)
+		}
+	}
+
+	stdinW.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serveStdioTolerant returned %v after EOF, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("serveStdioTolerant did not return after stdin closed")
+	}
+	stdoutW.Close()
+
+	logBytes, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("reading log file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(logBytes)), "\n")
+	if len(lines) < 4 {
+		t.Fatalf("expected at least 4 debug log lines (in/out x2), got %d:\n%s", len(lines), logBytes)
+	}
+
+	var sawInitialize, sawToolsCall, sawDuration bool
+	for _, line := range lines {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("log line %q is not valid JSON: %v", line, err)
+		}
+		switch entry["method"] {
+		case "initialize":
+			sawInitialize = true
+		case "tools/call":
+			sawToolsCall = true
+		}
+		if entry["direction"] == "out" {
+			if _, ok := entry["duration"]; ok {
+				sawDuration = true
+			}
+		}
+	}
+	if !sawInitialize {
+		t.Errorf("expected a log line referencing method %q, got:\n%s", "initialize", logBytes)
+	}
+	if !sawToolsCall {
+		t.Errorf("expected a log line referencing method %q, got:\n%s", "tools/call", logBytes)
+	}
+	if !sawDuration {
+		t.Errorf("expected at least one outbound log line with a duration field, got:\n%s", logBytes)
+	}
+}
+
+func TestLogDebugMessage_DisabledByDefaultWritesNothing(t *testing.T) {
+	origEnabled := debugEnabled
+	debugEnabled = false
+	t.Cleanup(func() { debugEnabled = origEnabled })
+
+	var buf bytes.Buffer
+	origLogger := debugLogger
+	debugLogger = slog.New(slog.NewJSONHandler(&buf, debugLogHandlerOptions))
+	t.Cleanup(func() { debugLogger = origLogger })
+
+	logDebugMessage(context.Background(), "in", []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`), 0)
+	if buf.Len() != 0 {
+		t.Errorf("expected no debug output with debugEnabled false, got %q", buf.String())
+	}
+}
+
+func TestTruncateForDebug_CutsLongPayloadsWithMarker(t *testing.T) {
+	origMax := debugMaxBytes
+	debugMaxBytes = 8
+	t.Cleanup(func() { debugMaxBytes = origMax })
+
+	got := truncateForDebug([]byte("0123456789"))
+	want := "01234567...(truncated)"
+	if got != want {
+		t.Errorf("truncateForDebug = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newDefaultsMiddleware injects each declared property's "default" (as set
+// by mcp.DefaultNumber/DefaultString/DefaultBool) into request.Params.Arguments
+// whenever the client omitted that argument. It must be the outermost
+// middleware in any addToolWithMiddleware chain so every other middleware —
+// validation, logging, the handler itself — sees a fully-populated argument
+// map, exactly as if the client had sent the default explicitly.
+func newDefaultsMiddleware(tool mcp.Tool) toolMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if request.Params.Arguments == nil {
+				request.Params.Arguments = map[string]any{}
+			}
+			for name, raw := range tool.InputSchema.Properties {
+				if _, present := request.Params.Arguments[name]; present {
+					continue
+				}
+				prop, ok := raw.(map[string]any)
+				if !ok {
+					continue
+				}
+				if def, ok := prop["default"]; ok {
+					request.Params.Arguments[name] = def
+				}
+			}
+			return next(ctx, request)
+		}
+	}
+}
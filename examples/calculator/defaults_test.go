@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestNewDefaultsMiddleware_InjectsDefaultWhenOmitted(t *testing.T) {
+	tool := mcp.NewTool("widget",
+		mcp.WithNumber("limit", mcp.DefaultNumber(10)),
+	)
+
+	var seen map[string]any
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		seen = request.Params.Arguments
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	wrapped := newDefaultsMiddleware(tool)(handler)
+	req := mcp.CallToolRequest{}
+
+	if _, err := wrapped(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if seen["limit"] != 10.0 {
+		t.Errorf("limit = %v, want 10", seen["limit"])
+	}
+}
+
+func TestNewDefaultsMiddleware_LeavesExplicitValueAlone(t *testing.T) {
+	tool := mcp.NewTool("widget",
+		mcp.WithNumber("limit", mcp.DefaultNumber(10)),
+	)
+
+	var seen map[string]any
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		seen = request.Params.Arguments
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	wrapped := newDefaultsMiddleware(tool)(handler)
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"limit": 5.0}
+
+	if _, err := wrapped(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if seen["limit"] != 5.0 {
+		t.Errorf("limit = %v, want 5 (the explicit value should not be overwritten)", seen["limit"])
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerDynamicRegistrationTools wires up management tools that add and
+// remove other tools and resources at runtime, to exercise tools/list_changed
+// and resources/list_changed against sessions that declared the
+// corresponding capability (see server.WithToolCapabilities and
+// server.WithResourceCapabilities in main.go). Emitting those notifications
+// on mutation and keeping a tools/call arriving mid-mutation from racing
+// against s's internal tool map is *MCPServer's own responsibility — the
+// same division of labor as the cancellation contract documented on serve()
+// in transport.go. What's ours to get right is not racing with ourselves
+// across concurrent calls to these tools, which dynamicToolsMu exists
+// to serialize.
+var dynamicToolsMu sync.Mutex
+
+// adminRegisteredTools tracks which tool names were added through
+// admin_register_tool, guarded by dynamicToolsMu. *server.MCPServer has no
+// "does this name exist" query we can rely on, so admin_unregister_tool's
+// built-in protection works by checking this set instead: only a name this
+// fixture itself registered dynamically can be removed through it.
+var adminRegisteredTools = map[string]bool{}
+
+func registerDynamicRegistrationTools(s *server.MCPServer) {
+	s.AddTool(
+		mcp.NewTool("admin_register_tool",
+			mcp.WithDescription("Registers a new echo-style tool with the given name and description"),
+			mcp.WithString("name", mcp.Required(), mcp.Description("Name for the new tool")),
+			mcp.WithString("description", mcp.Required(), mcp.Description("Description for the new tool")),
+		),
+		adminRegisterToolHandler(s),
+	)
+
+	s.AddTool(
+		mcp.NewTool("admin_unregister_tool",
+			mcp.WithDescription("Removes a tool previously added through admin_register_tool"),
+			mcp.WithString("name", mcp.Required(), mcp.Description("Name of the tool to remove")),
+		),
+		adminUnregisterToolHandler(s),
+	)
+
+	s.AddTool(
+		mcp.NewTool("register_echo_tool",
+			mcp.WithDescription("Registers a new tool, named by the caller, that echoes back its \"text\" argument"),
+			mcp.WithString("name", mcp.Required(), mcp.Description("Name for the new tool")),
+		),
+		registerEchoToolHandler(s),
+	)
+
+	s.AddTool(
+		mcp.NewTool("unregister_tool",
+			mcp.WithDescription("Removes a previously registered tool by name"),
+			mcp.WithString("name", mcp.Required(), mcp.Description("Name of the tool to remove")),
+		),
+		unregisterToolHandler(s),
+	)
+
+	s.AddTool(
+		mcp.NewTool("unregister_resource",
+			mcp.WithDescription("Removes a previously added resource by URI"),
+			mcp.WithString("uri", mcp.Required(), mcp.Description("URI of the resource to remove")),
+		),
+		unregisterResourceHandler(s),
+	)
+}
+
+type dynamicToolNameArgs struct {
+	Name string `json:"name"`
+}
+
+type adminRegisterToolArgs struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// adminRegisterToolHandler registers a new echo-style tool under the
+// caller's chosen name and description, rejecting a name that's already
+// registered — whether that's a built-in tool or one a prior
+// admin_register_tool call already claimed — rather than silently
+// overwriting it. *server.MCPServer emits notifications/tools/list_changed
+// on AddTool itself; that's its own responsibility, not ours.
+func adminRegisterToolHandler(s *server.MCPServer) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var args adminRegisterToolArgs
+		if err := bindArguments(request, &args); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if args.Name == "" {
+			return nil, invalidParamsf("name must not be empty")
+		}
+
+		dynamicToolsMu.Lock()
+		defer dynamicToolsMu.Unlock()
+
+		if adminRegisteredTools[args.Name] {
+			return toolResultErrorf("tool %q is already registered", args.Name), nil
+		}
+
+		tool := mcp.NewTool(args.Name,
+			mcp.WithDescription(args.Description),
+			withObject("payload", nil, required()),
+		)
+		finalizeRequired(&tool)
+
+		s.AddTool(tool, handleEchoTool)
+		adminRegisteredTools[args.Name] = true
+
+		return mcp.NewToolResultText(fmt.Sprintf("registered tool %q", args.Name)), nil
+	}
+}
+
+// adminUnregisterToolHandler removes a tool previously added through
+// admin_register_tool. A name this fixture didn't register dynamically —
+// whether a built-in like calculate or simply unknown — is rejected rather
+// than handed to s.DeleteTools, since that call would otherwise succeed
+// silently against a built-in tool it has no business removing.
+func adminUnregisterToolHandler(s *server.MCPServer) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var args dynamicToolNameArgs
+		if err := bindArguments(request, &args); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		dynamicToolsMu.Lock()
+		defer dynamicToolsMu.Unlock()
+
+		if !adminRegisteredTools[args.Name] {
+			return toolResultErrorf("tool %q is a built-in tool or isn't registered, and cannot be removed through admin_unregister_tool", args.Name), nil
+		}
+
+		s.DeleteTools(args.Name)
+		delete(adminRegisteredTools, args.Name)
+
+		return mcp.NewToolResultText(fmt.Sprintf("unregistered tool %q", args.Name)), nil
+	}
+}
+
+// registerEchoToolHandler returns a handler for register_echo_tool rather
+// than being one itself, so it can close over s the same way
+// registerHistoryResource's callback closes over a history index.
+func registerEchoToolHandler(s *server.MCPServer) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var args dynamicToolNameArgs
+		if err := bindArguments(request, &args); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if args.Name == "" {
+			return nil, invalidParamsf("name must not be empty")
+		}
+
+		tool := mcp.NewTool(args.Name,
+			mcp.WithDescription(fmt.Sprintf("Dynamically registered echo tool %q", args.Name)),
+			withObject("payload", nil, required()),
+		)
+		finalizeRequired(&tool)
+
+		dynamicToolsMu.Lock()
+		s.AddTool(tool, handleEchoTool)
+		dynamicToolsMu.Unlock()
+
+		return mcp.NewToolResultText(fmt.Sprintf("registered tool %q", args.Name)), nil
+	}
+}
+
+func unregisterToolHandler(s *server.MCPServer) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var args dynamicToolNameArgs
+		if err := bindArguments(request, &args); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		dynamicToolsMu.Lock()
+		s.DeleteTools(args.Name)
+		dynamicToolsMu.Unlock()
+
+		return mcp.NewToolResultText(fmt.Sprintf("unregistered tool %q", args.Name)), nil
+	}
+}
+
+func unregisterResourceHandler(s *server.MCPServer) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var args struct {
+			URI string `json:"uri"`
+		}
+		if err := bindArguments(request, &args); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		dynamicToolsMu.Lock()
+		s.RemoveResource(args.URI)
+		dynamicToolsMu.Unlock()
+
+		return mcp.NewToolResultText(fmt.Sprintf("removed resource %q", args.URI)), nil
+	}
+}
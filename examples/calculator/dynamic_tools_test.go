@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// TestRegisterEchoToolHandler_AddsAToolVisibleInToolsList and
+// TestAdminRegisterToolHandler_AddsAToolVisibleInToolsList are skipped:
+// mcp-go v0.23.1 has no Streamable HTTP server at all
+// (server.NewStreamableHTTPServer and server.WithEndpointPath don't exist
+// in this version — it ships SSE only, see serveHTTP's doc comment in
+// transport.go). TestRegisterEchoToolHandler_RejectsEmptyName and
+// TestUnregisterResourceHandler_RemovesResource below still exercise the
+// handlers directly instead of over HTTP.
+func TestRegisterEchoToolHandler_AddsAToolVisibleInToolsList(t *testing.T) {
+	t.Skip("mcp-go v0.23.1 has no Streamable HTTP server; see serveHTTP's doc comment in transport.go")
+}
+
+func TestAdminRegisterToolHandler_AddsAToolVisibleInToolsList(t *testing.T) {
+	t.Skip("mcp-go v0.23.1 has no Streamable HTTP server; see serveHTTP's doc comment in transport.go")
+}
+
+func TestRegisterEchoToolHandler_RejectsEmptyName(t *testing.T) {
+	s := server.NewMCPServer("Calculator", "0.1.0", server.WithToolCapabilities(true))
+	registerDynamicRegistrationTools(s)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": ""}
+
+	_, err := registerEchoToolHandler(s)(context.Background(), req)
+	if _, ok := err.(*protocolError); !ok {
+		t.Fatalf("err = %T, want *protocolError", err)
+	}
+}
+
+func TestUnregisterResourceHandler_RemovesResource(t *testing.T) {
+	s := server.NewMCPServer("Calculator", "0.1.0", server.WithResourceCapabilities(true, true))
+	s.AddResource(mcp.NewResource("calc://scratch", "Scratch"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return nil, nil
+	})
+	registerDynamicRegistrationTools(s)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"uri": "calc://scratch"}
+
+	res, err := unregisterResourceHandler(s)(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.IsError {
+		t.Fatalf("expected a successful result")
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// fetchableResources maps the URIs fetch_document is willing to embed to the
+// same handlers resources/read already dispatches to, so a "fetch document"
+// tool result and a plain resource read never disagree about a URI's
+// contents.
+var fetchableResources = map[string]func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error){
+	"config:///calculator":  handleConfigResource,
+	"calc://history/latest": handleHistoryLatestResource,
+}
+
+// registerFetchDocumentTool wires up fetch_document, which returns a
+// resource's contents embedded in a tool result (an EmbeddedResource
+// content block) instead of flattened into plain text, so a client can
+// cache or re-read it by URI.
+//
+// mcp.NewToolResultResource(uri, contents) itself would be a convenience
+// constructor living in the mcp-go library, not this fixture's own
+// package — see describe_result's doc comment in audio.go for the same
+// reasoning. What we build here is the EmbeddedResource content block that
+// constructor would ultimately return, using the mcp.ResourceContents
+// values resources/read already produces.
+func registerFetchDocumentTool(s *server.MCPServer) {
+	tool := mcp.NewTool("fetch_document",
+		mcp.WithDescription("Fetch a resource by URI and return its contents embedded in the tool result"),
+		mcp.WithString("uri", mcp.Required(), mcp.Description("A resource URI, e.g. config:///calculator or calc://history/latest")),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Fetch Document",
+			ReadOnlyHint:    true,
+			DestructiveHint: false,
+			IdempotentHint:  true,
+		}),
+	)
+	addToolWithMiddleware(s, tool, handleFetchDocumentTool, recoverMiddleware)
+}
+
+type fetchDocumentArgs struct {
+	URI string `json:"uri"`
+}
+
+func handleFetchDocumentTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var args fetchDocumentArgs
+	if err := bindArguments(request, &args); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	parsed, err := url.Parse(args.URI)
+	if err != nil || parsed.Scheme == "" {
+		return nil, invalidParamsf("uri %q is not a well-formed URI", args.URI)
+	}
+
+	handler, ok := fetchableResources[args.URI]
+	if !ok {
+		return toolResultErrorf("no resource registered at %q", args.URI), nil
+	}
+
+	readReq := mcp.ReadResourceRequest{}
+	readReq.Params.URI = args.URI
+	contents, err := handler(ctx, readReq)
+	if err != nil {
+		return toolResultErrorf("%s", err.Error()), nil
+	}
+	if len(contents) == 0 {
+		return toolResultErrorf("resource %q returned no contents", args.URI), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{Type: "text", Text: fmt.Sprintf("fetched %s", args.URI)},
+			mcp.EmbeddedResource{Type: "resource", Resource: contents[0]},
+		},
+	}, nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func fetchDocumentRequest(uri string) mcp.CallToolRequest {
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "fetch_document"
+	req.Params.Arguments = map[string]any{"uri": uri}
+	return req
+}
+
+func TestHandleFetchDocumentTool_EmbedsTheResourceContents(t *testing.T) {
+	res, err := handleFetchDocumentTool(context.Background(), fetchDocumentRequest("config:///calculator"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Content) != 2 {
+		t.Fatalf("len(Content) = %d, want 2", len(res.Content))
+	}
+
+	embedded, ok := res.Content[1].(mcp.EmbeddedResource)
+	if !ok {
+		t.Fatalf("Content[1] = %T, want mcp.EmbeddedResource", res.Content[1])
+	}
+	text, ok := embedded.Resource.(mcp.TextResourceContents)
+	if !ok {
+		t.Fatalf("Resource = %T, want mcp.TextResourceContents", embedded.Resource)
+	}
+	if text.URI != "config:///calculator" {
+		t.Errorf("URI = %q, want %q", text.URI, "config:///calculator")
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(text.Text), &decoded); err != nil {
+		t.Errorf("embedded text did not round-trip as JSON: %v", err)
+	}
+}
+
+func TestHandleFetchDocumentTool_MalformedURIIsInvalidParams(t *testing.T) {
+	_, err := handleFetchDocumentTool(context.Background(), fetchDocumentRequest("not a uri"))
+	pe, ok := err.(*protocolError)
+	if !ok {
+		t.Fatalf("err = %T, want *protocolError", err)
+	}
+	if pe.Code() != mcp.INVALID_PARAMS {
+		t.Errorf("code = %d, want %d", pe.Code(), mcp.INVALID_PARAMS)
+	}
+}
+
+func TestHandleFetchDocumentTool_UnknownURIIsToolDomainError(t *testing.T) {
+	res, err := handleFetchDocumentTool(context.Background(), fetchDocumentRequest("calc://nope"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsError {
+		t.Errorf("IsError = false, want true")
+	}
+}
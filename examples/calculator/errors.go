@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// toolResultErrorf is mcp.NewToolResultError with fmt.Sprintf formatting,
+// for the common case of a tool-domain failure (a bad divisor, an
+// unsupported operation) that should come back as a *successful*
+// tools/call response with IsError set, not a JSON-RPC protocol error.
+func toolResultErrorf(format string, args ...any) *mcp.CallToolResult {
+	return mcp.NewToolResultError(fmt.Sprintf(format, args...))
+}
+
+// protocolError is returned as a handler's `error` return value (as
+// opposed to baked into a *mcp.CallToolResult) when the request itself was
+// malformed at the protocol level — bad params, an unknown method — so the
+// transport reports it as a real JSON-RPC error rather than a successful
+// result with isError: true. Clients are expected to retry these
+// differently than a tool-domain failure.
+type protocolError struct {
+	code    int
+	message string
+	data    any
+}
+
+func (e *protocolError) Error() string { return e.message }
+func (e *protocolError) Code() int     { return e.code }
+
+// Data exposes extra structured detail for the JSON-RPC error's optional
+// "data" member, beyond what fits in Error()'s plain message string.
+// recoverMiddleware sets it to the raw recovered panic value alongside the
+// human-readable message; the busy and rate-limit errors (concurrency.go,
+// rate_limit.go) set it to a retry_after_ms hint.
+func (e *protocolError) Data() any { return e.data }
+
+func invalidParamsf(format string, args ...any) *protocolError {
+	return &protocolError{code: mcp.INVALID_PARAMS, message: fmt.Sprintf(format, args...)}
+}
+
+func methodNotFoundf(format string, args ...any) *protocolError {
+	return &protocolError{code: mcp.METHOD_NOT_FOUND, message: fmt.Sprintf(format, args...)}
+}
+
+func internalErrorf(format string, args ...any) *protocolError {
+	return &protocolError{code: mcp.INTERNAL_ERROR, message: fmt.Sprintf(format, args...)}
+}
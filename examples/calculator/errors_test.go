@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleCalculateTool_UnsupportedOperationIsProtocolError(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"operation": "exponentiate", "x": 2.0, "y": 3.0}
+
+	res, err := handle_calculate_tool(context.Background(), req)
+	if res != nil {
+		t.Fatalf("expected no result, got %v", res)
+	}
+
+	pe, ok := err.(*protocolError)
+	if !ok {
+		t.Fatalf("expected a *protocolError, got %T: %v", err, err)
+	}
+	if pe.Code() != mcp.INVALID_PARAMS {
+		t.Errorf("code = %d, want INVALID_PARAMS", pe.Code())
+	}
+}
+
+func TestHandleCalculateTool_DivideByZeroIsToolDomainError(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"operation": "divide", "x": 1.0, "y": 0.0}
+
+	res, err := handle_calculate_tool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("expected a successful result with isError, got transport error: %v", err)
+	}
+	if !res.IsError {
+		t.Fatal("expected IsError to be true for division by zero")
+	}
+}
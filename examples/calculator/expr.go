@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxExpressionLength bounds the expression string evaluate will parse, so
+// a pathological input (deeply nested parentheses, a very long digit run)
+// can't pin a CPU core. Overridable via -max-expression-length.
+var maxExpressionLength = 200
+
+// registerEvaluateTool wires up evaluate, a full-expression counterpart to
+// calculate for callers who don't want to flatten "(3 + 4) * 2 / 7" into a
+// sequence of binary operation calls.
+func registerEvaluateTool(s *server.MCPServer) {
+	tool := mcp.NewTool("evaluate",
+		mcp.WithDescription("Evaluate an arithmetic expression with +, -, *, /, parentheses, and unary minus"),
+		mcp.WithString("expression", mcp.Required(), mcp.Description(`An expression such as "(3 + 4) * 2 / 7"`)),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Evaluate",
+			ReadOnlyHint:    true,
+			DestructiveHint: false,
+			IdempotentHint:  true,
+		}),
+	)
+	addToolWithMiddleware(s, tool, handleEvaluateTool, recoverMiddleware)
+}
+
+type evaluateArgs struct {
+	Expression string `json:"expression"`
+}
+
+func handleEvaluateTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var args evaluateArgs
+	if err := bindArguments(request, &args); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if len(args.Expression) > maxExpressionLength {
+		return nil, invalidParamsf("expression is %d characters, exceeding the %d-character limit", len(args.Expression), maxExpressionLength)
+	}
+
+	result, err := evaluateExpression(args.Expression)
+	if err != nil {
+		// A syntax error or a divide-by-zero are both problems with this
+		// particular expression, not the request envelope, so both come
+		// back as a tool-domain isError result rather than a protocol error.
+		return toolResultErrorf("%s", err.Error()), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("%v", result)), nil
+}
+
+// exprError is a parse or evaluation failure tied to a specific rune offset
+// in the source expression, so a client can point a user at the offending
+// token instead of just showing "syntax error".
+type exprError struct {
+	pos int
+	msg string
+}
+
+func (e *exprError) Error() string {
+	return fmt.Sprintf("position %d: %s", e.pos, e.msg)
+}
+
+type tokenKind int
+
+const (
+	tokenNumber tokenKind = iota
+	tokenPlus
+	tokenMinus
+	tokenStar
+	tokenSlash
+	tokenLParen
+	tokenRParen
+	tokenEOF
+)
+
+type token struct {
+	kind  tokenKind
+	value float64
+	pos   int
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '+':
+			tokens = append(tokens, token{kind: tokenPlus, pos: i})
+			i++
+		case r == '-':
+			tokens = append(tokens, token{kind: tokenMinus, pos: i})
+			i++
+		case r == '*':
+			tokens = append(tokens, token{kind: tokenStar, pos: i})
+			i++
+		case r == '/':
+			tokens = append(tokens, token{kind: tokenSlash, pos: i})
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, pos: i})
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			lit := string(runes[start:i])
+			n, err := strconv.ParseFloat(lit, 64)
+			if err != nil {
+				return nil, &exprError{pos: start, msg: fmt.Sprintf("invalid number literal %q", lit)}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: n, pos: start})
+		default:
+			return nil, &exprError{pos: i, msg: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(runes)})
+	return tokens, nil
+}
+
+// exprParser is a recursive-descent parser over the grammar:
+//
+//	expression = term (("+" | "-") term)*
+//	term       = unary (("*" | "/") unary)*
+//	unary      = "-" unary | primary
+//	primary    = number | "(" expression ")"
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *exprParser) peek() token  { return p.tokens[p.pos] }
+func (p *exprParser) advance() token {
+	t := p.tokens[p.pos]
+	p.pos++
+	return t
+}
+
+func (p *exprParser) parseExpression() (float64, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch p.peek().kind {
+		case tokenPlus:
+			p.advance()
+			right, err := p.parseTerm()
+			if err != nil {
+				return 0, err
+			}
+			left += right
+		case tokenMinus:
+			p.advance()
+			right, err := p.parseTerm()
+			if err != nil {
+				return 0, err
+			}
+			left -= right
+		default:
+			return left, nil
+		}
+	}
+}
+
+func (p *exprParser) parseTerm() (float64, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch p.peek().kind {
+		case tokenStar:
+			p.advance()
+			right, err := p.parseUnary()
+			if err != nil {
+				return 0, err
+			}
+			left *= right
+		case tokenSlash:
+			op := p.advance()
+			right, err := p.parseUnary()
+			if err != nil {
+				return 0, err
+			}
+			if right == 0 {
+				return 0, &exprError{pos: op.pos, msg: "division by zero"}
+			}
+			left /= right
+		default:
+			return left, nil
+		}
+	}
+}
+
+func (p *exprParser) parseUnary() (float64, error) {
+	if p.peek().kind == tokenMinus {
+		p.advance()
+		v, err := p.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		return -v, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (float64, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenNumber:
+		p.advance()
+		return t.value, nil
+	case tokenLParen:
+		p.advance()
+		v, err := p.parseExpression()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek().kind != tokenRParen {
+			return 0, &exprError{pos: p.peek().pos, msg: "expected ')'"}
+		}
+		p.advance()
+		return v, nil
+	case tokenEOF:
+		return 0, &exprError{pos: t.pos, msg: "unexpected end of expression"}
+	default:
+		return 0, &exprError{pos: t.pos, msg: "expected a number or '('"}
+	}
+}
+
+// evaluateExpression parses and evaluates expr in one pass. Syntax errors
+// and division by zero are both reported as an *exprError carrying the
+// offending token's rune offset; a non-finite result (NaN, +Inf, -Inf,
+// from e.g. an overflowing multiplication) is reported the same way.
+func evaluateExpression(expr string) (float64, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return 0, err
+	}
+
+	p := &exprParser{tokens: tokens}
+	v, err := p.parseExpression()
+	if err != nil {
+		return 0, err
+	}
+	if p.peek().kind != tokenEOF {
+		return 0, &exprError{pos: p.peek().pos, msg: "unexpected trailing input"}
+	}
+	if err := requireFinite(v); err != nil {
+		return 0, &exprError{pos: 0, msg: err.Error()}
+	}
+	return v, nil
+}
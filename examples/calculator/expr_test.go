@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestEvaluateExpression_TableDriven(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    float64
+		wantErr string
+	}{
+		{expr: "1", want: 1},
+		{expr: "1.5", want: 1.5},
+		{expr: "1 + 2", want: 3},
+		{expr: "1 - 2", want: -1},
+		{expr: "2 * 3", want: 6},
+		{expr: "6 / 3", want: 2},
+		{expr: "1 + 2 * 3", want: 7},
+		{expr: "(1 + 2) * 3", want: 9},
+		{expr: "(3 + 4) * 2 / 7", want: 2},
+		{expr: "-5", want: -5},
+		{expr: "-(5)", want: -5},
+		{expr: "--5", want: 5},
+		{expr: "3 - -2", want: 5},
+		{expr: "3 * -2", want: -6},
+		{expr: "-2 * -3", want: 6},
+		{expr: "((1))", want: 1},
+		{expr: "((1 + 2) * (3 + 4))", want: 21},
+		{expr: "10 / 4", want: 2.5},
+		{expr: "1 + 2 + 3 + 4 + 5", want: 15},
+		{expr: "2 * 2 * 2", want: 8},
+		{expr: "100 - 1 - 1 - 1", want: 97},
+		{expr: "   1   +   1   ", want: 2},
+		{expr: "0", want: 0},
+		{expr: "0.1 + 0.2", want: 0.3},
+		{expr: "-0", want: 0},
+		{expr: "1 / 3 * 3", want: 1},
+		{expr: "2 - 3 * 4", want: -10},
+		{expr: "(2 - 3) * 4", want: -4},
+		{expr: "", wantErr: "position 0"},
+		{expr: "   ", wantErr: "position 3"},
+		{expr: "1 +", wantErr: "position 3"},
+		{expr: "+1", wantErr: "position 0"},
+		{expr: "1 2", wantErr: "position 2"},
+		{expr: "(1 + 2", wantErr: "position 6"},
+		{expr: "1 + 2)", wantErr: "position 5"},
+		{expr: "()", wantErr: "position 1"},
+		{expr: "1 / 0", wantErr: "position 2"},
+		{expr: "1 / (2 - 2)", wantErr: "position 2"},
+		{expr: "1 $ 2", wantErr: "position 2"},
+		{expr: "1..2", wantErr: "position 0"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			got, err := evaluateExpression(tc.expr)
+			if tc.wantErr != "" {
+				if err == nil {
+					t.Fatalf("evaluateExpression(%q) = %v, want error containing %q", tc.expr, got, tc.wantErr)
+				}
+				if !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("evaluateExpression(%q) error = %q, want it to contain %q", tc.expr, err.Error(), tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("evaluateExpression(%q) unexpected error: %v", tc.expr, err)
+			}
+			if math.Abs(got-tc.want) > 1e-9 {
+				t.Fatalf("evaluateExpression(%q) = %v, want %v", tc.expr, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateExpression_OverflowToInfinityIsAnError(t *testing.T) {
+	// The tokenizer doesn't support exponent notation, so build a chain of
+	// multiplications that overflows float64 out of plain decimal literals.
+	expr := strings.TrimSuffix(strings.Repeat("1000000000 * ", 40), " * ")
+
+	_, err := evaluateExpression(expr)
+	if err == nil {
+		t.Fatalf("evaluateExpression(%q) returned no error for an overflowing result", expr)
+	}
+	if !strings.Contains(err.Error(), "not a finite number") {
+		t.Errorf("error = %q, want it to mention a non-finite result", err.Error())
+	}
+}
+
+func evaluateRequest(expr string) mcp.CallToolRequest {
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "evaluate"
+	req.Params.Arguments = map[string]any{"expression": expr}
+	return req
+}
+
+func TestHandleEvaluateTool_ReturnsTheComputedResult(t *testing.T) {
+	res, err := handleEvaluateTool(context.Background(), evaluateRequest("(3 + 4) * 2 / 7"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text, ok := res.Content[0].(mcp.TextContent)
+	if !ok || text.Text != "2" {
+		t.Errorf("content = %v, want \"2\"", res.Content)
+	}
+}
+
+func TestHandleEvaluateTool_SyntaxErrorIsAToolResultError(t *testing.T) {
+	res, err := handleEvaluateTool(context.Background(), evaluateRequest("1 +"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsError {
+		t.Fatalf("expected an isError result for a syntax error")
+	}
+}
+
+func TestHandleEvaluateTool_OverLengthLimitIsInvalidParams(t *testing.T) {
+	old := maxExpressionLength
+	maxExpressionLength = 5
+	defer func() { maxExpressionLength = old }()
+
+	_, err := handleEvaluateTool(context.Background(), evaluateRequest("1 + 1 + 1 + 1"))
+	pe, ok := err.(*protocolError)
+	if !ok {
+		t.Fatalf("err = %T, want *protocolError", err)
+	}
+	if pe.Code() != mcp.INVALID_PARAMS {
+		t.Errorf("code = %d, want %d", pe.Code(), mcp.INVALID_PARAMS)
+	}
+}
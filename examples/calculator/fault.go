@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// faultSpecFlag is the --fault flag's raw value, parsed by parseFaultSpec
+// before serve starts. Empty (the default) means faultOutboundResponse is
+// always a no-op.
+var faultSpecFlag = ""
+
+// faultIncludeInit is --fault-include-init: by default initialize is
+// exempt from fault injection (a client that can't even complete the
+// handshake can't exercise the retry/timeout logic --fault exists to
+// test), but this flag lets a caller opt into faulting it too.
+var faultIncludeInit = false
+
+// faultKind identifies what parseFaultSpec's --fault flag asks the
+// transport layer to do to a matching outbound response.
+type faultKind int
+
+const (
+	faultDrop faultKind = iota
+	faultDelay
+	faultTruncate
+)
+
+// faultSpec is --fault, parsed: every N-th outbound response (matching
+// Every) is dropped, delayed by Delay, or truncated, depending on Kind.
+type faultSpec struct {
+	Kind  faultKind
+	Every int
+	Delay time.Duration
+}
+
+// fault is the parsed faultSpecFlag, set once by main before serve
+// starts; a zero Every means parseFaultSpec was never called or
+// faultSpecFlag was empty, and faultOutboundResponse is a no-op.
+var fault faultSpec
+
+// faultCounterKey is the sessionStore key fault.go uses for its
+// per-session outbound-response counter, the same pattern counter.go
+// uses for counterKey — "shared across a session" means this count, not
+// a process-global one, so two concurrently connected sessions each see
+// their own 5th, 10th, 15th, ... response faulted independently.
+const faultCounterKey = "fault_response_count"
+
+// parseFaultSpec parses --fault's "drop:5" / "delay:3:2s" /
+// "truncate:7" syntax into a faultSpec. An empty spec string is valid and
+// produces the zero faultSpec (Every == 0, so faultOutboundResponse never
+// matches).
+func parseFaultSpec(spec string) (faultSpec, error) {
+	if spec == "" {
+		return faultSpec{}, nil
+	}
+	parts := strings.Split(spec, ":")
+	if len(parts) < 2 {
+		return faultSpec{}, fmt.Errorf("--fault %q: expected kind:N or kind:N:duration", spec)
+	}
+
+	every, err := strconv.Atoi(parts[1])
+	if err != nil || every <= 0 {
+		return faultSpec{}, fmt.Errorf("--fault %q: N must be a positive integer", spec)
+	}
+
+	switch parts[0] {
+	case "drop":
+		return faultSpec{Kind: faultDrop, Every: every}, nil
+	case "truncate":
+		return faultSpec{Kind: faultTruncate, Every: every}, nil
+	case "delay":
+		if len(parts) != 3 {
+			return faultSpec{}, fmt.Errorf("--fault %q: delay needs a duration, e.g. delay:3:2s", spec)
+		}
+		delay, err := time.ParseDuration(parts[2])
+		if err != nil {
+			return faultSpec{}, fmt.Errorf("--fault %q: invalid duration: %w", spec, err)
+		}
+		return faultSpec{Kind: faultDelay, Every: every, Delay: delay}, nil
+	default:
+		return faultSpec{}, fmt.Errorf("--fault %q: unknown kind %q, want drop, delay, or truncate", spec, parts[0])
+	}
+}
+
+// faultOutboundResponse applies fault to b, the outbound bytes for one
+// response belonging to sessionID, identified by method (so
+// "initialize" can be exempted per faultIncludeInit). It returns the
+// (possibly mutated) bytes to send, and whether to send anything at all
+// — false means the caller must not write a response, the "drop" case.
+//
+// This only ever runs from the same few places stdio.go and ws.go
+// already own their own outbound dispatch end to end (mirroring
+// replay.go's approach to the same problem): there's no single seam that
+// sees every transport's outbound bytes, since the sse/http/both
+// transports hand their response bytes to mcp-go's own HTTP handler
+// rather than this fixture's code. Fault injection for those transports
+// is therefore not implemented — a documented limitation, not a
+// silently-dropped feature.
+func faultOutboundResponse(sessionID, method string, b []byte) ([]byte, bool) {
+	if fault.Every == 0 {
+		return b, true
+	}
+	if method == "initialize" && !faultIncludeInit {
+		return b, true
+	}
+
+	raw, _ := sessionStore.Get(sessionID, faultCounterKey)
+	count, _ := strconv.Atoi(raw)
+	count++
+	sessionStore.Set(sessionID, faultCounterKey, strconv.Itoa(count))
+
+	if count%fault.Every != 0 {
+		return b, true
+	}
+
+	switch fault.Kind {
+	case faultDrop:
+		log.Printf("fault: dropping response #%d for session %s (method %s)", count, sessionID, method)
+		return nil, false
+	case faultDelay:
+		log.Printf("fault: delaying response #%d for session %s (method %s) by %s", count, sessionID, method, fault.Delay)
+		time.Sleep(fault.Delay)
+		return b, true
+	case faultTruncate:
+		log.Printf("fault: truncating response #%d for session %s (method %s)", count, sessionID, method)
+		if len(b) == 0 {
+			return b, true
+		}
+		return b[:len(b)/2], true
+	default:
+		return b, true
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func withTestFault(t *testing.T, spec faultSpec, includeInit bool) {
+	t.Helper()
+	origSpec, origIncludeInit := fault, faultIncludeInit
+	fault, faultIncludeInit = spec, includeInit
+	t.Cleanup(func() { fault, faultIncludeInit = origSpec, origIncludeInit })
+}
+
+func TestParseFaultSpec(t *testing.T) {
+	cases := []struct {
+		spec    string
+		want    faultSpec
+		wantErr bool
+	}{
+		{spec: "", want: faultSpec{}},
+		{spec: "drop:5", want: faultSpec{Kind: faultDrop, Every: 5}},
+		{spec: "truncate:7", want: faultSpec{Kind: faultTruncate, Every: 7}},
+		{spec: "delay:3:2s", want: faultSpec{Kind: faultDelay, Every: 3, Delay: 2 * time.Second}},
+		{spec: "delay:3", wantErr: true},
+		{spec: "bogus:5", wantErr: true},
+		{spec: "drop:0", wantErr: true},
+		{spec: "drop:-1", wantErr: true},
+		{spec: "drop", wantErr: true},
+	}
+	for _, c := range cases {
+		got, err := parseFaultSpec(c.spec)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("parseFaultSpec(%q): expected an error, got %v", c.spec, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseFaultSpec(%q): %v", c.spec, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("parseFaultSpec(%q) = %+v, want %+v", c.spec, got, c.want)
+		}
+	}
+}
+
+func TestFaultOutboundResponse_DropsOnlyTheNthResponse(t *testing.T) {
+	const session = "fault-drop-session"
+	t.Cleanup(func() { sessionStore.DeleteSession(session) })
+	withTestFault(t, faultSpec{Kind: faultDrop, Every: 5}, false)
+
+	for i := 1; i <= 6; i++ {
+		b, ok := faultOutboundResponse(session, "tools/call", []byte(fmt.Sprintf(`{"id":%d}`, i)))
+		if i == 5 {
+			if ok {
+				t.Errorf("response #%d: ok = true, want the 5th response dropped", i)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("response #%d: ok = false, want it delivered", i)
+		}
+		if b == nil {
+			t.Errorf("response #%d: bytes = nil, want the original response", i)
+		}
+	}
+}
+
+func TestFaultOutboundResponse_ExemptsInitializeUnlessIncludeInit(t *testing.T) {
+	const session = "fault-init-session"
+	t.Cleanup(func() { sessionStore.DeleteSession(session) })
+	withTestFault(t, faultSpec{Kind: faultDrop, Every: 1}, false)
+
+	b, ok := faultOutboundResponse(session, "initialize", []byte(`{"id":1}`))
+	if !ok || string(b) != `{"id":1}` {
+		t.Fatalf("initialize response faulted with -fault-include-init unset: ok=%v b=%s", ok, b)
+	}
+
+	withTestFault(t, faultSpec{Kind: faultDrop, Every: 1}, true)
+	_, ok = faultOutboundResponse(session, "initialize", []byte(`{"id":1}`))
+	if ok {
+		t.Fatal("initialize response not faulted with -fault-include-init set")
+	}
+}
+
+func TestFaultOutboundResponse_TruncateHalvesThePayload(t *testing.T) {
+	const session = "fault-truncate-session"
+	t.Cleanup(func() { sessionStore.DeleteSession(session) })
+	withTestFault(t, faultSpec{Kind: faultTruncate, Every: 1}, false)
+
+	original := []byte(`{"jsonrpc":"2.0","id":1,"result":{}}`)
+	b, ok := faultOutboundResponse(session, "tools/call", original)
+	if !ok {
+		t.Fatal("ok = false, want the truncated response still delivered")
+	}
+	if len(b) != len(original)/2 {
+		t.Errorf("truncated length = %d, want %d", len(b), len(original)/2)
+	}
+}
+
+func TestFaultOutboundResponse_DelaySleepsAndPreservesBytes(t *testing.T) {
+	const session = "fault-delay-session"
+	t.Cleanup(func() { sessionStore.DeleteSession(session) })
+	withTestFault(t, faultSpec{Kind: faultDelay, Every: 1, Delay: 50 * time.Millisecond}, false)
+
+	original := []byte(`{"jsonrpc":"2.0","id":42,"result":{}}`)
+	start := time.Now()
+	b, ok := faultOutboundResponse(session, "tools/call", original)
+	elapsed := time.Since(start)
+
+	if !ok {
+		t.Fatal("ok = false, want the delayed response still delivered")
+	}
+	if string(b) != string(original) {
+		t.Errorf("delayed response bytes = %s, want unchanged %s", b, original)
+	}
+	if elapsed < 50*time.Millisecond {
+		t.Errorf("faultOutboundResponse returned after %s, want at least the 50ms delay", elapsed)
+	}
+}
+
+// TestServeStdioTolerant_FaultDropsOnlyTheConfiguredResponse runs an
+// initialize handshake (exempt by default) followed by six tools/call
+// requests with -fault drop:5, and asserts the 4th and 6th calculate
+// responses arrive while the 5th never does.
+func TestServeStdioTolerant_FaultDropsOnlyTheConfiguredResponse(t *testing.T) {
+	withTestFault(t, faultSpec{Kind: faultDrop, Every: 5}, false)
+
+	origStdin, origStdout := os.Stdin, os.Stdout
+	defer func() { os.Stdin, os.Stdout = origStdin, origStdout }()
+
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdin): %v", err)
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdout): %v", err)
+	}
+	os.Stdin, os.Stdout = stdinR, stdoutW
+
+	srv := newTestServer()
+	done := make(chan error, 1)
+	go func() { done <- serveStdioTolerant(context.Background(), srv) }()
+
+	writeLine(t, stdinW, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`)
+	for i := 2; i <= 7; i++ {
+		writeLine(t, stdinW, fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"calculate","arguments":{"operation":"add","x":1,"y":2}}}`, i))
+	}
+
+	scanner := bufio.NewScanner(stdoutR)
+	seenIDs := map[float64]bool{}
+	for i := 0; i < 6; i++ {
+		if !scanner.Scan() {
+			break
+		}
+		var resp map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("stdout line %q is not valid JSON: %v", scanner.Text(), err)
+		}
+		if id, ok := resp["id"].(float64); ok {
+			seenIDs[id] = true
+		}
+	}
+
+	stdinW.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serveStdioTolerant returned %v after EOF, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("serveStdioTolerant did not return after stdin closed")
+	}
+	stdoutW.Close()
+
+	if seenIDs[6] {
+		t.Error("response id 6 (the 5th tools/call, per drop:5) arrived, want it dropped")
+	}
+	if !seenIDs[5] {
+		t.Error("response id 5 (the 4th tools/call) did not arrive, want it delivered")
+	}
+	if !seenIDs[7] {
+		t.Error("response id 7 (the 6th tools/call) did not arrive, want it delivered")
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxResponseBytes caps generate_text's size_bytes argument, so a single
+// call can't be used to exhaust memory or bandwidth. main wires it to
+// --max-response-bytes.
+var maxResponseBytes = 16 * 1024 * 1024
+
+// generateTextSeed is fixed rather than time-derived so two calls with the
+// same size_bytes always produce byte-identical output; framing/backpressure
+// tests depend on being able to predict the payload instead of just its
+// length.
+const generateTextSeed = 1
+
+const generateTextAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789 "
+
+// registerGenerateTextTool wires up generate_text, which returns a
+// deterministic pseudo-random ASCII payload of an exact byte size, split
+// across one or more text content blocks. It exists to give MCP clients
+// something concrete to frame and apply backpressure against over stdio,
+// SSE, and websocket.
+func registerGenerateTextTool(s *server.MCPServer) {
+	tool := mcp.NewTool("generate_text",
+		mcp.WithDescription("Generate a deterministic ASCII payload of an exact byte size, for framing and backpressure tests"),
+		mcp.WithNumber("size_bytes", mcp.Required(), mcp.Description("Exact total size of the generated text, in bytes"), mcp.Min(0)),
+		mcp.WithNumber("chunks", mcp.Description("Number of text content blocks to split the payload across (default 1)"), mcp.Min(1)),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Generate Text",
+			ReadOnlyHint:    true,
+			DestructiveHint: false,
+			IdempotentHint:  true,
+		}),
+	)
+	addToolWithMiddleware(s, tool, handleGenerateTextTool, recoverMiddleware)
+}
+
+type generateTextArgs struct {
+	SizeBytes float64 `json:"size_bytes"`
+	Chunks    float64 `json:"chunks" mcp:"optional"`
+}
+
+func handleGenerateTextTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var args generateTextArgs
+	if err := bindArguments(request, &args); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	size := int(args.SizeBytes)
+	if size < 0 {
+		return nil, invalidParamsf("size_bytes must be non-negative, got %d", size)
+	}
+	if size > maxResponseBytes {
+		return toolResultErrorf("size_bytes %d exceeds the limit of %d", size, maxResponseBytes), nil
+	}
+
+	chunks := 1
+	if args.Chunks > 0 {
+		chunks = int(args.Chunks)
+	}
+	if chunks < 1 {
+		return nil, invalidParamsf("chunks must be at least 1, got %d", chunks)
+	}
+	if chunks > size && size > 0 {
+		return nil, invalidParamsf("chunks (%d) cannot exceed size_bytes (%d)", chunks, size)
+	}
+
+	text := generateDeterministicText(size)
+
+	return &mcp.CallToolResult{Content: splitIntoTextContent(text, chunks)}, nil
+}
+
+// generateDeterministicText returns n bytes of pseudo-random ASCII drawn
+// from a freshly-seeded generator, so the same n always yields the same
+// text regardless of what else has called math/rand in this process.
+func generateDeterministicText(n int) string {
+	rng := rand.New(rand.NewSource(generateTextSeed))
+	out := make([]byte, n)
+	for i := range out {
+		out[i] = generateTextAlphabet[rng.Intn(len(generateTextAlphabet))]
+	}
+	return string(out)
+}
+
+// splitIntoTextContent divides text into chunks content blocks as evenly
+// as possible; the total length across blocks always equals len(text).
+func splitIntoTextContent(text string, chunks int) []mcp.Content {
+	if chunks > len(text) {
+		chunks = len(text)
+	}
+	if chunks < 1 {
+		chunks = 1
+	}
+
+	content := make([]mcp.Content, 0, chunks)
+	base := len(text) / chunks
+	remainder := len(text) % chunks
+
+	start := 0
+	for i := 0; i < chunks; i++ {
+		size := base
+		if i < remainder {
+			size++
+		}
+		content = append(content, mcp.TextContent{Type: "text", Text: text[start : start+size]})
+		start += size
+	}
+	return content
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func generateTextRequest(sizeBytes, chunks float64) mcp.CallToolRequest {
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "generate_text"
+	args := map[string]any{"size_bytes": sizeBytes}
+	if chunks > 0 {
+		args["chunks"] = chunks
+	}
+	req.Params.Arguments = args
+	return req
+}
+
+func totalContentBytes(t *testing.T, content []mcp.Content) int {
+	t.Helper()
+
+	total := 0
+	for _, c := range content {
+		text, ok := c.(mcp.TextContent)
+		if !ok {
+			t.Fatalf("content block = %T, want mcp.TextContent", c)
+		}
+		total += len(text.Text)
+	}
+	return total
+}
+
+func TestHandleGenerateTextTool_ExactByteCountsAtRepresentativeSizes(t *testing.T) {
+	for _, size := range []int{1024, 1024 * 1024} {
+		res, err := handleGenerateTextTool(context.Background(), generateTextRequest(float64(size), 1))
+		if err != nil {
+			t.Fatalf("size %d: unexpected error: %v", size, err)
+		}
+		if got := totalContentBytes(t, res.Content); got != size {
+			t.Errorf("size %d: got %d total bytes", size, got)
+		}
+	}
+}
+
+func TestHandleGenerateTextTool_SplitsAcrossChunksWithoutLosingBytes(t *testing.T) {
+	const size = 1024 * 1024
+	res, err := handleGenerateTextTool(context.Background(), generateTextRequest(size, 7))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Content) != 7 {
+		t.Fatalf("len(Content) = %d, want 7", len(res.Content))
+	}
+	if got := totalContentBytes(t, res.Content); got != size {
+		t.Errorf("got %d total bytes, want %d", got, size)
+	}
+}
+
+func TestHandleGenerateTextTool_IsDeterministicAcrossCalls(t *testing.T) {
+	first, err := handleGenerateTextTool(context.Background(), generateTextRequest(2048, 3))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := handleGenerateTextTool(context.Background(), generateTextRequest(2048, 3))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := range first.Content {
+		a := first.Content[i].(mcp.TextContent).Text
+		b := second.Content[i].(mcp.TextContent).Text
+		if a != b {
+			t.Fatalf("chunk %d differs between calls with identical arguments", i)
+		}
+	}
+}
+
+func TestHandleGenerateTextTool_AtTheConfiguredCapSucceeds(t *testing.T) {
+	old := maxResponseBytes
+	maxResponseBytes = 4096
+	defer func() { maxResponseBytes = old }()
+
+	res, err := handleGenerateTextTool(context.Background(), generateTextRequest(4096, 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := totalContentBytes(t, res.Content); got != 4096 {
+		t.Errorf("got %d total bytes, want 4096", got)
+	}
+}
+
+func TestHandleGenerateTextTool_OverTheCapIsToolDomainError(t *testing.T) {
+	old := maxResponseBytes
+	maxResponseBytes = 4096
+	defer func() { maxResponseBytes = old }()
+
+	res, err := handleGenerateTextTool(context.Background(), generateTextRequest(4097, 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsError {
+		t.Errorf("IsError = false, want true for a size_bytes over the cap")
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerGetHistoryEntryTool wires up get_history_entry, which embeds a
+// calc://history/{index} resource directly in a tool result instead of
+// making the caller follow up with a separate resources/read. Unlike
+// fetch_document (embedded_resource.go), which only covers the static
+// fetchableResources map, calc://history/{index} is a template match, so
+// this goes straight through readHistoryEntry (resources.go) the same way
+// handleHistoryTemplate does, keeping the embedded contents and a plain
+// resources/read of the same index from ever disagreeing.
+func registerGetHistoryEntryTool(s *server.MCPServer) {
+	tool := mcp.NewTool("get_history_entry",
+		mcp.WithDescription("Fetch a calculation history entry, embedded as a resource content block"),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("1-based history index"), mcp.Min(1)),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Get History Entry",
+			ReadOnlyHint:    true,
+			DestructiveHint: false,
+			IdempotentHint:  true,
+		}),
+	)
+	addToolWithMiddleware(s, tool, handleGetHistoryEntryTool, recoverMiddleware)
+}
+
+type getHistoryEntryArgs struct {
+	Index float64 `json:"index"`
+}
+
+func handleGetHistoryEntryTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var args getHistoryEntryArgs
+	if err := bindArguments(request, &args); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	index := int(args.Index)
+	uri := historyURI(index)
+
+	contents, err := readHistoryEntry(uri, index)
+	if err != nil {
+		// The index simply doesn't exist yet; a well-formed request hitting
+		// a tool-domain gap, not a malformed one.
+		return toolResultErrorf("%s", err.Error()), nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{Type: "text", Text: fmt.Sprintf("calculation #%d", index)},
+			mcp.EmbeddedResource{Type: "resource", Resource: contents[0]},
+		},
+	}, nil
+}
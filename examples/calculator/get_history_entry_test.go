@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func getHistoryEntryRequest(index float64) mcp.CallToolRequest {
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "get_history_entry"
+	req.Params.Arguments = map[string]any{"index": index}
+	return req
+}
+
+func TestHandleGetHistoryEntryTool_EmbedsTheSameContentsAResourceReadWould(t *testing.T) {
+	recordCalculation("add", 1, 2, 3, time.Now())
+	index := recordCalculation("multiply", 3, 4, 12, time.Now())
+
+	res, err := handleGetHistoryEntryTool(context.Background(), getHistoryEntryRequest(float64(index)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Content) != 2 {
+		t.Fatalf("len(Content) = %d, want 2", len(res.Content))
+	}
+
+	embedded, ok := res.Content[1].(mcp.EmbeddedResource)
+	if !ok {
+		t.Fatalf("Content[1] = %T, want mcp.EmbeddedResource", res.Content[1])
+	}
+	text, ok := embedded.Resource.(mcp.TextResourceContents)
+	if !ok {
+		t.Fatalf("Resource = %T, want mcp.TextResourceContents", embedded.Resource)
+	}
+
+	wantURI := historyURI(index)
+	direct, err := readHistoryEntry(wantURI, index)
+	if err != nil {
+		t.Fatalf("readHistoryEntry: %v", err)
+	}
+	wantText, ok := direct[0].(mcp.TextResourceContents)
+	if !ok {
+		t.Fatalf("readHistoryEntry returned %T, want mcp.TextResourceContents", direct[0])
+	}
+
+	if text.URI != wantURI {
+		t.Errorf("URI = %q, want %q", text.URI, wantURI)
+	}
+	if text.MIMEType != wantText.MIMEType {
+		t.Errorf("MIMEType = %q, want %q", text.MIMEType, wantText.MIMEType)
+	}
+	if text.Text != wantText.Text {
+		t.Errorf("Text = %q, want %q", text.Text, wantText.Text)
+	}
+}
+
+func TestHandleGetHistoryEntryTool_OutOfRangeIndexIsToolDomainError(t *testing.T) {
+	res, err := handleGetHistoryEntryTool(context.Background(), getHistoryEntryRequest(99999))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsError {
+		t.Errorf("IsError = false, want true")
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// serverName and serverVersion mirror the values passed to
+// server.NewMCPServer, so /healthz and /readyz can report them without a
+// second source of truth drifting out of sync.
+const (
+	serverName    = "Calculator"
+	serverVersion = "0.1.0"
+)
+
+// startedAt is set once, at process start, so /healthz can report uptime.
+var startedAt = time.Now()
+
+// ready flips to true once main has finished registering every tool,
+// resource, and prompt; readyzHandler reports 503 until then so a
+// docker-compose healthcheck (or test harness) doesn't start hammering the
+// MCP endpoints against a server that's still wiring itself up.
+var ready atomic.Bool
+
+func markReady() { ready.Store(true) }
+
+type healthStatus struct {
+	Name    string  `json:"name"`
+	Version string  `json:"version"`
+	Uptime  float64 `json:"uptime_seconds"`
+}
+
+// mountHealthEndpoints registers /healthz and /readyz on mux, the same mux
+// the sse/http/both transports serve their MCP endpoints from. Neither
+// endpoint touches the MCP session machinery, so polling them never
+// requires (or creates) an initialized session.
+func mountHealthEndpoints(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", handleHealthz)
+	mux.HandleFunc("/readyz", handleReadyz)
+}
+
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeHealthStatus(w, http.StatusOK)
+}
+
+func handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if !ready.Load() {
+		writeHealthStatus(w, http.StatusServiceUnavailable)
+		return
+	}
+	writeHealthStatus(w, http.StatusOK)
+}
+
+func writeHealthStatus(w http.ResponseWriter, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(healthStatus{
+		Name:    serverName,
+		Version: serverVersion,
+		Uptime:  time.Since(startedAt).Seconds(),
+	})
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleHealthz_AlwaysReturnsOK(t *testing.T) {
+	mux := http.NewServeMux()
+	mountHealthEndpoints(mux)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("GET /healthz: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var status healthStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if status.Name != serverName || status.Version != serverVersion {
+		t.Errorf("status = %+v, want name=%q version=%q", status, serverName, serverVersion)
+	}
+}
+
+func TestHandleReadyz_ReflectsReadyFlag(t *testing.T) {
+	oldReady := ready.Load()
+	defer ready.Store(oldReady)
+
+	mux := http.NewServeMux()
+	mountHealthEndpoints(mux)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ready.Store(false)
+	resp, err := http.Get(srv.URL + "/readyz")
+	if err != nil {
+		t.Fatalf("GET /readyz: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status before ready = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+
+	ready.Store(true)
+	resp, err = http.Get(srv.URL + "/readyz")
+	if err != nil {
+		t.Fatalf("GET /readyz: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status after ready = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const historyTemplate = "calc://history/{index}"
+
+// resourceNotFoundCode is the JSON-RPC error code MCP reserves for "the
+// resource URI parsed fine but nothing exists there" (-32002), as opposed
+// to mcp.INVALID_PARAMS for a URI that doesn't parse at all.
+const resourceNotFoundCode = -32002
+
+// calcEntry records one successful calculate call so it can be replayed as
+// an MCP resource.
+type calcEntry struct {
+	Operation string    `json:"operation"`
+	X         float64   `json:"x"`
+	Y         float64   `json:"y"`
+	Result    float64   `json:"result"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// history is the in-memory log of calculate calls, guarded by mu since SSE
+// (and stdio.go, one goroutine per line) can dispatch concurrent requests.
+var history = struct {
+	mu      sync.Mutex
+	entries []calcEntry
+}{}
+
+func recordCalculation(op string, x, y, result float64, now time.Time) int {
+	history.mu.Lock()
+	defer history.mu.Unlock()
+
+	history.entries = append(history.entries, calcEntry{
+		Operation: op,
+		X:         x,
+		Y:         y,
+		Result:    result,
+		Timestamp: now,
+	})
+	return len(history.entries)
+}
+
+// historyEntry returns the 1-indexed entry and whether it exists.
+func historyEntry(index int) (calcEntry, bool) {
+	history.mu.Lock()
+	defer history.mu.Unlock()
+
+	if index < 1 || index > len(history.entries) {
+		return calcEntry{}, false
+	}
+	return history.entries[index-1], true
+}
+
+func historyLen() int {
+	history.mu.Lock()
+	defer history.mu.Unlock()
+	return len(history.entries)
+}
+
+// resetHistory clears the calculation history log and removes every
+// calc://history/N resource it backs, since a resource whose handler would
+// now report "no calculation at index N" shouldn't linger in
+// resources/list.
+func resetHistory(s *server.MCPServer) {
+	history.mu.Lock()
+	n := len(history.entries)
+	history.entries = nil
+	history.mu.Unlock()
+
+	for i := 1; i <= n; i++ {
+		s.RemoveResource(historyURI(i))
+	}
+}
+
+// registerResetHistoryTool wires up reset_history, annotated destructive
+// and non-idempotent (as is chaos.go's simulate_crash): unlike calculate,
+// calling it twice isn't a no-op (the second call just finds nothing left
+// to clear), and it discards data the caller can't get back.
+func registerResetHistoryTool(s *server.MCPServer) {
+	addToolWithMiddleware(s, newResetHistoryTool(), handleResetHistoryTool(s), recoverMiddleware)
+}
+
+func newResetHistoryTool() mcp.Tool {
+	return mcp.NewTool("reset_history",
+		mcp.WithDescription("Clear the calculation history and remove its history resources"),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Reset History",
+			ReadOnlyHint:    false,
+			DestructiveHint: true,
+			IdempotentHint:  false,
+		}),
+	)
+}
+
+func handleResetHistoryTool(s *server.MCPServer) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		cleared := historyLen()
+		resetHistory(s)
+
+		return mcp.NewToolResultText(fmt.Sprintf("cleared %d history entries", cleared)), nil
+	}
+}
+
+func historyURI(index int) string {
+	return fmt.Sprintf("calc://history/%d", index)
+}
+
+func marshalEntry(e calcEntry) (string, error) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func registerHistoryTemplate(s *server.MCPServer) {
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate(historyTemplate, "Calculation history lookup", mcp.WithTemplateMIMEType("application/json")),
+		recoverResourceHandler(historyTemplate, handleHistoryTemplate),
+	)
+}
+
+// resourceError carries a specific JSON-RPC error code through a handler's
+// plain `error` return so the transport layer can report it verbatim
+// instead of falling back to a generic internal-error code.
+type resourceError struct {
+	code    int
+	message string
+}
+
+func (e *resourceError) Error() string { return e.message }
+func (e *resourceError) Code() int     { return e.code }
+
+func handleHistoryTemplate(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	vars, ok := matchURITemplate(historyTemplate, request.Params.URI)
+	if !ok {
+		return nil, &resourceError{code: mcp.INVALID_PARAMS, message: fmt.Sprintf("URI %q does not match %q", request.Params.URI, historyTemplate)}
+	}
+
+	index, err := strconv.Atoi(vars["index"])
+	if err != nil {
+		return nil, &resourceError{code: mcp.INVALID_PARAMS, message: fmt.Sprintf("index %q is not a number", vars["index"])}
+	}
+
+	contents, err := readHistoryEntry(request.Params.URI, index)
+	if err != nil {
+		return nil, &resourceError{code: resourceNotFoundCode, message: err.Error()}
+	}
+
+	return contents, nil
+}
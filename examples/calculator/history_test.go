@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestRecordCalculation_AssignsSequentialIndexes(t *testing.T) {
+	before := historyLen()
+
+	i1 := recordCalculation("add", 1, 2, 3, time.Now())
+	i2 := recordCalculation("add", 2, 2, 4, time.Now())
+
+	if i2 != i1+1 {
+		t.Errorf("expected sequential indexes, got %d then %d", i1, i2)
+	}
+	if historyLen() != before+2 {
+		t.Errorf("historyLen() = %d, want %d", historyLen(), before+2)
+	}
+}
+
+func TestHandleHistoryLatestResource(t *testing.T) {
+	recordCalculation("multiply", 4, 5, 20, time.Now())
+
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = "calc://history/latest"
+
+	contents, err := handleHistoryLatestResource(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text, ok := contents[0].(mcp.TextResourceContents)
+	if !ok {
+		t.Fatalf("expected TextResourceContents, got %T", contents[0])
+	}
+
+	if !strings.Contains(text.Text, `"operation":"multiply"`) {
+		t.Errorf("latest resource body = %q, want it to mention multiply", text.Text)
+	}
+}
+
+func TestHandleHistoryTemplate_ValidIndex(t *testing.T) {
+	index := recordCalculation("add", 1, 1, 2, time.Now())
+
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = historyURI(index)
+
+	contents, err := handleHistoryTemplate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(contents) != 1 {
+		t.Fatalf("expected one content item, got %d", len(contents))
+	}
+}
+
+func TestHandleHistoryTemplate_OutOfRange(t *testing.T) {
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = "calc://history/999999"
+
+	_, err := handleHistoryTemplate(context.Background(), req)
+	if err == nil {
+		t.Fatal("expected an error for an out-of-range index")
+	}
+
+	re, ok := err.(*resourceError)
+	if !ok || re.Code() != resourceNotFoundCode {
+		t.Errorf("error = %v, want a resourceError with code %d", err, resourceNotFoundCode)
+	}
+}
+
+func TestHandleResetHistoryTool_ClearsTheHistoryLog(t *testing.T) {
+	recordCalculation("add", 1, 1, 2, time.Now())
+	recordCalculation("add", 2, 2, 4, time.Now())
+
+	s := server.NewMCPServer("Calculator", "0.1.0", server.WithResourceCapabilities(true, true))
+	res, err := handleResetHistoryTool(s)(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.IsError {
+		t.Fatalf("expected a successful result, got %v", res)
+	}
+	if historyLen() != 0 {
+		t.Errorf("historyLen() = %d after reset, want 0", historyLen())
+	}
+
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = "calc://history/latest"
+	if _, err := handleHistoryLatestResource(context.Background(), req); err == nil {
+		t.Error("expected calc://history/latest to report no calculations after reset")
+	}
+}
+
+func TestNewResetHistoryTool_IsAnnotatedDestructiveAndNotIdempotent(t *testing.T) {
+	tool := newResetHistoryTool()
+
+	if !tool.Annotations.DestructiveHint {
+		t.Error("reset_history should be annotated DestructiveHint: true")
+	}
+	if tool.Annotations.IdempotentHint {
+		t.Error("reset_history should be annotated IdempotentHint: false")
+	}
+}
+
+func TestHandleHistoryTemplate_NonNumericIndex(t *testing.T) {
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = "calc://history/not-a-number"
+
+	_, err := handleHistoryTemplate(context.Background(), req)
+	if err == nil {
+		t.Fatal("expected an error for a non-numeric index")
+	}
+
+	re, ok := err.(*resourceError)
+	if !ok || re.Code() != mcp.INVALID_PARAMS {
+		t.Errorf("error = %v, want a resourceError with code INVALID_PARAMS", err)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func newTestServer() *server.MCPServer {
+	hooks := &server.Hooks{}
+	registerSessionRegistryHooks(hooks)
+
+	s := server.NewMCPServer("Calculator", "0.1.0", server.WithLogging(), server.WithHooks(hooks))
+	tool := mcp.NewTool("calculate",
+		mcp.WithDescription("Perform basic arithmetic operations"),
+		mcp.WithString("operation", mcp.Required(), mcp.Enum("add", "subtract", "multiply", "divide")),
+		mcp.WithNumber("x", mcp.Required()),
+		mcp.WithNumber("y", mcp.Required()),
+	)
+	s.AddTool(tool, handle_calculate_tool)
+	return s
+}
+
+func postJSONRPC(t *testing.T, srv *httptest.Server, sessionID string, body map[string]any) (*http.Response, map[string]any) {
+	t.Helper()
+
+	raw, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("marshal request: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/mcp", bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", sessionID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var decoded map[string]any
+	_ = json.NewDecoder(resp.Body).Decode(&decoded)
+
+	return resp, decoded
+}
+
+// TestStreamableHTTP_InitializeAndCalculate is skipped: mcp-go v0.23.1 has
+// no Streamable HTTP server at all (server.NewStreamableHTTPServer and
+// server.WithEndpointPath don't exist in this version — it ships SSE only,
+// see serveHTTP's doc comment in transport.go). postJSONRPC stays in this
+// file, though no test in this package calls it while this one is skipped.
+func TestStreamableHTTP_InitializeAndCalculate(t *testing.T) {
+	t.Skip("mcp-go v0.23.1 has no Streamable HTTP server; see serveHTTP's doc comment in transport.go")
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerRenderResultTool wires up render_result, used to exercise
+// non-text content (image blocks) in the Anubis client's tools/call
+// handling.
+func registerRenderResultTool(s *server.MCPServer) {
+	tool := mcp.NewTool("render_result",
+		mcp.WithDescription("Perform a calculation and render the result as a PNG swatch alongside the text answer"),
+		mcp.WithString("operation",
+			mcp.Required(),
+			mcp.Description("The operation to perform (add, subtract, multiply, divide)"),
+			mcp.Enum("add", "subtract", "multiply", "divide"),
+		),
+		mcp.WithNumber("x", mcp.Required()),
+		mcp.WithNumber("y", mcp.Required()),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Render Result",
+			ReadOnlyHint:    true,
+			DestructiveHint: false,
+			IdempotentHint:  true,
+		}),
+	)
+	addToolWithMiddleware(s, tool, handleRenderResultTool, recoverMiddleware)
+}
+
+// renderResultPNG draws a single-color 32x32 swatch: green for a
+// non-negative result, red for a negative one. The image itself carries no
+// information a client needs — it exists so tests can assert that image
+// content round-trips alongside text content.
+func renderResultPNG(result float64) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	c := color.RGBA{R: 0x22, G: 0xaa, B: 0x44, A: 0xff}
+	if result < 0 {
+		c = color.RGBA{R: 0xaa, G: 0x22, B: 0x22, A: 0xff}
+	}
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			img.Set(x, y, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func handleRenderResultTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var args calculateArgs
+	if err := bindArguments(request, &args); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result, err := calculate(args.Operation, args.X, args.Y)
+	if err != nil {
+		if pe, ok := err.(*protocolError); ok {
+			return nil, pe
+		}
+		return toolResultErrorf("%s", err.Error()), nil
+	}
+
+	png, err := renderResultPNG(result)
+	if err != nil {
+		return nil, internalErrorf("rendering result: %v", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{Type: "text", Text: fmt.Sprintf("%v", result)},
+			mcp.ImageContent{Type: "image", Data: base64.StdEncoding.EncodeToString(png), MIMEType: "image/png"},
+		},
+	}, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var pngMagic = []byte{0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a}
+
+func TestHandleRenderResultTool_ReturnsTextAndImageContent(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"operation": "add", "x": 2.0, "y": 3.0}
+
+	res, err := handleRenderResultTool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(res.Content) != 2 {
+		t.Fatalf("len(Content) = %d, want 2", len(res.Content))
+	}
+
+	text, ok := res.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("Content[0] = %T, want mcp.TextContent", res.Content[0])
+	}
+	if text.Text != "5" {
+		t.Errorf("text = %q, want %q", text.Text, "5")
+	}
+
+	img, ok := res.Content[1].(mcp.ImageContent)
+	if !ok {
+		t.Fatalf("Content[1] = %T, want mcp.ImageContent", res.Content[1])
+	}
+	if img.MIMEType != "image/png" {
+		t.Errorf("MIMEType = %q, want %q", img.MIMEType, "image/png")
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(img.Data)
+	if err != nil {
+		t.Fatalf("image data did not decode as base64: %v", err)
+	}
+	if !bytes.HasPrefix(raw, pngMagic) {
+		t.Errorf("decoded image did not start with the PNG magic bytes")
+	}
+}
+
+func TestHandleRenderResultTool_DivideByZeroIsToolDomainError(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"operation": "divide", "x": 1.0, "y": 0.0}
+
+	res, err := handleRenderResultTool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsError {
+		t.Errorf("IsError = false, want true")
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// TestInitializeResponse_ReportsInstructionsAndServerInfo confirms
+// server.WithInstructions and the name/version passed to NewMCPServer
+// (this fixture's -server-name/-server-version/-instructions flags, see
+// main's serverOpts construction) surface unchanged in the initialize
+// response.
+func TestInitializeResponse_ReportsInstructionsAndServerInfo(t *testing.T) {
+	s := server.NewMCPServer("test-server", "9.9.9", server.WithInstructions("Use the calculate tool for arithmetic."))
+
+	req, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "initialize",
+		"params": map[string]any{
+			"protocolVersion": "2025-06-18",
+			"capabilities":    map[string]any{},
+			"clientInfo":      map[string]any{"name": "test", "version": "0.0.1"},
+		},
+	})
+	resp := s.HandleMessage(context.Background(), req)
+	b, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("marshaling initialize response: %v", err)
+	}
+
+	var envelope struct {
+		Result struct {
+			Instructions string `json:"instructions"`
+			ServerInfo   struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+			} `json:"serverInfo"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		t.Fatalf("unmarshaling initialize response: %v", err)
+	}
+
+	if envelope.Result.Instructions != "Use the calculate tool for arithmetic." {
+		t.Errorf("instructions = %q, want the configured text", envelope.Result.Instructions)
+	}
+	if envelope.Result.ServerInfo.Name != "test-server" || envelope.Result.ServerInfo.Version != "9.9.9" {
+		t.Errorf("serverInfo = %+v, want name=test-server version=9.9.9", envelope.Result.ServerInfo)
+	}
+}
+
+// TestInitializeResponse_OmitsInstructionsWhenUnset confirms a server
+// built without server.WithInstructions (main's default when -instructions
+// is empty) leaves the field unset rather than sending an empty string.
+func TestInitializeResponse_OmitsInstructionsWhenUnset(t *testing.T) {
+	s := server.NewMCPServer("test-server", "9.9.9")
+
+	req, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "initialize",
+		"params": map[string]any{
+			"protocolVersion": "2025-06-18",
+			"capabilities":    map[string]any{},
+			"clientInfo":      map[string]any{"name": "test", "version": "0.0.1"},
+		},
+	})
+	resp := s.HandleMessage(context.Background(), req)
+	b, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("marshaling initialize response: %v", err)
+	}
+
+	var envelope struct {
+		Result struct {
+			Instructions *string `json:"instructions"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		t.Fatalf("unmarshaling initialize response: %v", err)
+	}
+	if envelope.Result.Instructions != nil {
+		t.Errorf("instructions = %q, want omitted", *envelope.Result.Instructions)
+	}
+}
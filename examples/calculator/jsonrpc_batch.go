@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// dispatchJSONRPCBatch handles a JSON-RPC batch — a top-level JSON array
+// of request/notification objects, sent as a single message on the stdio
+// and websocket transports (see stdioLineBatch). Each element is
+// dispatched independently and concurrently through the same
+// checkProtocolPreconditions + s.HandleMessage path a lone request would
+// take; a notification element produces no entry in the result. Per the
+// spec, if every element turns out to be a notification the whole batch
+// produces no response at all, signaled by a nil return.
+//
+// elements is already known to be non-empty: an empty batch is an Invalid
+// Request on its own and is rejected by validateStdioLine before this is
+// ever called.
+//
+// This only covers the transports this fixture dispatches itself. The
+// sse/http/both transports hand their POST body straight to
+// server.NewSSEServer's own http.Handler (see transport.go; the http
+// transport has no server of its own) — whether and how those support a batch body is
+// mcp-go's call, not something we can confirm or patch from out here,
+// the same reasoning newTracingMiddleware's doc comment gives for why a
+// request span can't be added at the hooks layer either.
+func dispatchJSONRPCBatch(ctx context.Context, s *server.MCPServer, sessionID string, elements []json.RawMessage) []byte {
+	results := make([]json.RawMessage, len(elements))
+
+	var wg sync.WaitGroup
+	for i, raw := range elements {
+		wg.Add(1)
+		go func(i int, raw json.RawMessage) {
+			defer wg.Done()
+			results[i] = dispatchJSONRPCBatchElement(ctx, s, sessionID, raw)
+		}(i, raw)
+	}
+	wg.Wait()
+
+	responses := make([]json.RawMessage, 0, len(results))
+	for _, r := range results {
+		if r != nil {
+			responses = append(responses, r)
+		}
+	}
+	if len(responses) == 0 {
+		return nil
+	}
+
+	b, err := json.Marshal(responses)
+	if err != nil {
+		return mustMarshal(newStdioParseError())
+	}
+	return b
+}
+
+// dispatchJSONRPCBatchElement dispatches one element of a batch, returning
+// nil for a notification (mcp-go's HandleMessage already returns nil for
+// those — see stdio.go) and a marshaled response object otherwise. An
+// element that isn't itself a JSON object gets its own Invalid Request
+// response rather than failing the whole batch.
+func dispatchJSONRPCBatchElement(ctx context.Context, s *server.MCPServer, sessionID string, raw json.RawMessage) json.RawMessage {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return mustMarshal(newStdioParseError())
+	}
+	if _, ok := v.(map[string]any); !ok {
+		return mustMarshal(newStdioInvalidRequest())
+	}
+
+	var env jsonrpcEnvelope
+	_ = json.Unmarshal(raw, &env)
+	if violation := checkProtocolPreconditions(sessionID, env); violation != nil {
+		return mustMarshal(violation)
+	}
+
+	response := s.HandleMessage(ctx, raw)
+	if response == nil {
+		return nil
+	}
+	b, err := json.Marshal(response)
+	if err != nil {
+		return mustMarshal(newStdioParseError())
+	}
+	if env.Method == "initialize" {
+		b = rewriteInitializeResponse(b)
+	}
+	return b
+}
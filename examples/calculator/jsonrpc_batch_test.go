@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestDispatchJSONRPCBatch_MixedSuccessErrorAndNotification exercises a
+// batch with a successful request, a request for an unknown method, and a
+// notification: the response array should contain exactly the two request
+// responses, in order, with the notification producing no entry.
+func TestDispatchJSONRPCBatch_MixedSuccessErrorAndNotification(t *testing.T) {
+	elements := []json.RawMessage{
+		json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"ping"}`),
+		json.RawMessage(`{"jsonrpc":"2.0","id":2,"method":"does/not/exist"}`),
+		json.RawMessage(`{"jsonrpc":"2.0","method":"notifications/initialized"}`),
+	}
+
+	b := dispatchJSONRPCBatch(context.Background(), newTestServer(), "stdio", elements)
+	if b == nil {
+		t.Fatal("expected a response array, got nil")
+	}
+
+	var responses []map[string]any
+	if err := json.Unmarshal(b, &responses); err != nil {
+		t.Fatalf("unmarshaling batch response %q: %v", b, err)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("got %d responses, want 2 (notification must produce none): %v", len(responses), responses)
+	}
+
+	byID := map[float64]map[string]any{}
+	for _, r := range responses {
+		id, _ := r["id"].(float64)
+		byID[id] = r
+	}
+
+	if _, ok := byID[1]["result"]; !ok {
+		t.Errorf("id 1 (ping): expected a result, got %v", byID[1])
+	}
+	errObj, ok := byID[2]["error"].(map[string]any)
+	if !ok {
+		t.Errorf("id 2 (unknown method): expected an error, got %v", byID[2])
+	} else if code, _ := errObj["code"].(float64); code != -32601 {
+		t.Errorf("id 2 error code = %v, want -32601", errObj["code"])
+	}
+}
+
+// TestDispatchJSONRPCBatch_AllNotificationsProduceNoResponse covers the
+// spec's requirement that a batch consisting only of notifications gets
+// no response at all, not an empty array.
+func TestDispatchJSONRPCBatch_AllNotificationsProduceNoResponse(t *testing.T) {
+	elements := []json.RawMessage{
+		json.RawMessage(`{"jsonrpc":"2.0","method":"notifications/initialized"}`),
+	}
+	if b := dispatchJSONRPCBatch(context.Background(), newTestServer(), "stdio", elements); b != nil {
+		t.Errorf("expected nil for an all-notification batch, got %q", b)
+	}
+}
+
+// TestDispatchJSONRPCBatchElement_NonObjectElementIsInvalidRequest covers
+// a batch element that isn't itself a JSON object, which must get its own
+// Invalid Request response rather than failing the whole batch.
+func TestDispatchJSONRPCBatchElement_NonObjectElementIsInvalidRequest(t *testing.T) {
+	resp := dispatchJSONRPCBatchElement(context.Background(), newTestServer(), "stdio", json.RawMessage(`"not an object"`))
+	var body stdioErrorResponse
+	if err := json.Unmarshal(resp, &body); err != nil {
+		t.Fatalf("unmarshaling %q: %v", resp, err)
+	}
+	if body.Error.Code != -32600 {
+		t.Errorf("code = %d, want -32600", body.Error.Code)
+	}
+}
+
+// TestServeStdioTolerant_BatchLineProducesBatchResponse drives a batch
+// through the real stdio dispatch loop, confirming validateStdioLine's
+// stdioLineBatch classification and dispatchJSONRPCBatch are wired
+// together correctly end to end.
+func TestServeStdioTolerant_BatchLineProducesBatchResponse(t *testing.T) {
+	origStdin, origStdout := os.Stdin, os.Stdout
+	defer func() { os.Stdin, os.Stdout = origStdin, origStdout }()
+
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdin): %v", err)
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdout): %v", err)
+	}
+	os.Stdin, os.Stdout = stdinR, stdoutW
+
+	srv := newTestServer()
+	done := make(chan error, 1)
+	go func() { done <- serveStdioTolerant(context.Background(), srv) }()
+
+	writeLine(t, stdinW, `[{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","id":2,"method":"does/not/exist"}]`)
+
+	scanner := bufio.NewScanner(stdoutR)
+	if !scanner.Scan() {
+		t.Fatalf("expected a batch response line, scan error: %v", scanner.Err())
+	}
+	var responses []map[string]any
+	if err := json.Unmarshal(scanner.Bytes(), &responses); err != nil {
+		t.Fatalf("unmarshaling %q: %v", scanner.Text(), err)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("got %d responses, want 2: %v", len(responses), responses)
+	}
+
+	stdinW.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serveStdioTolerant returned %v after EOF, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("serveStdioTolerant did not return after stdin closed")
+	}
+	stdoutW.Close()
+}
+
+// TestServeStdioTolerant_EmptyBatchIsInvalidRequest covers the other half
+// of "empty or not all valid JSON-RPC": an empty array is rejected with a
+// single Invalid Request object, not an empty array.
+func TestServeStdioTolerant_EmptyBatchIsInvalidRequest(t *testing.T) {
+	origStdin, origStdout := os.Stdin, os.Stdout
+	defer func() { os.Stdin, os.Stdout = origStdin, origStdout }()
+
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdin): %v", err)
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdout): %v", err)
+	}
+	os.Stdin, os.Stdout = stdinR, stdoutW
+
+	srv := newTestServer()
+	done := make(chan error, 1)
+	go func() { done <- serveStdioTolerant(context.Background(), srv) }()
+
+	writeLine(t, stdinW, `[]`)
+
+	scanner := bufio.NewScanner(stdoutR)
+	assertErrorCode(t, scanner, -32600)
+
+	stdinW.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serveStdioTolerant returned %v after EOF, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("serveStdioTolerant did not return after stdin closed")
+	}
+	stdoutW.Close()
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// keepAliveInterval, when positive, enables the zombie-session reaper: a
+// session that sends nothing for keepAliveInterval*keepAliveGraceFactor is
+// treated as dead. Zero (the default) disables the feature entirely: no
+// background goroutine runs, and no session is ever reaped. main wires this
+// to --keep-alive.
+//
+// Incoming "ping" requests need no handling here at all — mcp-go answers
+// them with an empty result at the dispatch layer before a request ever
+// reaches this fixture's hooks or handlers, the same way it handles
+// "initialize" itself. What's missing, and what this file adds, is the
+// other direction: detecting a session that's gone quiet.
+//
+// A genuine server-initiated ping (send a "ping" request to one specific
+// session and await its pong, the way handleAskModelTool awaits a sampling
+// response for the session tied to its own request's context) would need a
+// send-request-to-an-arbitrary-session primitive on *server.MCPServer, which
+// this fixture has no confirmed version of for the sse/http transports —
+// they hand dispatch off to mcp-go's own SSEServer entirely. ping_latency.go does it anyway for stdio and websocket
+// specifically, by writing the raw JSON-RPC request straight to the
+// connection those two transports already own end to end, rather than
+// through any mcp-go send-to-session primitive. So the reaper below still
+// tracks real inbound traffic instead of sending its own wire pings, and
+// "reaping" means forgetting the session and firing onSessionReaped hooks,
+// not forcing the underlying transport connection closed — this fixture
+// has no confirmed way to do that from outside the transport layer either,
+// for the sse/http sessions this reaper actually covers.
+var keepAliveInterval time.Duration
+
+// keepAliveGraceFactor is how many missed intervals of silence a session
+// gets before the reaper gives up on it.
+const keepAliveGraceFactor = 3
+
+// sessionActivity tracks the last time each session sent us anything.
+var sessionActivity = struct {
+	mu       sync.Mutex
+	lastSeen map[string]time.Time
+}{lastSeen: map[string]time.Time{}}
+
+// onSessionReapedHooks are fixture-level callbacks fired when the keepalive
+// reaper gives up on a session, distinct from server.Hooks'
+// AddOnUnregisterSession since that fires on real disconnects the transport
+// itself notices, while this fires on inferred ones.
+var onSessionReapedHooks []func(sessionID string)
+
+func onSessionReaped(fn func(sessionID string)) {
+	onSessionReapedHooks = append(onSessionReapedHooks, fn)
+}
+
+// registerKeepAliveHooks wires activity tracking into every inbound
+// request, via the same AddBeforeAny hook point subscriptions.go and
+// latency.go use, and clears a session's tracked activity once mcp-go tells
+// us it actually disconnected.
+func registerKeepAliveHooks(hooks *server.Hooks) {
+	hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+		if keepAliveInterval <= 0 {
+			return
+		}
+		sessionID := sessionIDFromContext(ctx)
+		if sessionID == "" {
+			return
+		}
+		sessionActivity.mu.Lock()
+		sessionActivity.lastSeen[sessionID] = time.Now()
+		sessionActivity.mu.Unlock()
+	})
+
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		sessionActivity.mu.Lock()
+		delete(sessionActivity.lastSeen, session.SessionID())
+		sessionActivity.mu.Unlock()
+	})
+}
+
+// runKeepAliveReaper periodically reaps sessions that have gone silent for
+// too long. It returns once ctx is cancelled (server shutdown). Callers
+// should only start this when keepAliveInterval > 0.
+func runKeepAliveReaper(ctx context.Context) {
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reapIdleSessions()
+		}
+	}
+}
+
+func reapIdleSessions() {
+	deadline := time.Now().Add(-keepAliveInterval * keepAliveGraceFactor)
+
+	sessionActivity.mu.Lock()
+	var dead []string
+	for sessionID, last := range sessionActivity.lastSeen {
+		if last.Before(deadline) {
+			dead = append(dead, sessionID)
+		}
+	}
+	for _, sessionID := range dead {
+		delete(sessionActivity.lastSeen, sessionID)
+	}
+	sessionActivity.mu.Unlock()
+
+	for _, sessionID := range dead {
+		log.Printf("keepalive: session %s silent for over %s, reaping", sessionID, keepAliveInterval*keepAliveGraceFactor)
+		unsubscribeAll(sessionID)
+		for _, hook := range onSessionReapedHooks {
+			hook(sessionID)
+		}
+	}
+}
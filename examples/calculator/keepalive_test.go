@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReapIdleSessions_ReapsOnlySessionsPastTheGracePeriod(t *testing.T) {
+	keepAliveInterval = 10 * time.Millisecond
+	defer func() { keepAliveInterval = 0 }()
+
+	sessionActivity.mu.Lock()
+	sessionActivity.lastSeen["sess-fresh"] = time.Now()
+	sessionActivity.lastSeen["sess-stale"] = time.Now().Add(-keepAliveInterval * (keepAliveGraceFactor + 1))
+	sessionActivity.mu.Unlock()
+	defer func() {
+		sessionActivity.mu.Lock()
+		delete(sessionActivity.lastSeen, "sess-fresh")
+		delete(sessionActivity.lastSeen, "sess-stale")
+		sessionActivity.mu.Unlock()
+	}()
+
+	var reaped []string
+	onSessionReapedHooks = nil
+	onSessionReaped(func(sessionID string) { reaped = append(reaped, sessionID) })
+	defer func() { onSessionReapedHooks = nil }()
+
+	reapIdleSessions()
+
+	if len(reaped) != 1 || reaped[0] != "sess-stale" {
+		t.Fatalf("reaped = %v, want [sess-stale]", reaped)
+	}
+
+	sessionActivity.mu.Lock()
+	_, freshStillTracked := sessionActivity.lastSeen["sess-fresh"]
+	_, staleStillTracked := sessionActivity.lastSeen["sess-stale"]
+	sessionActivity.mu.Unlock()
+
+	if !freshStillTracked {
+		t.Error("expected sess-fresh to remain tracked")
+	}
+	if staleStillTracked {
+		t.Error("expected sess-stale to be removed from tracking")
+	}
+}
+
+func TestKeepAliveInterval_DisabledByDefault(t *testing.T) {
+	if keepAliveInterval != 0 {
+		t.Fatalf("expected keepAliveInterval to default to 0, got %v", keepAliveInterval)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// latencyMean and latencyJitter configure an artificial delay applied to
+// every tools/call, resources/read, and prompts/get, for clients that need
+// to exercise timeouts or loading states against a server that doesn't
+// just respond instantly. Both default to zero, which disables the delay
+// entirely.
+var (
+	latencyMean   time.Duration
+	latencyJitter time.Duration
+)
+
+// delayedMethods are the request types artificial latency applies to: the
+// three methods that actually do request-shaped work, as opposed to
+// protocol bookkeeping like initialize or ping.
+var delayedMethods = map[mcp.MCPMethod]bool{
+	"tools/call":     true,
+	"resources/read": true,
+	"prompts/get":    true,
+}
+
+// registerLatencyHook wires the configured delay into every request in
+// delayedMethods, via the same AddBeforeAny hook point subscriptions.go and
+// roots.go use to intercept specific methods. Running before dispatch, at
+// the server level rather than in transport.go, means the delay applies
+// identically to stdio, sse, and http.
+func registerLatencyHook(hooks *server.Hooks) {
+	hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+		if !delayedMethods[method] {
+			return
+		}
+		sleep(ctx, randomLatency())
+	})
+}
+
+// randomLatency samples a duration uniformly from
+// [latencyMean-latencyJitter, latencyMean+latencyJitter], clamped to zero.
+func randomLatency() time.Duration {
+	if latencyMean == 0 && latencyJitter == 0 {
+		return 0
+	}
+	if latencyJitter == 0 {
+		return latencyMean
+	}
+
+	offset := time.Duration(rand.Int63n(2*int64(latencyJitter)+1)) - latencyJitter
+	d := latencyMean + offset
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// sleep blocks for d or until ctx is cancelled, whichever comes first, so a
+// cancelled request doesn't still burn the full artificial delay before the
+// handler gets a chance to see ctx.Err() and bail out.
+func sleep(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
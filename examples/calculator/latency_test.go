@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSleep_BlocksForAtLeastTheConfiguredDuration(t *testing.T) {
+	start := time.Now()
+	sleep(context.Background(), 50*time.Millisecond)
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("elapsed = %v, want at least 50ms", elapsed)
+	}
+}
+
+func TestSleep_ZeroDurationReturnsImmediately(t *testing.T) {
+	start := time.Now()
+	sleep(context.Background(), 0)
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("elapsed = %v, want near-instant", elapsed)
+	}
+}
+
+func TestSleep_CancellationCutsItShort(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	sleep(ctx, time.Hour)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("elapsed = %v, want the delay cut short by cancellation", elapsed)
+	}
+}
+
+func TestRandomLatency_DisabledReturnsZero(t *testing.T) {
+	oldMean, oldJitter := latencyMean, latencyJitter
+	defer func() { latencyMean, latencyJitter = oldMean, oldJitter }()
+
+	latencyMean, latencyJitter = 0, 0
+	if d := randomLatency(); d != 0 {
+		t.Errorf("randomLatency() = %v, want 0", d)
+	}
+}
+
+func TestRandomLatency_StaysWithinMeanPlusMinusJitter(t *testing.T) {
+	oldMean, oldJitter := latencyMean, latencyJitter
+	defer func() { latencyMean, latencyJitter = oldMean, oldJitter }()
+
+	latencyMean, latencyJitter = 100*time.Millisecond, 20*time.Millisecond
+	for i := 0; i < 50; i++ {
+		d := randomLatency()
+		if d < 80*time.Millisecond || d > 120*time.Millisecond {
+			t.Fatalf("randomLatency() = %v, want within [80ms, 120ms]", d)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// lenientArgumentsEnabled gates newLenientArgumentsMiddleware, standing in
+// for a hypothetical server.WithLenientArguments() on *server.MCPServer for
+// the same reason strictValidationEnabled does (see validation.go): that
+// option would live at the transport's dispatch layer, which we don't own.
+// Off by default, so a client that already sends well-typed arguments sees
+// no behavior change.
+var lenientArgumentsEnabled = false
+
+// newLenientArgumentsMiddleware coerces stringy numbers ("42") and booleans
+// ("true"/"false") in request.Params.Arguments to the type tool's
+// inputSchema declares for that property, before the handler (or, ahead of
+// it in the chain, newValidationMiddleware) ever sees them. It exists for
+// clients — often LLMs themselves — that encode every argument as a JSON
+// string regardless of the tool's declared type.
+//
+// A value already matching its declared type, or a property tool doesn't
+// declare, is left untouched. A string that doesn't parse as its declared
+// type comes back as InvalidParams: coercion either succeeds silently or
+// fails loudly, never falls through to the handler half-converted.
+func newLenientArgumentsMiddleware(tool mcp.Tool) toolMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if !lenientArgumentsEnabled {
+				return next(ctx, request)
+			}
+
+			for name, value := range request.Params.Arguments {
+				prop, declared := tool.InputSchema.Properties[name]
+				if !declared {
+					continue
+				}
+				propSchema, ok := prop.(map[string]any)
+				if !ok {
+					continue
+				}
+
+				coerced, err := coerceArgument(name, value, propSchema)
+				if err != nil {
+					return nil, invalidParamsf("%s", err.Error())
+				}
+				request.Params.Arguments[name] = coerced
+			}
+
+			return next(ctx, request)
+		}
+	}
+}
+
+// coerceArgument converts value to the type schema declares, if value is a
+// string and the declared type isn't already "string". Any other
+// already-matching value passes through unchanged.
+func coerceArgument(name string, value any, schema map[string]any) (any, error) {
+	s, isString := value.(string)
+	if !isString {
+		return value, nil
+	}
+
+	switch schema["type"] {
+	case "number", "integer":
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("argument %s: %q is not a valid number", name, s)
+		}
+		return n, nil
+	case "boolean":
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("argument %s: %q is not a valid boolean", name, s)
+		}
+		return b, nil
+	default:
+		return value, nil
+	}
+}
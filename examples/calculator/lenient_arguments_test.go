@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func lenientTestTool() mcp.Tool {
+	return mcp.NewTool("lenient_test",
+		mcp.WithNumber("x", mcp.Required()),
+		mcp.WithBoolean("flag", mcp.Required()),
+		mcp.WithString("label", mcp.Required()),
+	)
+}
+
+func TestNewLenientArgumentsMiddleware_CoercesStringyNumbersAndBooleans(t *testing.T) {
+	old := lenientArgumentsEnabled
+	lenientArgumentsEnabled = true
+	defer func() { lenientArgumentsEnabled = old }()
+
+	tool := lenientTestTool()
+	var seen map[string]any
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		seen = request.Params.Arguments
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"x": "42", "flag": "true", "label": "unchanged"}
+
+	wrapped := newLenientArgumentsMiddleware(tool)(handler)
+	if _, err := wrapped(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if seen["x"] != 42.0 {
+		t.Errorf("x = %#v, want float64(42)", seen["x"])
+	}
+	if seen["flag"] != true {
+		t.Errorf("flag = %#v, want true", seen["flag"])
+	}
+	if seen["label"] != "unchanged" {
+		t.Errorf("label = %#v, want unchanged", seen["label"])
+	}
+}
+
+func TestNewLenientArgumentsMiddleware_UnparseableStringIsInvalidParams(t *testing.T) {
+	old := lenientArgumentsEnabled
+	lenientArgumentsEnabled = true
+	defer func() { lenientArgumentsEnabled = old }()
+
+	tool := lenientTestTool()
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		t.Fatal("handler should not run when coercion fails")
+		return nil, nil
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"x": "not-a-number", "flag": "true", "label": "ok"}
+
+	_, err := newLenientArgumentsMiddleware(tool)(handler)(context.Background(), req)
+	pe, ok := err.(*protocolError)
+	if !ok {
+		t.Fatalf("err = %T, want *protocolError", err)
+	}
+	if pe.Code() != mcp.INVALID_PARAMS {
+		t.Errorf("code = %d, want %d", pe.Code(), mcp.INVALID_PARAMS)
+	}
+}
+
+func TestNewLenientArgumentsMiddleware_AlreadyTypedArgumentsPassThrough(t *testing.T) {
+	old := lenientArgumentsEnabled
+	lenientArgumentsEnabled = true
+	defer func() { lenientArgumentsEnabled = old }()
+
+	tool := lenientTestTool()
+	var seen map[string]any
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		seen = request.Params.Arguments
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"x": 42.0, "flag": true, "label": "ok"}
+
+	if _, err := newLenientArgumentsMiddleware(tool)(handler)(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if seen["x"] != 42.0 || seen["flag"] != true {
+		t.Errorf("already-typed arguments were mutated: %#v", seen)
+	}
+}
+
+func TestNewLenientArgumentsMiddleware_DisabledByDefault(t *testing.T) {
+	tool := lenientTestTool()
+	var seen map[string]any
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		seen = request.Params.Arguments
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"x": "42", "flag": "true", "label": "ok"}
+
+	if _, err := newLenientArgumentsMiddleware(tool)(handler)(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if seen["x"] != "42" {
+		t.Errorf("x = %#v, want the untouched string \"42\" when disabled", seen["x"])
+	}
+}
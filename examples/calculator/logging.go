@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// logLevels lists the standard syslog severities MCP's logging capability
+// uses, in increasing order of severity. A session's configured level
+// suppresses every notification below it in this ordering.
+var logLevels = []mcp.LoggingLevel{
+	mcp.LoggingLevelDebug,
+	mcp.LoggingLevelInfo,
+	mcp.LoggingLevelNotice,
+	mcp.LoggingLevelWarning,
+	mcp.LoggingLevelError,
+	mcp.LoggingLevelCritical,
+	mcp.LoggingLevelAlert,
+	mcp.LoggingLevelEmergency,
+}
+
+func logLevelRank(level mcp.LoggingLevel) int {
+	for i, l := range logLevels {
+		if l == level {
+			return i
+		}
+	}
+	return 0
+}
+
+// sessionLogLevels tracks each session's minimum level, set via
+// logging/setLevel. A session with no entry defaults to debug (everything
+// passes), matching the MCP spec's default.
+var sessionLogLevels = struct {
+	sync.Mutex
+	byID map[string]mcp.LoggingLevel
+}{byID: make(map[string]mcp.LoggingLevel)}
+
+func setSessionLogLevel(sessionID string, level mcp.LoggingLevel) {
+	if sessionID == "" {
+		return
+	}
+	sessionLogLevels.Lock()
+	defer sessionLogLevels.Unlock()
+	sessionLogLevels.byID[sessionID] = level
+}
+
+func clearSessionLogLevel(sessionID string) {
+	sessionLogLevels.Lock()
+	defer sessionLogLevels.Unlock()
+	delete(sessionLogLevels.byID, sessionID)
+}
+
+func sessionAllowsLevel(sessionID string, level mcp.LoggingLevel) bool {
+	sessionLogLevels.Lock()
+	min, ok := sessionLogLevels.byID[sessionID]
+	sessionLogLevels.Unlock()
+	if !ok {
+		return true
+	}
+	return logLevelRank(level) >= logLevelRank(min)
+}
+
+// emitLog sends a notifications/message to ctx's session, unless that
+// session has raised its level above level via logging/setLevel. It always
+// targets ctx's own session via SendNotificationToClient: both callers
+// below (logInvocationMiddleware, handleLogEmitTool) are mid-request, so
+// there's never a different session to reach.
+func emitLog(ctx context.Context, srv *server.MCPServer, level mcp.LoggingLevel, logger string, data any) {
+	if srv == nil {
+		return
+	}
+
+	sessionID := sessionIDFromContext(ctx)
+	if !sessionAllowsLevel(sessionID, level) {
+		return
+	}
+
+	srv.SendNotificationToClient(ctx, "notifications/message", map[string]any{
+		"level":  level,
+		"logger": logger,
+		"data":   data,
+	})
+}
+
+// registerLoggingHooks wires logging/setLevel handling and session cleanup
+// onto hooks, following the same AddBeforeAny interception
+// registerSubscriptionHooks uses for resources/subscribe.
+func registerLoggingHooks(hooks *server.Hooks) {
+	hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+		if method != "logging/setLevel" {
+			return
+		}
+		req, ok := message.(*mcp.SetLevelRequest)
+		if !ok {
+			return
+		}
+		setSessionLogLevel(sessionIDFromContext(ctx), req.Params.Level)
+	})
+
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		clearSessionLogLevel(session.SessionID())
+	})
+}
+
+// logInvocationMiddleware emits a debug-level notifications/message for
+// every tool call, summarizing its name, arguments, and duration, so the
+// Anubis client can exercise logging/setLevel filtering end to end without
+// a dedicated tool.
+func logInvocationMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := next(ctx, request)
+		emitLog(ctx, server.ServerFromContext(ctx), mcp.LoggingLevelDebug, "calculator",
+			fmt.Sprintf("tool=%s args=%v duration=%s", request.Params.Name, request.Params.Arguments, time.Since(start)))
+		return result, err
+	}
+}
+
+// registerLogEmitTool wires up log_emit, which sends one notifications/message
+// at every severity so a test can assert logging/setLevel filtering
+// deterministically.
+func registerLogEmitTool(s *server.MCPServer) {
+	tool := mcp.NewTool("log_emit",
+		mcp.WithDescription("Emit one log notification at every severity level"),
+	)
+	addToolWithMiddleware(s, tool, handleLogEmitTool, recoverMiddleware)
+}
+
+func handleLogEmitTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	srv := server.ServerFromContext(ctx)
+	for _, level := range logLevels {
+		emitLog(ctx, srv, level, "calculator", fmt.Sprintf("log_emit: %s message", level))
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("emitted %d log messages", len(logLevels))), nil
+}
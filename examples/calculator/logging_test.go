@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestSessionAllowsLevel_DefaultsToDebug(t *testing.T) {
+	if !sessionAllowsLevel("unset-session", mcp.LoggingLevelDebug) {
+		t.Error("a session with no configured level should allow debug")
+	}
+}
+
+func TestSetSessionLogLevel_SuppressesBelowConfiguredLevel(t *testing.T) {
+	const session = "test-session-1"
+	t.Cleanup(func() { clearSessionLogLevel(session) })
+
+	setSessionLogLevel(session, mcp.LoggingLevelWarning)
+
+	if sessionAllowsLevel(session, mcp.LoggingLevelDebug) {
+		t.Error("debug should be suppressed once the session is set to warning")
+	}
+	if sessionAllowsLevel(session, mcp.LoggingLevelInfo) {
+		t.Error("info should be suppressed once the session is set to warning")
+	}
+	if !sessionAllowsLevel(session, mcp.LoggingLevelWarning) {
+		t.Error("warning itself should still be allowed")
+	}
+	if !sessionAllowsLevel(session, mcp.LoggingLevelError) {
+		t.Error("error should still be allowed once the session is set to warning")
+	}
+}
+
+func TestClearSessionLogLevel_ResetsToDefault(t *testing.T) {
+	const session = "test-session-2"
+
+	setSessionLogLevel(session, mcp.LoggingLevelError)
+	clearSessionLogLevel(session)
+
+	if !sessionAllowsLevel(session, mcp.LoggingLevelDebug) {
+		t.Error("clearing a session's level should restore the debug default")
+	}
+}
+
+func TestHandleLogEmitTool_Succeeds(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	res, err := handleLogEmitTool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.IsError {
+		t.Fatalf("expected a successful result")
+	}
+}
@@ -2,90 +2,481 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// mcpServer is kept at package scope so tool handlers can register
+// resources (e.g. a new calc://history/N entry) as a side effect of a call.
+var mcpServer *server.MCPServer
+
 func main() {
-	s := server.NewMCPServer("Calculator", "0.1.0", server.WithLogging())
+	noRecover := flag.Bool("no-recover", false, "Let a panicking handler crash the process instead of converting it to an internal error")
+	strictValidation := flag.Bool("strict-validation", false, "Validate tools/call arguments against the declared inputSchema before dispatch")
+	rejectExtra := flag.Bool("reject-extra-properties", false, "With -strict-validation, reject arguments not declared in the tool's inputSchema instead of ignoring them")
+	manyTools := flag.Int("many-tools", 0, "Register N generated no-op tools (noop_001..noop_N) to exercise tools/list pagination")
+	pageSize := flag.Int("page-size", 100, "Number of items tools/list and resources/list return per page")
+
+	var transport string
+	flag.StringVar(&transport, "t", "stdio", "Transport type (stdio, sse, http, both, or websocket)")
+	flag.StringVar(
+		&transport,
+		"transport",
+		"stdio",
+		"Transport type (stdio, sse, http, both, or websocket)",
+	)
+	host := flag.String("host", envOrDefault("CALC_HOST", "localhost"), "Host to bind for the sse and http transports")
+	port := flag.Int("port", envIntOrDefault("CALC_PORT", 8000), "Port to bind for the sse and http transports (0 for an ephemeral port)")
+	ssePath := flag.String("sse-path", envOrDefault("CALC_SSE_PATH", "/sse"), "Path the SSE transport listens for event streams on")
+	messagePath := flag.String("message-path", envOrDefault("CALC_MESSAGE_PATH", "/message"), "Path the SSE transport listens for posted messages on")
+	wsPath := flag.String("ws-path", envOrDefault("CALC_WS_PATH", "/ws"), "Path the websocket transport listens for connections on")
+	wsSubprotocol := flag.String("ws-subprotocol", envOrDefault("CALC_WS_SUBPROTOCOL", ""), "If set, the only WebSocket subprotocol the websocket transport accepts")
+	certFile := flag.String("cert-file", envOrDefault("CALC_CERT_FILE", ""), "TLS certificate file; with -key-file, serves the sse/http/both transports over HTTPS")
+	keyFile := flag.String("key-file", envOrDefault("CALC_KEY_FILE", ""), "TLS private key file; required alongside -cert-file")
+	clientCAFile := flag.String("client-ca-file", envOrDefault("CALC_CLIENT_CA_FILE", ""), "With -cert-file/-key-file, CA bundle to verify client certificates against (enables mutual TLS)")
+	authToken := flag.String("auth-token", envOrDefault("CALC_AUTH_TOKEN", ""), "If set, require a matching Authorization: Bearer <token> header on the sse/http/both transports")
+	latency := flag.Duration("latency", 0, "Artificial delay applied to every tools/call, resources/read, and prompts/get")
+	jitter := flag.Duration("jitter", 0, "Random +/- jitter applied on top of -latency")
+	maxBatch := flag.Int("max-batch", 1000, "Maximum number of operations accepted by a single calculate_batch call")
+	maxExprLength := flag.Int("max-expression-length", 200, "Maximum character length of an expression accepted by evaluate")
+	maxRespBytes := flag.Int("max-response-bytes", 16*1024*1024, "Maximum size_bytes accepted by generate_text")
+	noSubscriptions := flag.Bool("no-subscriptions", false, "Disable resources/subscribe tracking and advertise the subscribe sub-capability as false")
+	enableChaos := flag.Bool("enable-chaos", false, "Register simulate_crash, simulate_hang, and panic_tool for robustness testing")
+	toolTimeoutFlag := flag.Duration("tool-timeout", 90*time.Second, "Deadline enforced on tools that apply newTimeoutMiddleware (currently slow_add)")
+	lenientArguments := flag.Bool("lenient-arguments", false, "Coerce stringy numbers/booleans in tools/call arguments to their declared schema type before dispatch (currently calculate)")
+	keepAlive := flag.Duration("keep-alive", 0, "If set, reap a session that's sent nothing for keepAliveGraceFactor intervals of this duration (0 disables the reaper)")
+	sseKeepAliveInterval := flag.Duration("keepalive-interval", 25*time.Second, "Send a keep-alive notification to every open SSE session at this cadence, to defeat idle-connection timeouts in front of the server (0 disables)")
+	strict := flag.Bool("strict", false, "Reject any request but initialize/ping/notifications-initialized sent before the initialize handshake completes, with -32002")
+	dumpToolSchema := flag.Bool("dump-tool-schema", false, "Print every registered tool's JSON Schema to stdout and exit instead of serving")
+	protocolVersion := flag.String("protocol-version", "", "Pin the initialize response to this MCP protocol version (one of knownProtocolVersions) and disable capabilities newer than it")
+	noTools := flag.Bool("no-tools", false, "Disable the tools capability: drop it from the initialize response and return method-not-found for tools/list and tools/call")
+	noResources := flag.Bool("no-resources", false, "Disable the resources capability: drop it from the initialize response and return method-not-found for resources/* methods")
+	noPrompts := flag.Bool("no-prompts", false, "Disable the prompts capability: drop it from the initialize response and return method-not-found for prompts/list and prompts/get")
+	noLogging := flag.Bool("no-logging", false, "Disable the logging capability: drop it from the initialize response and return method-not-found for logging/setLevel")
+	rateLimitPerSession := flag.Int("rate-limit-per-session", 0, "Maximum tools/call invocations per -rate-limit-window for a single session (0 disables)")
+	rateLimitWindow := flag.Duration("rate-limit-window", time.Second, "Window -rate-limit-per-session and -rate-limit-tool-calculate-per-session refill over")
+	rateLimitCalculatePerSession := flag.Int("rate-limit-tool-calculate-per-session", 0, "Per-tool override of -rate-limit-per-session for calculate alone (0 disables)")
+	corsOrigins := flag.String("cors-allowed-origins", envOrDefault("CALC_CORS_ALLOWED_ORIGINS", ""), "Comma-separated list of origins (or \"*\") allowed to make cross-origin requests against the sse/http/both transports")
+	corsCredentials := flag.Bool("cors-allow-credentials", false, "Send Access-Control-Allow-Credentials: true; mutually exclusive with a \"*\" -cors-allowed-origins entry")
+	corsHeaders := flag.String("cors-headers", "", "Comma-separated list of request headers to answer CORS preflights with; defaults to defaultCORSAllowedHeaders when unset")
+	allowedOrigins := flag.String("allowed-origins", envOrDefault("CALC_ALLOWED_ORIGINS", ""), "Comma-separated list of origins (or \"*\") allowed to send requests to the sse/both transports' stream and message endpoints; rejects any other Origin with 403")
+	requireOrigin := flag.Bool("require-origin", false, "Reject sse/http/both requests with no Origin header at all, instead of letting non-browser clients through by default")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 5*time.Second, "How long to let in-flight requests finish after SIGINT/SIGTERM before forcing the http server closed")
+	logFormat := flag.String("log-format", envOrDefault("CALC_LOG_FORMAT", "text"), "Structured log output format for tool call logging: text or json")
+	compressionThreshold := flag.Int("compression-threshold-bytes", 0, "Gzip-encode sse/http/both POST responses at least this big when the client's Accept-Encoding allows it (0 disables; never applies to stdio or the open SSE event stream)")
+	secondPrefix := flag.String("second-server-prefix", "", "Mount a second, independent Echo MCP server (its own session table) on the sse/both transports' mux at this path prefix, e.g. /echo (empty disables)")
+	pingInterval := flag.Duration("ping-interval", 0, "Send a JSON-RPC ping request to the connection at this cadence and record its round-trip latency (stdio and websocket only; 0 disables)")
+	pingMaxMissed := flag.Int("ping-max-missed", 0, "Close the connection after this many consecutive missed pings (0 never evicts, even with -ping-interval set)")
+	maxRequestBytesFlag := flag.Int("max-request-bytes", 4*1024*1024, "Maximum size of a single JSON-RPC request accepted on the sse/http/both transports' POST body or a stdio line (0 disables)")
+	stdioBufferSizeFlag := flag.Int("stdio-buffer-size", 64*1024, "Initial read buffer size for the stdio transport; large single-line messages still work past this, it only tunes throughput")
+	stdioFraming := flag.String("stdio-framing", stdioFramingLines, "How the stdio transport delimits one message from the next: \"lines\" (one compact JSON value per line) or \"content-length\" (LSP-style Content-Length header framing, for clients that pretty-print JSON across multiple lines)")
+	debug := flag.Bool("debug", false, "Log every JSON-RPC message crossing the wire, in either direction, as a single-line JSON record to stderr (and -log-file, if set)")
+	debugMaxBytesFlag := flag.Int("debug-max-bytes", 2048, "Truncate a -debug message's logged payload past this many bytes (0 or negative disables truncation)")
+	logFile := flag.String("log-file", "", "Append -debug output to this file as well as stderr")
+	record := flag.String("record", "", "Append every JSON-RPC message (direction, timestamp, session id, raw payload) crossing the wire on any transport to this file as JSON Lines (empty disables)")
+	replay := flag.String("replay", "", "Answer requests from a --record-produced transcript at this path, matched by method and params, instead of invoking real handlers (empty disables)")
+	faultFlag := flag.String("fault", "", "Make the stdio and websocket transports misbehave on every Nth outbound response for a session: \"drop:5\", \"delay:3:2s\", or \"truncate:7\" (empty disables)")
+	faultIncludeInitFlag := flag.Bool("fault-include-init", false, "With -fault, also apply fault injection to the initialize response (exempt by default)")
+	serverNameFlag := flag.String("server-name", "Calculator", "Server name reported in the initialize response's serverInfo")
+	serverVersionFlag := flag.String("server-version", "0.1.0", "Server version reported in the initialize response's serverInfo")
+	instructions := flag.String("instructions", "", "Natural-language usage guidance included in the initialize response's instructions field (empty omits it)")
+	maxConcurrentFlag := flag.Int("max-concurrent", 0, "Cap the number of tools/call invocations running at once, process-wide rather than per-session (0 disables)")
+	queueSizeFlag := flag.Int("queue-size", 0, "With -max-concurrent saturated, let up to this many additional calls wait for a free slot instead of refusing them immediately as busy (0 refuses immediately)")
+	flag.Parse()
+
+	recoverEnabled = !*noRecover
+	strictValidationEnabled = *strictValidation
+	rejectExtraProperties = *rejectExtra
+	latencyMean = *latency
+	latencyJitter = *jitter
+	maxBatchSize = *maxBatch
+	maxExpressionLength = *maxExprLength
+	maxResponseBytes = *maxRespBytes
+	subscriptionsEnabled = !*noSubscriptions
+	chaosEnabled = *enableChaos
+	toolTimeout = *toolTimeoutFlag
+	lenientArgumentsEnabled = *lenientArguments
+	keepAliveInterval = *keepAlive
+	ssePingInterval = *sseKeepAliveInterval
+	compressionThresholdBytes = *compressionThreshold
+	serverPingInterval = *pingInterval
+	serverPingMaxMissed = *pingMaxMissed
+	maxRequestBytes = *maxRequestBytesFlag
+	stdioBufferSize = *stdioBufferSizeFlag
+	switch *stdioFraming {
+	case stdioFramingLines, stdioFramingContentLength:
+		stdioFramingMode = *stdioFraming
+	default:
+		log.Fatalf("stdio-framing: %q must be %q or %q", *stdioFraming, stdioFramingLines, stdioFramingContentLength)
+	}
+	debugEnabled = *debug
+	debugMaxBytes = *debugMaxBytesFlag
+	debugLogCloser, err := configureDebugLogging(*logFile)
+	if err != nil {
+		log.Fatalf("log-file: %v", err)
+	}
+	if debugLogCloser != nil {
+		defer debugLogCloser.Close()
+	}
+	recordPath = *record
+	if recordPath != "" {
+		if err := openRecordFile(recordPath); err != nil {
+			log.Fatalf("%v", err)
+		}
+		defer closeRecordFile()
+	}
+	if *replay != "" {
+		if err := loadReplayFile(*replay); err != nil {
+			log.Fatalf("%v", err)
+		}
+		replayPath = *replay
+	}
+	parsedFault, err := parseFaultSpec(*faultFlag)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	fault = parsedFault
+	faultIncludeInit = *faultIncludeInitFlag
+	maxConcurrentToolCalls = *maxConcurrentFlag
+	concurrencyQueueSize = *queueSizeFlag
+	ensureConcurrencyLimiter()
+	if *secondPrefix != "" && *secondPrefix != "/" {
+		secondServerPrefix = strings.TrimSuffix(*secondPrefix, "/")
+	} else if *secondPrefix == "/" {
+		log.Fatalf("second-server-prefix: %q would collide with the primary server's own root paths", *secondPrefix)
+	}
+	strictModeEnabled = *strict
+	if *protocolVersion != "" {
+		if !isKnownProtocolVersion(*protocolVersion) {
+			log.Fatalf("--protocol-version: %q is not one of %v", *protocolVersion, knownProtocolVersions)
+		}
+		pinnedProtocolVersion = *protocolVersion
+	}
+	capabilitiesEnabled.Tools = !*noTools
+	capabilitiesEnabled.Resources = !*noResources
+	capabilitiesEnabled.Prompts = !*noPrompts
+	capabilitiesEnabled.Logging = !*noLogging
+	sessionRateLimit = rateLimitConfig{Limit: *rateLimitPerSession, Window: *rateLimitWindow}
+	if *rateLimitCalculatePerSession > 0 {
+		toolRateLimits["calculate"] = rateLimitConfig{Limit: *rateLimitCalculatePerSession, Window: *rateLimitWindow}
+	}
+	if err := validateTLSFlags(*certFile, *keyFile); err != nil {
+		log.Fatalf("%v", err)
+	}
+	if *wsSubprotocol != "" {
+		wsUpgrader.Subprotocols = []string{*wsSubprotocol}
+	}
+	shutdownGrace = *shutdownTimeout
+	structuredLogger = slog.New(newSlogHandler(*logFormat))
+
+	var cors *corsConfig
+	if *corsOrigins != "" {
+		var allowedHeaders []string
+		if *corsHeaders != "" {
+			allowedHeaders = strings.Split(*corsHeaders, ",")
+		}
+		parsed, err := newCORSConfig(strings.Split(*corsOrigins, ","), *corsCredentials, allowedHeaders)
+		if err != nil {
+			log.Fatalf("cors: %v", err)
+		}
+		cors = parsed
+	}
+
+	var origin *originConfig
+	if *allowedOrigins != "" {
+		origin = newOriginConfig(strings.Split(*allowedOrigins, ","), *requireOrigin)
+	} else if *requireOrigin {
+		log.Fatalf("origin: -require-origin needs at least one -allowed-origins entry")
+	}
+
+	hooks := &server.Hooks{}
+	registerSessionRegistryHooks(hooks)
+	registerSubscriptionHooks(hooks)
+	registerLoggingHooks(hooks)
+	registerRootsListChangedHook(hooks)
+	registerLatencyHook(hooks)
+	registerCounterHooks(hooks)
+	registerKeepAliveHooks(hooks)
+	registerSSEKeepAliveHooks(hooks)
+	registerStrictModeHooks(hooks)
+	registerRateLimitHooks(hooks)
+	registerMetricsHooks(hooks)
+	hooks.AddBeforeInitialize(func(ctx context.Context, id any, message *mcp.InitializeRequest) {
+		log.Printf("initialize from %s %s", message.Params.ClientInfo.Name, message.Params.ClientInfo.Version)
+	})
+	hooks.AddAfterCallTool(func(ctx context.Context, id any, message *mcp.CallToolRequest, result *mcp.CallToolResult) {
+		log.Printf("tool %s completed (isError=%v)", message.Params.Name, result.IsError)
+	})
+
+	serverOpts := []server.ServerOption{
+		server.WithHooks(hooks),
+		server.WithPaginationLimit(*pageSize),
+	}
+	if *instructions != "" {
+		serverOpts = append(serverOpts, server.WithInstructions(*instructions))
+	}
+	if capabilitiesEnabled.Logging {
+		serverOpts = append(serverOpts, server.WithLogging())
+	}
+	if capabilitiesEnabled.Resources {
+		serverOpts = append(serverOpts, server.WithResourceCapabilities(subscriptionsEnabled, true))
+	}
+	if capabilitiesEnabled.Tools {
+		serverOpts = append(serverOpts, server.WithToolCapabilities(true))
+	}
+	if capabilitiesEnabled.Prompts {
+		serverOpts = append(serverOpts, server.WithPromptCapabilities(true))
+	}
+	log.Printf("effective capability set: %s", effectiveCapabilitySet())
+
+	// -server-name/-server-version are passed here, not through a
+	// ServerOption: NewMCPServer's name and version are set entirely via
+	// these two positional arguments, with no separate
+	// "server.WithServerInfo" to go alongside -instructions above. Per-
+	// item "title" fields (the 2025-06-18 spec's display-name addition to
+	// Tool/Resource/Prompt) aren't set anywhere in this fixture either —
+	// there's no confirmed mcp-go v0.23.1 option for them, and NewTool's
+	// own option set (see tool registrations throughout this file) has
+	// no WithTitle to check that against.
+	s := server.NewMCPServer(*serverNameFlag, *serverVersionFlag, serverOpts...)
+	mcpServer = s
+
+	// handleCompletion (completion.go) implements the completion/complete
+	// logic and is exercised directly by completion_test.go, but this
+	// mcp-go version's server.MCPServer has no register-a-completion-
+	// handler hook and never dispatches the completion/complete method at
+	// all — it falls through to "Method not found" like any other
+	// unrecognized method. Wiring handleCompletion into the live server
+	// would need a newer mcp-go or a custom request_handler.go fork.
 
 	tool := mcp.NewTool("calculate",
 		mcp.WithDescription("Perform basic arithmetic operations"),
 		mcp.WithString("operation",
 			mcp.Required(),
-			mcp.Description("The operation to perform (add, subtract, multiply, divide)"),
-			mcp.Enum("add", "subtract", "multiply", "divide"),
+			mcp.Description(fmt.Sprintf("The operation to perform (%s)", operationsDescription())),
+			mcp.Enum(operationNames()...),
 		),
 		mcp.WithNumber("x",
 			mcp.Required(),
 			mcp.Description("First number"),
+			mcp.Min(-1e15),
+			mcp.Max(1e15),
 		),
 		mcp.WithNumber("y",
 			mcp.Required(),
 			mcp.Description("Second number"),
+			mcp.Min(-1e15),
+			mcp.Max(1e15),
 		),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Calculate",
+			ReadOnlyHint:    true,
+			DestructiveHint: false,
+			IdempotentHint:  true,
+		}),
 	)
+	registerOutputSchema(tool.Name, mcp.ToolInputSchema{
+		Type: "object",
+		Properties: map[string]any{
+			"operation": map[string]any{"type": "string"},
+			"x":         map[string]any{"type": "number"},
+			"y":         map[string]any{"type": "number"},
+			"result":    map[string]any{"type": "number"},
+		},
+		Required: []string{"operation", "x", "y", "result"},
+	})
 
-	s.AddTool(tool, handle_calculate_tool)
+	addToolWithMiddleware(s, tool, handle_calculate_tool, newTracingMiddleware("calculate"), newRateLimitMiddleware("calculate"), newDefaultsMiddleware(tool), recoverMiddleware, newConcurrencyMiddleware(), loggingMiddleware, logInvocationMiddleware, newLenientArgumentsMiddleware(tool), newValidationMiddleware(tool))
+	registerResources(s)
+	registerResourceTemplates(s)
+	registerHistoryTemplate(s)
+	registerPrompts(s)
+	registerSlowAddTool(s)
+	registerEchoTool(s)
+	registerRenderResultTool(s)
+	registerDescribeResultTool(s)
+	registerFetchDocumentTool(s)
+	registerLogEmitTool(s)
+	registerAskModelTool(s)
+	registerRootsTool(s)
+	registerRaiseErrorTool(s)
+	registerCalculateBatchTool(s)
+	registerCalculateBigTool(s)
+	registerEvaluateTool(s)
+	registerCounterTools(s)
+	registerDynamicRegistrationTools(s)
+	registerResetHistoryTool(s)
+	registerGetHistoryEntryTool(s)
+	registerGenerateTextTool(s)
+	registerConnectionStatsTool(s)
 
-	var transport string
-	flag.StringVar(&transport, "t", "stdio", "Transport type (stdio or sse)")
-	flag.StringVar(
-		&transport,
-		"transport",
-		"stdio",
-		"Transport type (stdio or sse)",
-	)
-	flag.Parse()
+	if chaosEnabled {
+		registerChaosTools(s)
+	}
 
-	switch transport {
-	case "stdio":
-		if err := server.ServeStdio(s); err != nil {
-			log.Fatalf("Server error: %v", err)
-		}
-	case "sse":
-		sse := server.NewSSEServer(s, "")
-		log.Printf("SSE server listening on :8000")
+	if *manyTools > 0 {
+		registerManyTools(s, *manyTools)
+		registerManyResources(s, *manyTools)
+	}
 
-		if err := sse.Start(":8000"); err != nil {
-			log.Fatalf("Server error: %v", err)
+	if *dumpToolSchema {
+		if err := DumpToolSchemas(context.Background(), s, os.Stdout); err != nil {
+			log.Fatalf("dump-tool-schema: %v", err)
 		}
-	default:
-		log.Fatalf(
-			"Invalid transport type: %s. Must be 'stdio' or 'sse'",
-			transport,
-		)
+		return
+	}
+
+	cfg := transportConfig{
+		Host:          *host,
+		Port:          *port,
+		SSEPath:       *ssePath,
+		MessagePath:   *messagePath,
+		WebSocketPath: *wsPath,
+		CertFile:      *certFile,
+		KeyFile:       *keyFile,
+		ClientCAFile:  *clientCAFile,
+		AuthToken:     *authToken,
+		CORS:          cors,
+		Origin:        origin,
+	}
+
+	markReady()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go forceExitOnSecondSignal(ctx)
+
+	if keepAliveInterval > 0 {
+		go runKeepAliveReaper(ctx)
+	}
+
+	if err := serve(ctx, s, transport, cfg); err != nil {
+		log.Fatalf("Server error: %v", err)
 	}
 }
 
-func handle_calculate_tool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	op := request.Params.Arguments["operation"].(string)
-	x := request.Params.Arguments["x"].(float64)
-	y := request.Params.Arguments["y"].(float64)
+// forceExitOnSecondSignal waits for ctx (tied to the first SIGINT/SIGTERM)
+// to be cancelled, then watches for a second one and exits immediately
+// instead of waiting out shutdownGrace — for operators who really do want
+// the process gone right now, not after a graceful drain.
+func forceExitOnSecondSignal(ctx context.Context) {
+	<-ctx.Done()
+
+	again := make(chan os.Signal, 1)
+	signal.Notify(again, os.Interrupt, syscall.SIGTERM)
+	<-again
+	log.Fatal("received a second interrupt/terminate signal, forcing immediate exit")
+}
+
+// canonicalOperation maps the short aliases some older clients still send
+// onto the canonical operation names advertised in the tool's enum schema.
+func canonicalOperation(op string) string {
+	if def, ok := lookupOperation(op); ok {
+		return def.name
+	}
+	return op
+}
+
+// calculateArgs mirrors the calculate tool's inputSchema via json tags, so
+// handle_calculate_tool can bind arguments instead of type-asserting them.
+type calculateArgs struct {
+	Operation string  `json:"operation"`
+	X         float64 `json:"x"`
+	Y         float64 `json:"y"`
+}
+
+// calculate is the single arithmetic code path shared by the calculate
+// tool and the explain_calculation prompt, so both agree on results.
+//
+// A request for an operation outside operationTable returns a
+// *protocolError (the request itself was malformed); divide-by-zero,
+// 0^negative, and sqrt of a negative number all return a plain error (the
+// request was fine, the computation wasn't). A result that isn't finite
+// (NaN, +Inf, -Inf) is likewise reported as a plain error, since JSON can't
+// encode it.
+func calculate(op string, x, y float64) (float64, error) {
+	def, ok := lookupOperation(op)
+	if !ok {
+		return 0, invalidParamsf("operation %s isn't supported", op)
+	}
 
-	if op == "div" && y == 0 {
-		return mcp.NewToolResultError("Cannot divide by zero"), nil
+	result, err := def.apply(x, y)
+	if err != nil {
+		return 0, err
+	}
+	if err := requireFinite(result); err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+func handle_calculate_tool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	if op == "add" {
-		return mcp.NewToolResultText(fmt.Sprintf("%v", x+y)), nil
+	var args calculateArgs
+	if err := bindArguments(request, &args); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	if op == "mult" {
-		return mcp.NewToolResultText(fmt.Sprintf("%v", x*y)), nil
+	op, x, y := args.Operation, args.X, args.Y
+
+	result, err := calculate(op, x, y)
+	if err != nil {
+		if pe, ok := err.(*protocolError); ok {
+			// The operation fell outside the tool's declared enum: a
+			// malformed request, reported as a real JSON-RPC error rather
+			// than a successful isError result.
+			return nil, pe
+		}
+		// Division by zero is a tool-domain failure: the request was
+		// well-formed, the computation just can't proceed.
+		return toolResultErrorf("%s", err.Error()), nil
 	}
 
-	if op == "sub" {
-		return mcp.NewToolResultText(fmt.Sprintf("%v", x-y)), nil
+	index := recordCalculation(canonicalOperation(op), x, y, result, time.Now())
+	if mcpServer != nil {
+		registerHistoryResource(mcpServer, index)
+		notifyResourceUpdated(ctx, mcpServer, "calc://history/latest")
 	}
 
-	if op == "div" {
-		return mcp.NewToolResultText(fmt.Sprintf("%v", x/y)), nil
+	structured := calculateResult{
+		Operation: canonicalOperation(op),
+		X:         x,
+		Y:         y,
+		Result:    result,
+	}
+	encoded, err := json.Marshal(structured)
+	if err != nil {
+		return nil, internalErrorf("encoding structured result: %v", err)
 	}
 
-	return mcp.NewToolResultError(fmt.Sprintf("operation %s isn't supported", op)), nil
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{Type: "text", Text: fmt.Sprintf("%v", result)},
+			mcp.TextContent{Type: "text", Text: string(encoded)},
+		},
+	}, nil
+}
+
+// calculateResult is calculate's outputSchema shape: clients that parse
+// structured content instead of the text fallback get operation/x/y/result
+// back as typed JSON rather than a bare stringified float.
+type calculateResult struct {
+	Operation string  `json:"operation"`
+	X         float64 `json:"x"`
+	Y         float64 `json:"y"`
+	Result    float64 `json:"result"`
 }
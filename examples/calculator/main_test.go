@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func callCalculate(t *testing.T, op string, x, y float64) *mcp.CallToolResult {
+	t.Helper()
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "calculate"
+	req.Params.Arguments = map[string]any{
+		"operation": op,
+		"x":         x,
+		"y":         y,
+	}
+
+	res, err := handle_calculate_tool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("handle_calculate_tool(%q) returned error: %v", op, err)
+	}
+
+	return res
+}
+
+// resultText returns res.Content[0]'s text, the human-readable result
+// handle_calculate_tool always leads with (a structured JSON text block
+// follows at Content[1] on success; see TestHandleCalculateTool_*
+// in structured_output_test.go).
+func resultText(t *testing.T, res *mcp.CallToolResult) string {
+	t.Helper()
+
+	if len(res.Content) == 0 {
+		t.Fatalf("expected at least one content item, got 0")
+	}
+
+	tc, ok := res.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected TextContent, got %T", res.Content[0])
+	}
+
+	return tc.Text
+}
+
+func TestHandleCalculateTool_AdvertisedOperations(t *testing.T) {
+	cases := []struct {
+		op   string
+		x, y float64
+		want string
+	}{
+		{"add", 2, 3, "5"},
+		{"subtract", 5, 3, "2"},
+		{"multiply", 4, 3, "12"},
+		{"divide", 9, 3, "3"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.op, func(t *testing.T) {
+			res := callCalculate(t, tc.op, tc.x, tc.y)
+			if res.IsError {
+				t.Fatalf("operation %q returned an error result", tc.op)
+			}
+
+			got := resultText(t, res)
+			if got != tc.want {
+				t.Errorf("operation %q = %q, want %q", tc.op, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHandleCalculateTool_DivideByZero(t *testing.T) {
+	res := callCalculate(t, "divide", 1, 0)
+
+	if !res.IsError {
+		t.Fatalf("expected divide by zero to return an error result")
+	}
+
+	if got := resultText(t, res); got != "Cannot divide by zero" {
+		t.Errorf("error message = %q, want %q", got, "Cannot divide by zero")
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerManyTools registers n generated no-op tools named noop_001..noop_N,
+// used together with --page-size to give a client's tools/list pagination
+// code something real to walk.
+func registerManyTools(s *server.MCPServer, n int) {
+	for i := 1; i <= n; i++ {
+		tool := mcp.NewTool(fmt.Sprintf("noop_%03d", i),
+			mcp.WithDescription("Generated no-op tool for pagination testing"),
+		)
+		s.AddTool(tool, handleNoopTool)
+	}
+}
+
+func handleNoopTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return mcp.NewToolResultText("ok"), nil
+}
+
+// registerManyResources registers n generated no-op resources, for the same
+// reason registerManyTools does: giving resources/list pagination something
+// to page over.
+func registerManyResources(s *server.MCPServer, n int) {
+	for i := 1; i <= n; i++ {
+		uri := fmt.Sprintf("generated://noop/%03d", i)
+		s.AddResource(
+			mcp.NewResource(uri, fmt.Sprintf("Generated resource %03d", i), mcp.WithMIMEType("text/plain")),
+			handleNoopResource,
+		)
+	}
+}
+
+func handleNoopResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "text/plain",
+			Text:     "generated",
+		},
+	}, nil
+}
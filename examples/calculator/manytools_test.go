@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestRegisterManyTools_NamesAreSequentialAndUnique(t *testing.T) {
+	s := server.NewMCPServer("test", "0.0.0")
+	registerManyTools(s, 5)
+
+	raw, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": "tools/list", "params": map[string]any{}})
+	listResp, err := json.Marshal(s.HandleMessage(context.Background(), raw))
+	if err != nil {
+		t.Fatalf("marshaling tools/list response: %v", err)
+	}
+	var parsed struct {
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(listResp, &parsed); err != nil {
+		t.Fatalf("unmarshaling tools/list: %v", err)
+	}
+
+	seen := map[string]bool{}
+	for _, tool := range parsed.Result.Tools {
+		if seen[tool.Name] {
+			t.Fatalf("duplicate tool name %q", tool.Name)
+		}
+		seen[tool.Name] = true
+	}
+	for i := 1; i <= 5; i++ {
+		if name := fmt.Sprintf("noop_%03d", i); !seen[name] {
+			t.Errorf("tools/list is missing %q", name)
+		}
+	}
+	if len(seen) != 5 {
+		t.Fatalf("registered %d distinct names, want 5", len(seen))
+	}
+}
+
+func TestHandleNoopTool_ReturnsOK(t *testing.T) {
+	res, err := handleNoopTool(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.IsError {
+		t.Fatalf("expected a successful result")
+	}
+}
+
+// TestToolsList_PaginatesOverGeneratedTools is skipped: mcp-go v0.23.1 has
+// no Streamable HTTP server at all (server.NewStreamableHTTPServer and
+// server.WithEndpointPath don't exist in this version — it ships SSE only,
+// see serveHTTP's doc comment in transport.go).
+func TestToolsList_PaginatesOverGeneratedTools(t *testing.T) {
+	t.Skip("mcp-go v0.23.1 has no Streamable HTTP server; see serveHTTP's doc comment in transport.go")
+}
+
+func TestHandleNoopResource_ReturnsGeneratedText(t *testing.T) {
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = "generated://noop/001"
+
+	contents, err := handleNoopResource(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(contents) != 1 {
+		t.Fatalf("len(contents) = %d, want 1", len(contents))
+	}
+}
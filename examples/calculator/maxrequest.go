@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxRequestBytes caps the size of a single JSON-RPC request body this
+// fixture will read, on both the sse/http/both transports' POST endpoints
+// and the stdio line reader. Zero disables the check. A real
+// server.WithMaxRequestBytes isn't a confirmed mcp-go option, so
+// withMaxRequestBytes (HTTP) and the line-length check in
+// serveStdioTolerant (stdio.go) are this fixture's own enforcement,
+// ahead of anything mcp-go or s.HandleMessage ever sees. main wires this
+// to --max-request-bytes, default 4MiB.
+var maxRequestBytes = 4 * 1024 * 1024
+
+// requestTooLargeCode is this fixture's own JSON-RPC error code for a
+// request that exceeded maxRequestBytes, in the same -32000-to-32099
+// server-error range rateLimitExceededCode and toolTimeoutCode use.
+const requestTooLargeCode = -32006
+
+// withMaxRequestBytes wraps next for the sse/http/both transports,
+// rejecting a POST body over maxRequestBytes with a JSON-RPC error before
+// it ever reaches requireStrictProtocol's own io.ReadAll(r.Body) (strict.go)
+// or mcp-go's handlers. GET passes through untouched: the SSE event
+// stream has no request body to bound here.
+func withMaxRequestBytes(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if maxRequestBytes <= 0 || r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		limited := http.MaxBytesReader(w, r.Body, int64(maxRequestBytes))
+		body, err := io.ReadAll(limited)
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				writeRequestTooLarge(w)
+				return
+			}
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// newStdioRequestTooLarge mirrors newStdioParseError/newStdioInvalidRequest
+// (stdio.go): a line over maxRequestBytes has no reliable id to echo back
+// either, so the response is id: null like those two.
+func newStdioRequestTooLarge() stdioErrorResponse {
+	return stdioErrorResponse{
+		JSONRPC: "2.0",
+		ID:      nil,
+		Error: stdioErrorBody{
+			Code:    requestTooLargeCode,
+			Message: fmt.Sprintf("request line exceeds the %d byte limit", maxRequestBytes),
+		},
+	}
+}
+
+func writeRequestTooLarge(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	_ = json.NewEncoder(w).Encode(stdioErrorResponse{
+		JSONRPC: "2.0",
+		ID:      nil,
+		Error: stdioErrorBody{
+			Code:    requestTooLargeCode,
+			Message: fmt.Sprintf("request body exceeds the %d byte limit", maxRequestBytes),
+		},
+	})
+}
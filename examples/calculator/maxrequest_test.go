@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func withTestMaxRequestBytes(t *testing.T, n int) {
+	t.Helper()
+	orig := maxRequestBytes
+	maxRequestBytes = n
+	t.Cleanup(func() { maxRequestBytes = orig })
+}
+
+func TestWithMaxRequestBytes_BelowLimitPassesThrough(t *testing.T) {
+	withTestMaxRequestBytes(t, 16)
+
+	var gotBody string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := make([]byte, 16)
+		n, _ := r.Body.Read(body)
+		gotBody = string(body[:n])
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader("short"))
+	w := httptest.NewRecorder()
+	withMaxRequestBytes(next).ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if gotBody != "short" {
+		t.Errorf("body = %q, want %q", gotBody, "short")
+	}
+}
+
+func TestWithMaxRequestBytes_AboveLimitIsRejected(t *testing.T) {
+	withTestMaxRequestBytes(t, 8)
+
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader("this body is way over the limit"))
+	w := httptest.NewRecorder()
+	withMaxRequestBytes(next).ServeHTTP(w, req)
+
+	if called {
+		t.Error("next should not be called for an over-limit body")
+	}
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+	if !strings.Contains(w.Body.String(), "request body exceeds") {
+		t.Errorf("body = %q, want it to mention the limit", w.Body.String())
+	}
+}
+
+func TestWithMaxRequestBytes_DisabledWhenZero(t *testing.T) {
+	withTestMaxRequestBytes(t, 0)
+
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(strings.Repeat("x", 1000)))
+	w := httptest.NewRecorder()
+	withMaxRequestBytes(next).ServeHTTP(w, req)
+
+	if !called {
+		t.Error("next should be called when maxRequestBytes is 0")
+	}
+}
+
+func TestWithMaxRequestBytes_GETIsNeverBuffered(t *testing.T) {
+	withTestMaxRequestBytes(t, 4)
+
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/sse", nil)
+	w := httptest.NewRecorder()
+	withMaxRequestBytes(next).ServeHTTP(w, req)
+
+	if !called {
+		t.Error("GET requests should always pass through untouched")
+	}
+}
+
+func TestServeStdioTolerant_OverLimitLineIsRejectedBeforeDispatch(t *testing.T) {
+	withTestMaxRequestBytes(t, 32)
+
+	line := `{"jsonrpc":"2.0","id":1,"method":"ping","params":{"padding":"` + strings.Repeat("x", 64) + `"}}`
+	if len(line) <= maxRequestBytes {
+		t.Fatalf("test line (%d bytes) must exceed maxRequestBytes (%d)", len(line), maxRequestBytes)
+	}
+
+	origStdin, origStdout := os.Stdin, os.Stdout
+	defer func() { os.Stdin, os.Stdout = origStdin, origStdout }()
+
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdin): %v", err)
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdout): %v", err)
+	}
+	os.Stdin, os.Stdout = stdinR, stdoutW
+
+	done := make(chan error, 1)
+	go func() { done <- serveStdioTolerant(context.Background(), newTestServer()) }()
+
+	writeLine(t, stdinW, line)
+
+	scanner := bufio.NewScanner(stdoutR)
+	if !scanner.Scan() {
+		t.Fatalf("expected a response line, scan error: %v", scanner.Err())
+	}
+	var resp stdioErrorResponse
+	if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshaling %q: %v", scanner.Text(), err)
+	}
+
+	stdinW.Close()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("serveStdioTolerant did not return after stdin closed")
+	}
+	stdoutW.Close()
+
+	if resp.Error.Code != requestTooLargeCode {
+		t.Errorf("Error.Code = %d, want %d", resp.Error.Code, requestTooLargeCode)
+	}
+	if resp.ID != nil {
+		t.Errorf("ID = %v, want nil", resp.ID)
+	}
+}
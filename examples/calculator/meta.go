@@ -0,0 +1,25 @@
+package main
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// progressTokenFromRequest returns the progressToken a client attached
+// to request's _meta, if any. Unlike tool arguments, _meta isn't decoded
+// anywhere into the context in the pinned mcp-go — mcp.CallToolRequest's
+// own Params.Meta field (nil unless the client actually sent one) is the
+// only place it lives — so reportProgress's callers read it from the
+// request itself, not from ctx.
+//
+// This is, today, the only piece of a request's _meta this fixture can
+// read at all: a general request.Params.Meta accessor for arbitrary
+// custom keys, or a server.MetaFromContext-style helper, isn't something
+// this fixture has a confirmed seam for beyond this one library-defined
+// field. newTracingMiddleware's doc comment already flagged the same gap
+// for a traceparent carried the same way.
+func progressTokenFromRequest(request mcp.CallToolRequest) (mcp.ProgressToken, bool) {
+	if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return nil, false
+	}
+	return request.Params.Meta.ProgressToken, true
+}
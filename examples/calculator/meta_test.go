@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestProgressTokenFromRequest_ReturnsTokenWhenSet(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Meta = &struct {
+		ProgressToken mcp.ProgressToken `json:"progressToken,omitempty"`
+	}{ProgressToken: mcp.ProgressToken("abc")}
+
+	token, ok := progressTokenFromRequest(req)
+	if !ok || token != mcp.ProgressToken("abc") {
+		t.Errorf("progressTokenFromRequest() = (%v, %v), want (abc, true)", token, ok)
+	}
+}
+
+func TestProgressTokenFromRequest_FalseWhenUnset(t *testing.T) {
+	_, ok := progressTokenFromRequest(mcp.CallToolRequest{})
+	if ok {
+		t.Error("progressTokenFromRequest() = ok=true on a request with no _meta, want false")
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolCallDurationBuckets are the histogram bucket boundaries (seconds) for
+// calculator_tool_call_duration_seconds, chosen to cover everything from a
+// near-instant tool like counter_get up to slow_add (one second by
+// default, longer when duration_ms asks for it).
+var toolCallDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// metricsState is this fixture's entire metrics store: plain counters and a
+// hand-rolled histogram, guarded by one mutex. The official Prometheus
+// client would give richer label and exposition support, but this
+// fixture's surface is small enough that a hand-rolled text exposition
+// covers it without a new dependency.
+var metricsState = struct {
+	mu                 sync.Mutex
+	requestsByMethod   map[string]int64
+	toolCallsByOutcome map[string]map[string]int64   // tool -> outcome("ok"/"error") -> count
+	toolCallDurations  map[string]*durationHistogram // tool -> histogram
+	activeSessions     map[string]struct{}
+}{
+	requestsByMethod:   map[string]int64{},
+	toolCallsByOutcome: map[string]map[string]int64{},
+	toolCallDurations:  map[string]*durationHistogram{},
+	activeSessions:     map[string]struct{}{},
+}
+
+// durationHistogram accumulates observations into toolCallDurationBuckets'
+// cumulative buckets, plus the running sum and count a Prometheus histogram
+// needs for its _sum/_count series.
+type durationHistogram struct {
+	bucketCounts []int64 // parallel to toolCallDurationBuckets, cumulative
+	sum          float64
+	count        int64
+}
+
+func newDurationHistogram() *durationHistogram {
+	return &durationHistogram{bucketCounts: make([]int64, len(toolCallDurationBuckets))}
+}
+
+func (h *durationHistogram) observe(seconds float64) {
+	for i, le := range toolCallDurationBuckets {
+		if seconds <= le {
+			h.bucketCounts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// recordRequest increments the counter for method, regardless of whether it
+// ends up dispatched to a tool/resource/prompt handler.
+func recordRequest(method string) {
+	metricsState.mu.Lock()
+	defer metricsState.mu.Unlock()
+	metricsState.requestsByMethod[method]++
+}
+
+// recordToolCall updates the outcome counter and duration histogram for one
+// completed tools/call.
+func recordToolCall(tool, outcome string, duration time.Duration) {
+	metricsState.mu.Lock()
+	defer metricsState.mu.Unlock()
+
+	if metricsState.toolCallsByOutcome[tool] == nil {
+		metricsState.toolCallsByOutcome[tool] = map[string]int64{}
+	}
+	metricsState.toolCallsByOutcome[tool][outcome]++
+
+	hist, ok := metricsState.toolCallDurations[tool]
+	if !ok {
+		hist = newDurationHistogram()
+		metricsState.toolCallDurations[tool] = hist
+	}
+	hist.observe(duration.Seconds())
+}
+
+func markSessionActive(sessionID string) {
+	if sessionID == "" {
+		return
+	}
+	metricsState.mu.Lock()
+	defer metricsState.mu.Unlock()
+	metricsState.activeSessions[sessionID] = struct{}{}
+}
+
+func markSessionInactive(sessionID string) {
+	metricsState.mu.Lock()
+	defer metricsState.mu.Unlock()
+	delete(metricsState.activeSessions, sessionID)
+}
+
+// registerMetricsHooks wires request/session counting into every inbound
+// message, via the same AddBeforeAny hook point latency.go and keepalive.go
+// use, plus AddAfterCallTool for per-tool outcome and duration tracking.
+// Because every tool added with addToolWithMiddleware (or AddTool directly)
+// dispatches through this same hook regardless of which middleware it
+// carries, a newly registered tool is counted automatically with no changes
+// to this file or the tool's own registration.
+func registerMetricsHooks(hooks *server.Hooks) {
+	var pending sync.Map // request id -> start time, for AddAfterCallTool's duration
+
+	hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+		recordRequest(string(method))
+		markSessionActive(sessionIDFromContext(ctx))
+		if method == "tools/call" {
+			pending.Store(id, time.Now())
+		}
+	})
+
+	hooks.AddAfterCallTool(func(ctx context.Context, id any, message *mcp.CallToolRequest, result *mcp.CallToolResult) {
+		var duration time.Duration
+		if start, ok := pending.LoadAndDelete(id); ok {
+			duration = time.Since(start.(time.Time))
+		}
+		outcome := "ok"
+		if result == nil || result.IsError {
+			outcome = "error"
+		}
+		recordToolCall(message.Params.Name, outcome, duration)
+	})
+
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		markSessionInactive(session.SessionID())
+	})
+}
+
+// mountMetricsEndpoint registers /metrics on mux, the same mux the
+// sse/http/both transports serve their MCP endpoints from. Like /healthz and
+// /readyz, it never touches MCP session machinery.
+func mountMetricsEndpoint(mux *http.ServeMux) {
+	mux.HandleFunc("/metrics", handleMetrics)
+}
+
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write([]byte(renderMetrics()))
+}
+
+// renderMetrics builds the full Prometheus text-exposition payload. It's a
+// plain function returning a string, rather than writing straight to an
+// io.Writer, so tests can assert on the rendered text directly.
+func renderMetrics() string {
+	metricsState.mu.Lock()
+	defer metricsState.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP calculator_requests_total Total JSON-RPC requests received, by method.\n")
+	b.WriteString("# TYPE calculator_requests_total counter\n")
+	for _, method := range sortedKeys(metricsState.requestsByMethod) {
+		fmt.Fprintf(&b, "calculator_requests_total{method=%q} %d\n", method, metricsState.requestsByMethod[method])
+	}
+
+	b.WriteString("# HELP calculator_tool_calls_total Total tools/call invocations, by tool name and outcome.\n")
+	b.WriteString("# TYPE calculator_tool_calls_total counter\n")
+	for _, tool := range sortedKeys(metricsState.toolCallsByOutcome) {
+		byOutcome := metricsState.toolCallsByOutcome[tool]
+		for _, outcome := range sortedKeys(byOutcome) {
+			fmt.Fprintf(&b, "calculator_tool_calls_total{tool=%q,outcome=%q} %d\n", tool, outcome, byOutcome[outcome])
+		}
+	}
+
+	b.WriteString("# HELP calculator_active_sessions Number of sessions this process currently considers active.\n")
+	b.WriteString("# TYPE calculator_active_sessions gauge\n")
+	fmt.Fprintf(&b, "calculator_active_sessions %d\n", len(metricsState.activeSessions))
+
+	b.WriteString("# HELP calculator_tool_call_duration_seconds Histogram of tools/call handler duration, by tool name.\n")
+	b.WriteString("# TYPE calculator_tool_call_duration_seconds histogram\n")
+	for _, tool := range sortedKeys(metricsState.toolCallDurations) {
+		hist := metricsState.toolCallDurations[tool]
+		for i, le := range toolCallDurationBuckets {
+			fmt.Fprintf(&b, "calculator_tool_call_duration_seconds_bucket{tool=%q,le=%q} %d\n", tool, formatLe(le), hist.bucketCounts[i])
+		}
+		fmt.Fprintf(&b, "calculator_tool_call_duration_seconds_bucket{tool=%q,le=\"+Inf\"} %d\n", tool, hist.count)
+		fmt.Fprintf(&b, "calculator_tool_call_duration_seconds_sum{tool=%q} %g\n", tool, hist.sum)
+		fmt.Fprintf(&b, "calculator_tool_call_duration_seconds_count{tool=%q} %d\n", tool, hist.count)
+	}
+
+	return b.String()
+}
+
+func formatLe(bucket float64) string {
+	return fmt.Sprintf("%g", bucket)
+}
+
+// sortedKeys returns m's keys sorted, so renderMetrics' output is
+// deterministic across calls (and therefore easy to assert on in tests).
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
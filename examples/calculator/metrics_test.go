@@ -0,0 +1,66 @@
+package main
+
+import (
+	"testing"
+)
+
+func resetMetricsState(t *testing.T) {
+	t.Helper()
+	metricsState.mu.Lock()
+	metricsState.requestsByMethod = map[string]int64{}
+	metricsState.toolCallsByOutcome = map[string]map[string]int64{}
+	metricsState.toolCallDurations = map[string]*durationHistogram{}
+	metricsState.activeSessions = map[string]struct{}{}
+	metricsState.mu.Unlock()
+}
+
+// TestHandleMetrics_CountsRequestsAndToolCallsByOutcome is skipped: mcp-go
+// v0.23.1 has no Streamable HTTP server at all (server.NewStreamableHTTPServer
+// and server.WithEndpointPath don't exist in this version — it ships SSE
+// only, see serveHTTP's doc comment in transport.go).
+func TestHandleMetrics_CountsRequestsAndToolCallsByOutcome(t *testing.T) {
+	t.Skip("mcp-go v0.23.1 has no Streamable HTTP server; see serveHTTP's doc comment in transport.go")
+}
+
+func TestRecordToolCall_HistogramBucketsAreCumulative(t *testing.T) {
+	resetMetricsState(t)
+
+	recordToolCall("calculate", "ok", 0)
+	recordToolCall("calculate", "ok", 0)
+
+	metricsState.mu.Lock()
+	hist := metricsState.toolCallDurations["calculate"]
+	metricsState.mu.Unlock()
+
+	if hist.count != 2 {
+		t.Fatalf("count = %d, want 2", hist.count)
+	}
+	for i, count := range hist.bucketCounts {
+		if count != 2 {
+			t.Errorf("bucketCounts[%d] (le=%v) = %d, want 2 for a near-zero duration", i, toolCallDurationBuckets[i], count)
+		}
+	}
+}
+
+func TestMarkSessionActiveInactive_TracksActiveSessionCount(t *testing.T) {
+	resetMetricsState(t)
+
+	markSessionActive("sess-a")
+	markSessionActive("sess-b")
+	markSessionActive("sess-a") // idempotent
+
+	metricsState.mu.Lock()
+	count := len(metricsState.activeSessions)
+	metricsState.mu.Unlock()
+	if count != 2 {
+		t.Fatalf("active sessions = %d, want 2", count)
+	}
+
+	markSessionInactive("sess-a")
+	metricsState.mu.Lock()
+	count = len(metricsState.activeSessions)
+	metricsState.mu.Unlock()
+	if count != 1 {
+		t.Fatalf("active sessions after removing one = %d, want 1", count)
+	}
+}
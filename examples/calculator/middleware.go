@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// recoverEnabled gates recoverMiddleware. It defaults to on; main wires it
+// to --no-recover for users who'd rather crash loudly in development.
+var recoverEnabled = true
+
+// toolTimeout is the deadline newTimeoutMiddleware enforces on the tools it
+// wraps. Its default sits comfortably above slow_add's own 60s duration_ms
+// ceiling, so it only ever fires as a genuine backstop against a wedged
+// handler, not an everyday caller. main wires it to --tool-timeout; it has
+// no effect on tools that don't apply the middleware (deliberately
+// unbounded ones like simulate_hang, or ones with their own cancellation
+// story like ask_model).
+var toolTimeout = 90 * time.Second
+
+// toolTimeoutCode is the JSON-RPC error code newTimeoutMiddleware reports
+// when its deadline elapses, kept distinct from context.Canceled (a client-
+// supplied cancellation) so a caller can tell the two apart.
+const toolTimeoutCode = -32001
+
+// newTimeoutMiddleware derives a context with a d deadline for each call.
+// If the handler is still running when the deadline passes, its own ctx is
+// cancelled (so a well-behaved handler unwinds promptly, the way slow_add
+// already does on every iteration) and a toolTimeoutCode error is reported
+// in its place; the handler's eventual return value, if any, is discarded.
+// A client-supplied cancellation that fires first is reported as the
+// ordinary context.Canceled error instead, so the two causes are never
+// confused with each other. d <= 0 disables the middleware entirely.
+func newTimeoutMiddleware(d time.Duration) toolMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if d <= 0 {
+				return next(ctx, request)
+			}
+
+			timeoutCtx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			type outcome struct {
+				result *mcp.CallToolResult
+				err    error
+			}
+			done := make(chan outcome, 1)
+			go func() {
+				result, err := next(timeoutCtx, request)
+				done <- outcome{result, err}
+			}()
+
+			select {
+			case o := <-done:
+				return o.result, o.err
+			case <-timeoutCtx.Done():
+				if timeoutCtx.Err() == context.DeadlineExceeded {
+					return nil, &protocolError{code: toolTimeoutCode, message: fmt.Sprintf("tool %s exceeded its %s timeout", request.Params.Name, d)}
+				}
+				return nil, ctx.Err()
+			}
+		}
+	}
+}
+
+// recoverMiddleware converts a panicking handler into a JSON-RPC internal
+// error (-32603) instead of taking the whole process (and every other
+// in-flight session) down. The panic message and a stack trace go to the
+// server logger (stderr), tagged with the tool name, so it's traceable; the
+// raw recovered value also rides along in the error's Data field for a
+// client that wants more than the flattened message string; the session
+// itself stays alive.
+func recoverMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (result *mcp.CallToolResult, err error) {
+		if !recoverEnabled {
+			return next(ctx, request)
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("panic in tool %s (session %s): %v\n%s", request.Params.Name, sessionIDFromContext(ctx), r, debug.Stack())
+				result, err = nil, &protocolError{
+					code:    mcp.INTERNAL_ERROR,
+					message: fmt.Sprintf("tool %s panicked: %v", request.Params.Name, r),
+					data:    fmt.Sprintf("%v", r),
+				}
+			}
+		}()
+
+		return next(ctx, request)
+	}
+}
+
+// recoverResourceHandler wraps a resources/read handler the same way
+// recoverMiddleware wraps a tool handler. Resources and prompts don't go
+// through addToolWithMiddleware's toolMiddleware chain (they're not
+// server.ToolHandlerFunc), so each registration site wraps with this
+// directly instead.
+func recoverResourceHandler(uri string, next func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error)) func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) (result []mcp.ResourceContents, err error) {
+		if !recoverEnabled {
+			return next(ctx, request)
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("panic in resource %s (session %s): %v\n%s", uri, sessionIDFromContext(ctx), r, debug.Stack())
+				result, err = nil, &protocolError{
+					code:    mcp.INTERNAL_ERROR,
+					message: fmt.Sprintf("resource %s panicked: %v", uri, r),
+					data:    fmt.Sprintf("%v", r),
+				}
+			}
+		}()
+
+		return next(ctx, request)
+	}
+}
+
+// recoverPromptHandler is recoverResourceHandler's counterpart for
+// prompts/get handlers.
+func recoverPromptHandler(name string, next func(context.Context, mcp.GetPromptRequest) (*mcp.GetPromptResult, error)) func(context.Context, mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (result *mcp.GetPromptResult, err error) {
+		if !recoverEnabled {
+			return next(ctx, request)
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("panic in prompt %s (session %s): %v\n%s", name, sessionIDFromContext(ctx), r, debug.Stack())
+				result, err = nil, &protocolError{
+					code:    mcp.INTERNAL_ERROR,
+					message: fmt.Sprintf("prompt %s panicked: %v", name, r),
+					data:    fmt.Sprintf("%v", r),
+				}
+			}
+		}()
+
+		return next(ctx, request)
+	}
+}
+
+// toolMiddleware wraps a tool handler with cross-cutting behavior (auth,
+// metrics, logging) without touching the handler itself. Middlewares
+// compose in registration order: the first one passed to addToolWithMiddleware
+// is the outermost, so it sees the request first and the result last.
+type toolMiddleware func(next server.ToolHandlerFunc) server.ToolHandlerFunc
+
+// addToolWithMiddleware registers tool on s with handler wrapped by mws, in
+// the order given.
+func addToolWithMiddleware(s *server.MCPServer, tool mcp.Tool, handler server.ToolHandlerFunc, mws ...toolMiddleware) {
+	s.AddTool(tool, composeToolMiddleware(handler, mws...))
+}
+
+// composeToolMiddleware wraps handler with mws in the order given: the
+// first middleware is outermost, so it runs first on the way in and last
+// on the way out.
+func composeToolMiddleware(handler server.ToolHandlerFunc, mws ...toolMiddleware) server.ToolHandlerFunc {
+	for i := len(mws) - 1; i >= 0; i-- {
+		handler = mws[i](handler)
+	}
+	return handler
+}
+
+// loggingMiddleware logs each tool invocation through structuredLogger: Info
+// on success, Error on failure, both tagged with the tool name, session id,
+// and call duration so a log pipeline can filter/aggregate by any of them.
+func loggingMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := next(ctx, request)
+		duration := time.Since(start)
+
+		attrs := []any{
+			slog.String("tool", request.Params.Name),
+			slog.String("session_id", sessionIDFromContext(ctx)),
+			slog.Duration("duration", duration),
+		}
+		if err != nil {
+			structuredLogger.Error("tool call failed", append(attrs, slog.Any("error", err))...)
+		} else if result != nil && result.IsError {
+			structuredLogger.Error("tool call returned an error result", attrs...)
+		} else {
+			structuredLogger.Info("tool call succeeded", attrs...)
+		}
+
+		return result, err
+	}
+}
+
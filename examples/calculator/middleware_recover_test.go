@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRecoverMiddleware_ConvertsPanicToInternalError(t *testing.T) {
+	recoverEnabled = true
+
+	panicking := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args []int
+		_ = args[0] // index out of range
+		return nil, nil
+	}
+
+	wrapped := recoverMiddleware(panicking)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "boom"
+
+	result, err := wrapped(context.Background(), req)
+	if result != nil {
+		t.Errorf("result = %v, want nil", result)
+	}
+
+	pe, ok := err.(*protocolError)
+	if !ok {
+		t.Fatalf("err = %T, want *protocolError", err)
+	}
+	if pe.Code() != mcp.INTERNAL_ERROR {
+		t.Errorf("code = %d, want %d", pe.Code(), mcp.INTERNAL_ERROR)
+	}
+}
+
+func TestRecoverMiddleware_CarriesThePanicValueInData(t *testing.T) {
+	recoverEnabled = true
+
+	panicking := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		panic("boom")
+	}
+
+	_, err := recoverMiddleware(panicking)(context.Background(), mcp.CallToolRequest{})
+
+	pe, ok := err.(*protocolError)
+	if !ok {
+		t.Fatalf("err = %T, want *protocolError", err)
+	}
+	if pe.Data() != "boom" {
+		t.Errorf("Data() = %v, want %q", pe.Data(), "boom")
+	}
+}
+
+func TestRecoverResourceHandler_ConvertsPanicToInternalError(t *testing.T) {
+	recoverEnabled = true
+
+	panicking := func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		panic("resource boom")
+	}
+
+	_, err := recoverResourceHandler("test://panics", panicking)(context.Background(), mcp.ReadResourceRequest{})
+
+	pe, ok := err.(*protocolError)
+	if !ok {
+		t.Fatalf("err = %T, want *protocolError", err)
+	}
+	if pe.Code() != mcp.INTERNAL_ERROR {
+		t.Errorf("code = %d, want %d", pe.Code(), mcp.INTERNAL_ERROR)
+	}
+}
+
+func TestRecoverPromptHandler_ConvertsPanicToInternalError(t *testing.T) {
+	recoverEnabled = true
+
+	panicking := func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		panic("prompt boom")
+	}
+
+	_, err := recoverPromptHandler("test_prompt", panicking)(context.Background(), mcp.GetPromptRequest{})
+
+	pe, ok := err.(*protocolError)
+	if !ok {
+		t.Fatalf("err = %T, want *protocolError", err)
+	}
+	if pe.Code() != mcp.INTERNAL_ERROR {
+		t.Errorf("code = %d, want %d", pe.Code(), mcp.INTERNAL_ERROR)
+	}
+}
+
+func TestRecoverMiddleware_DisabledLetsPanicPropagate(t *testing.T) {
+	recoverEnabled = false
+	defer func() { recoverEnabled = true }()
+
+	panicking := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		panic("boom")
+	}
+
+	wrapped := recoverMiddleware(panicking)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic to propagate when recoverEnabled is false")
+		}
+	}()
+	wrapped(context.Background(), mcp.CallToolRequest{})
+}
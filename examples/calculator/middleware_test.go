@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestComposeToolMiddleware_RunsInRegistrationOrder(t *testing.T) {
+	var order []string
+
+	mark := func(name string) toolMiddleware {
+		return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+			return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				order = append(order, name)
+				return next(ctx, request)
+			}
+		}
+	}
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		order = append(order, "handler")
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	wrapped := composeToolMiddleware(handler, mark("outer"), mark("inner"))
+	if _, err := wrapped(context.Background(), mcp.CallToolRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"outer", "inner", "handler"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("order = %v, want %v", order, want)
+			break
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestNewTimeoutMiddleware_SlowHandlerReportsADistinctTimeoutError(t *testing.T) {
+	never := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	wrapped := newTimeoutMiddleware(10 * time.Millisecond)(never)
+	_, err := wrapped(context.Background(), mcp.CallToolRequest{})
+
+	pe, ok := err.(*protocolError)
+	if !ok {
+		t.Fatalf("err = %T, want *protocolError", err)
+	}
+	if pe.Code() != toolTimeoutCode {
+		t.Errorf("code = %d, want %d", pe.Code(), toolTimeoutCode)
+	}
+}
+
+func TestNewTimeoutMiddleware_ClientCancellationIsReportedAsCanceledNotTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	never := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	wrapped := newTimeoutMiddleware(time.Hour)(never)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := wrapped(ctx, mcp.CallToolRequest{})
+		done <- err
+	}()
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+		if _, ok := err.(*protocolError); ok {
+			t.Errorf("got a *protocolError for a client cancellation, want context.Canceled")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("wrapped handler did not return after the client cancelled")
+	}
+}
+
+func TestNewTimeoutMiddleware_FastHandlerPassesThroughUnaffected(t *testing.T) {
+	fast := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	wrapped := newTimeoutMiddleware(time.Second)(fast)
+	result, err := wrapped(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Content[0].(mcp.TextContent).Text != "ok" {
+		t.Errorf("got %v, want the fast handler's own result", result)
+	}
+}
+
+func TestNewTimeoutMiddleware_ZeroDisablesEnforcement(t *testing.T) {
+	never := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("still ran"), nil
+	}
+
+	wrapped := newTimeoutMiddleware(0)(never)
+	result, err := wrapped(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Content[0].(mcp.TextContent).Text != "still ran" {
+		t.Errorf("got %v, want the handler's own result", result)
+	}
+}
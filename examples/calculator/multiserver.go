@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// secondServerPrefix, if non-empty, mounts a second, independent MCP
+// server (see newEchoServer) alongside the calculator server on the
+// sse/both transports' shared mux, under this path prefix. It exists to
+// demonstrate and exercise mounting several logical MCP servers on one
+// *http.ServeMux, each with its own SSEServer and therefore its own
+// session table: server.WithBasePath(baseURL) is what gets echoed into
+// the `endpoint` event a mounted SSEServer sends clients, so a relative
+// base path like "/echo" is all a second mount needs to tell clients to
+// POST back to "/echo/message" instead of "/message". main wires this to
+// --second-server-prefix (empty disables).
+var secondServerPrefix = ""
+
+// newEchoServer builds the second MCP server secondServerPrefix mounts: a
+// single-tool server unrelated to the calculator, so its isolation from
+// the primary server (distinct session table, distinct capabilities) is
+// obvious rather than incidental.
+func newEchoServer() *server.MCPServer {
+	s := server.NewMCPServer("Echo", "0.1.0")
+	s.AddTool(
+		mcp.NewTool("echo",
+			mcp.WithDescription("Return the given text unchanged"),
+			mcp.WithString("text", mcp.Required()),
+		),
+		handleSecondServerEchoTool,
+	)
+	return s
+}
+
+// handleSecondServerEchoTool is newEchoServer's own echo handler, distinct
+// from the calculator server's handleEchoTool in tools.go: this one takes
+// a plain "text" string argument instead of an arbitrary "payload" value.
+func handleSecondServerEchoTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	text, ok := request.Params.Arguments["text"].(string)
+	if !ok {
+		return mcp.NewToolResultError("missing required argument: text"), nil
+	}
+	return mcp.NewToolResultText(text), nil
+}
+
+// mountSecondServer registers newEchoServer's SSE and message endpoints
+// on mux under prefix, alongside whatever the primary calculator server
+// is already mounted at. Each mounted server gets its own *server.SSEServer
+// instance, so their sessions, subscriptions, and keep-alive goroutines
+// never cross between servers.
+func mountSecondServer(mux *http.ServeMux, prefix string) {
+	echoSSE := server.NewSSEServer(newEchoServer(),
+		server.WithBasePath(prefix),
+		server.WithSSEEndpoint("/sse"),
+		server.WithMessageEndpoint("/message"),
+	)
+	mux.Handle(prefix+"/sse", echoSSE)
+	mux.Handle(prefix+"/message", echoSSE)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestRequireFinite_RejectsNaNAndInf(t *testing.T) {
+	cases := []float64{math.NaN(), math.Inf(1), math.Inf(-1)}
+	for _, v := range cases {
+		if err := requireFinite(v); err == nil {
+			t.Errorf("requireFinite(%v) = nil, want an error", v)
+		}
+	}
+}
+
+func TestRequireFinite_AcceptsFiniteValues(t *testing.T) {
+	cases := []float64{0, 1, -1, 1e300, -1e300}
+	for _, v := range cases {
+		if err := requireFinite(v); err != nil {
+			t.Errorf("requireFinite(%v) = %v, want nil", v, err)
+		}
+	}
+}
+
+func TestHandleCalculateBatchTool_NonFiniteResultIsReportedPerOperation(t *testing.T) {
+	ops := []any{
+		map[string]any{"operation": "power", "x": 1e300, "y": 10.0},
+	}
+
+	res, err := handleCalculateBatchTool(context.Background(), batchRequest(ops))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results := decodeBatchResults(t, res)
+	if len(results) != 1 {
+		t.Fatalf("results = %v, want a single batchResult", results)
+	}
+	if results[0].Error == "" {
+		t.Errorf("results[0] = %+v, want a non-finite-result error", results[0])
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// operation is one entry in operationTable: a canonical name, any short
+// aliases older clients might still send, and the function that computes
+// it. sqrt is unary and ignores y entirely.
+type operation struct {
+	name    string
+	aliases []string
+	apply   func(x, y float64) (float64, error)
+}
+
+// operationTable is the single source of truth for the calculate tool's
+// enum, description, and handler, so the three can't drift apart the way
+// they could when each spelled out "add, subtract, multiply, divide"
+// separately.
+var operationTable = []operation{
+	{name: "add", apply: func(x, y float64) (float64, error) { return x + y, nil }},
+	{name: "subtract", aliases: []string{"sub"}, apply: func(x, y float64) (float64, error) { return x - y, nil }},
+	{name: "multiply", aliases: []string{"mult"}, apply: func(x, y float64) (float64, error) { return x * y, nil }},
+	{name: "divide", aliases: []string{"div"}, apply: func(x, y float64) (float64, error) {
+		if y == 0 {
+			return 0, fmt.Errorf("Cannot divide by zero")
+		}
+		return x / y, nil
+	}},
+	{name: "power", aliases: []string{"pow"}, apply: func(x, y float64) (float64, error) {
+		if x == 0 && y < 0 {
+			return 0, fmt.Errorf("0 raised to a negative power is undefined")
+		}
+		return math.Pow(x, y), nil
+	}},
+	{name: "modulo", aliases: []string{"mod"}, apply: func(x, y float64) (float64, error) {
+		if y == 0 {
+			return 0, fmt.Errorf("cannot modulo by zero")
+		}
+		return math.Mod(x, y), nil
+	}},
+	{name: "sqrt", apply: func(x, _ float64) (float64, error) {
+		if x < 0 {
+			return 0, fmt.Errorf("cannot take the square root of a negative number")
+		}
+		return math.Sqrt(x), nil
+	}},
+}
+
+// requireFinite rejects NaN and +/-Inf, which every float64-producing tool
+// (calculate, evaluate, and calculate_batch and render_result/describe_result
+// transitively through calculate) must check before putting a result into a
+// text or structured content block: JSON has no way to encode either one,
+// so letting one through would hand a client unparseable output instead of
+// a clear error.
+func requireFinite(result float64) error {
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return fmt.Errorf("result is not a finite number (%v)", result)
+	}
+	return nil
+}
+
+// lookupOperation resolves op (canonical name or alias) against
+// operationTable.
+func lookupOperation(op string) (operation, bool) {
+	for _, def := range operationTable {
+		if def.name == op {
+			return def, true
+		}
+		for _, alias := range def.aliases {
+			if alias == op {
+				return def, true
+			}
+		}
+	}
+	return operation{}, false
+}
+
+// operationNames returns operationTable's canonical names, in table order,
+// for the tool's enum and description.
+func operationNames() []string {
+	names := make([]string, len(operationTable))
+	for i, def := range operationTable {
+		names[i] = def.name
+	}
+	return names
+}
+
+// operationsDescription renders operationNames as prose for the calculate
+// tool's "operation" argument description.
+func operationsDescription() string {
+	return strings.Join(operationNames(), ", ")
+}
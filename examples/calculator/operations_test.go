@@ -0,0 +1,98 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculate_NewOperations(t *testing.T) {
+	cases := []struct {
+		op   string
+		x, y float64
+		want float64
+	}{
+		{"power", 2, 10, 1024},
+		{"pow", 2, 0, 1},
+		{"modulo", 10, 3, 1},
+		{"mod", -10, 3, -1},
+		{"sqrt", 16, 0, 4},
+		{"sqrt", 0, 0, 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.op, func(t *testing.T) {
+			got, err := calculate(tc.op, tc.x, tc.y)
+			if err != nil {
+				t.Fatalf("calculate(%q, %v, %v) returned error: %v", tc.op, tc.x, tc.y, err)
+			}
+			if got != tc.want {
+				t.Errorf("calculate(%q, %v, %v) = %v, want %v", tc.op, tc.x, tc.y, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCalculate_DomainErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		op   string
+		x, y float64
+	}{
+		{"sqrt of a negative number", "sqrt", -1, 0},
+		{"modulo by zero", "modulo", 1, 0},
+		{"zero to a negative power", "power", 0, -1},
+		{"divide by zero", "divide", 1, 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := calculate(tc.op, tc.x, tc.y)
+			if err == nil {
+				t.Fatalf("calculate(%q, %v, %v) returned no error", tc.op, tc.x, tc.y)
+			}
+			if _, ok := err.(*protocolError); ok {
+				t.Errorf("err = %T, want a plain domain error, not a protocol error", err)
+			}
+		})
+	}
+}
+
+func TestCalculate_NonFiniteResultIsAnError(t *testing.T) {
+	cases := []struct {
+		name string
+		op   string
+		x, y float64
+	}{
+		{"overflow to +Inf", "power", 1e300, 10},
+		{"NaN from Inf minus Inf", "subtract", math.Inf(1), math.Inf(1)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := calculate(tc.op, tc.x, tc.y)
+			if err == nil {
+				t.Fatalf("calculate(%q, %v, %v) returned no error for a non-finite result", tc.op, tc.x, tc.y)
+			}
+		})
+	}
+}
+
+func TestLookupOperation_UnsupportedOperationIsInvalidParams(t *testing.T) {
+	_, err := calculate("bogus", 1, 1)
+	if _, ok := err.(*protocolError); !ok {
+		t.Fatalf("err = %T, want *protocolError", err)
+	}
+}
+
+func TestOperationNames_MatchesTableOrder(t *testing.T) {
+	want := []string{"add", "subtract", "multiply", "divide", "power", "modulo", "sqrt"}
+	got := operationNames()
+	if len(got) != len(want) {
+		t.Fatalf("operationNames() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("operationNames()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// originConfig controls requireAllowedOrigin's Origin-header check on the
+// sse/http/both transports, guarding against DNS-rebinding attacks the way
+// the MCP spec recommends. Unlike corsConfig (which only ever adds
+// response headers for origins it recognizes), this one can reject a
+// request outright, so it's a separate, narrower type rather than another
+// field bolted onto corsConfig.
+type originConfig struct {
+	AllowedOrigins []string
+	// RequireOrigin rejects a request with no Origin header at all. Off by
+	// default: most MCP clients are not browsers and never send one, and
+	// DNS-rebinding is specifically a browser-borne attack.
+	RequireOrigin bool
+}
+
+func newOriginConfig(allowedOrigins []string, requireOrigin bool) *originConfig {
+	return &originConfig{AllowedOrigins: allowedOrigins, RequireOrigin: requireOrigin}
+}
+
+func (c *originConfig) allows(origin string) bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// requireAllowedOrigin wraps next for the sse/both transports' stream and
+// message endpoints, rejecting a request whose Origin
+// header is present but not in cfg.AllowedOrigins with 403. A request with
+// no Origin header passes through unless cfg.RequireOrigin is set. A nil
+// cfg (no -allowed-origins configured) is a no-op passthrough.
+func requireAllowedOrigin(cfg *originConfig, next http.Handler) http.Handler {
+	if cfg == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			if cfg.RequireOrigin {
+				writeOriginRejected(w, "missing Origin header")
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !cfg.allows(origin) {
+			writeOriginRejected(w, "origin not allowed")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeOriginRejected(w http.ResponseWriter, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	_ = json.NewEncoder(w).Encode(authErrorBody{Error: reason})
+}
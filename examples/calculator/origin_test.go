@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireAllowedOrigin_NilConfigIsPassthrough(t *testing.T) {
+	srv := httptest.NewServer(requireAllowedOrigin(nil, okHandler()))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestRequireAllowedOrigin_AllowedOriginPassesThrough(t *testing.T) {
+	cfg := newOriginConfig([]string{"https://app.example.com"}, false)
+	srv := httptest.NewServer(requireAllowedOrigin(cfg, okHandler()))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Origin", "https://app.example.com")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestRequireAllowedOrigin_DisallowedOriginIsForbidden(t *testing.T) {
+	cfg := newOriginConfig([]string{"https://app.example.com"}, false)
+	srv := httptest.NewServer(requireAllowedOrigin(cfg, okHandler()))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusForbidden)
+	}
+	var body authErrorBody
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding JSON error body: %v", err)
+	}
+	if body.Error == "" {
+		t.Error("expected a non-empty error message in the JSON body")
+	}
+}
+
+func TestRequireAllowedOrigin_AbsentOriginPassesThroughByDefault(t *testing.T) {
+	cfg := newOriginConfig([]string{"https://app.example.com"}, false)
+	srv := httptest.NewServer(requireAllowedOrigin(cfg, okHandler()))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d (no Origin header should pass by default)", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestRequireAllowedOrigin_RequireOriginRejectsAbsentOrigin(t *testing.T) {
+	cfg := newOriginConfig([]string{"https://app.example.com"}, true)
+	srv := httptest.NewServer(requireAllowedOrigin(cfg, okHandler()))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusForbidden)
+	}
+}
+
+func TestRequireAllowedOrigin_WildcardAllowsAnyOrigin(t *testing.T) {
+	cfg := newOriginConfig([]string{"*"}, false)
+	srv := httptest.NewServer(requireAllowedOrigin(cfg, okHandler()))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Origin", "https://anything.example.com")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
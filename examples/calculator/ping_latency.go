@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// serverPingInterval, when non-zero, makes stdio.go and ws.go each run a
+// per-connection goroutine (see runServerPing) that sends the connection a
+// JSON-RPC `ping` request on this cadence and records the round-trip
+// latency of the matching response.
+//
+// This only runs for stdio and websocket: those are the two transports
+// where this fixture owns the connection's read loop directly (stdio.go,
+// ws.go both call s.HandleMessage themselves on each incoming line/frame),
+// so an unsolicited server-to-client request can be written to the same
+// connection and its reply recognized on the way back in. The sse/http
+// transports hand every request straight to mcp-go's own SSEServer (the
+// http transport has none), which owns that response stream end to end — the
+// same gap already documented in sse_keepalive.go and compression.go — so
+// there's no confirmed way to push an unsolicited `ping` down the open SSE
+// stream and correlate a reply to it from out here. main wires this to
+// --ping-interval (0 disables).
+var serverPingInterval = time.Duration(0)
+
+// serverPingMaxMissed, when positive, makes runServerPing call its evict
+// callback once a connection has failed to answer this many consecutive
+// pings. Zero (the default) means never evict on missed pings.
+var serverPingMaxMissed = 0
+
+// pingStats tracks one connection's outstanding ping and running latency
+// figures. sessionID-keyed storage (pingStatsRegistry below) lets the
+// connection_stats tool report on the calling session without threading
+// the tracker through context itself.
+type pingStats struct {
+	mu                sync.Mutex
+	nextID            int64
+	outstanding       int64 // -1 when no ping is awaiting a reply
+	sentAt            time.Time
+	consecutiveMissed int
+	pingCount         int64
+	lastLatencyMS     float64
+	totalLatencyMS    float64
+	resolved          chan int64
+}
+
+func newPingStats() *pingStats {
+	return &pingStats{outstanding: -1, resolved: make(chan int64, 1)}
+}
+
+// sendNew allocates the next ping id, marks it outstanding, and returns
+// the JSON-RPC request body to write to the connection.
+func (p *pingStats) sendNew() []byte {
+	p.mu.Lock()
+	p.nextID++
+	id := p.nextID
+	p.outstanding = id
+	p.sentAt = time.Now()
+	p.mu.Unlock()
+
+	body, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": id, "method": "ping"})
+	return body
+}
+
+// hasOutstanding reports whether the most recently sent ping is still
+// unanswered.
+func (p *pingStats) hasOutstanding() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.outstanding != -1
+}
+
+// recordMissed marks the outstanding ping as missed and returns the new
+// consecutive-miss count.
+func (p *pingStats) recordMissed() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.outstanding = -1
+	p.consecutiveMissed++
+	return p.consecutiveMissed
+}
+
+// resolve records a reply to ping id, if it's the one currently
+// outstanding; a reply to a ping that's already been counted missed (or
+// was never sent) is ignored.
+func (p *pingStats) resolve(id int64) {
+	p.mu.Lock()
+	if p.outstanding != id {
+		p.mu.Unlock()
+		return
+	}
+	latencyMS := float64(time.Since(p.sentAt).Microseconds()) / 1000
+	p.lastLatencyMS = latencyMS
+	p.totalLatencyMS += latencyMS
+	p.pingCount++
+	p.consecutiveMissed = 0
+	p.outstanding = -1
+	p.mu.Unlock()
+
+	select {
+	case p.resolved <- id:
+	default:
+	}
+}
+
+type pingStatsSnapshot struct {
+	PingCount         int64   `json:"ping_count"`
+	LastLatencyMS     float64 `json:"last_latency_ms"`
+	AverageLatencyMS  float64 `json:"average_latency_ms"`
+	ConsecutiveMissed int     `json:"consecutive_missed"`
+}
+
+func (p *pingStats) snapshot() pingStatsSnapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var avg float64
+	if p.pingCount > 0 {
+		avg = p.totalLatencyMS / float64(p.pingCount)
+	}
+	return pingStatsSnapshot{
+		PingCount:         p.pingCount,
+		LastLatencyMS:     p.lastLatencyMS,
+		AverageLatencyMS:  avg,
+		ConsecutiveMissed: p.consecutiveMissed,
+	}
+}
+
+// pingStatsRegistry follows the mutex-guarded map[string]T convention
+// subscriptions.go, sessionstore.go, and sse_keepalive.go all use for
+// per-session state.
+var pingStatsRegistry = struct {
+	mu   sync.Mutex
+	byID map[string]*pingStats
+}{byID: map[string]*pingStats{}}
+
+func startPingStats(sessionID string) *pingStats {
+	pingStatsRegistry.mu.Lock()
+	defer pingStatsRegistry.mu.Unlock()
+	stats := newPingStats()
+	pingStatsRegistry.byID[sessionID] = stats
+	return stats
+}
+
+func stopPingStats(sessionID string) {
+	pingStatsRegistry.mu.Lock()
+	defer pingStatsRegistry.mu.Unlock()
+	delete(pingStatsRegistry.byID, sessionID)
+}
+
+func getPingStats(sessionID string) *pingStats {
+	pingStatsRegistry.mu.Lock()
+	defer pingStatsRegistry.mu.Unlock()
+	return pingStatsRegistry.byID[sessionID]
+}
+
+// jsonrpcResponseShape is used only to recognize that an incoming line is
+// a JSON-RPC response (has an id and a result or error, but no method) as
+// opposed to a request or notification — the shape runServerPing's
+// replies come back as, since this fixture's own id allocation
+// (pingStats.sendNew) never collides with a client-chosen request id
+// space it doesn't control.
+type jsonrpcResponseShape struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Result json.RawMessage `json:"result"`
+	Error  json.RawMessage `json:"error"`
+}
+
+// isJSONRPCResponse reports whether raw is shaped like a JSON-RPC
+// response (as opposed to a request or notification), and if so, the
+// integer id it carries.
+func isJSONRPCResponse(raw []byte) (id int64, ok bool) {
+	var env jsonrpcResponseShape
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return 0, false
+	}
+	if env.Method != "" || len(env.ID) == 0 || (len(env.Result) == 0 && len(env.Error) == 0) {
+		return 0, false
+	}
+	if err := json.Unmarshal(env.ID, &id); err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// tryHandlePingResponse reports whether raw was a reply to one of this
+// session's server-initiated pings (in which case the caller should
+// swallow the line instead of passing it to s.HandleMessage, since
+// HandleMessage has nothing to do with a bare response object). It also
+// swallows any other response-shaped line for the same reason, even one
+// that doesn't match an outstanding ping — a client never has a reason to
+// send the server a JSON-RPC response outside of answering something the
+// server itself sent.
+func tryHandlePingResponse(sessionID string, raw []byte) bool {
+	id, ok := isJSONRPCResponse(raw)
+	if !ok {
+		return false
+	}
+	if stats := getPingStats(sessionID); stats != nil {
+		stats.resolve(id)
+	}
+	return true
+}
+
+// runServerPing sends sessionID's connection a ping every serverPingInterval
+// via write, until stop closes. It calls evict and returns once
+// serverPingMaxMissed consecutive pings go unanswered; write's own error
+// return also ends the loop, since a dead connection can't be pinged.
+func runServerPing(sessionID string, write func([]byte) error, stop <-chan struct{}, evict func(reason string)) {
+	if serverPingInterval <= 0 {
+		return
+	}
+
+	stats := startPingStats(sessionID)
+	defer stopPingStats(sessionID)
+
+	ticker := time.NewTicker(serverPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if stats.hasOutstanding() {
+				missed := stats.recordMissed()
+				if serverPingMaxMissed > 0 && missed >= serverPingMaxMissed {
+					evict(fmt.Sprintf("missed %d consecutive ping(s)", missed))
+					return
+				}
+			}
+			if err := write(stats.sendNew()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// registerConnectionStatsTool wires up connection_stats, reporting the
+// calling session's server-initiated ping latency. sessionOrStdioID, not
+// sessionIDFromContext, since runServerPing only ever runs for stdio/ws
+// sessions and counter.go's "stdio" collapse is exactly the id
+// startPingStats/stopPingStats use for the stdio connection.
+func registerConnectionStatsTool(s *server.MCPServer) {
+	tool := mcp.NewTool("connection_stats",
+		mcp.WithDescription("Report this connection's server-initiated ping latency (requires --ping-interval); all zero if no ping has been sent yet"),
+	)
+	addToolWithMiddleware(s, tool, handleConnectionStatsTool, recoverMiddleware)
+}
+
+func handleConnectionStatsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	stats := getPingStats(sessionOrStdioID(ctx))
+	var snapshot pingStatsSnapshot
+	if stats != nil {
+		snapshot = stats.snapshot()
+	}
+
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	return mcp.NewToolResultText(string(b)), nil
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func withTestServerPing(t *testing.T, interval time.Duration, maxMissed int) {
+	t.Helper()
+	origInterval := serverPingInterval
+	origMaxMissed := serverPingMaxMissed
+	serverPingInterval = interval
+	serverPingMaxMissed = maxMissed
+	t.Cleanup(func() {
+		serverPingInterval = origInterval
+		serverPingMaxMissed = origMaxMissed
+	})
+}
+
+func TestIsJSONRPCResponse(t *testing.T) {
+	cases := []struct {
+		raw    string
+		wantOK bool
+		wantID int64
+	}{
+		{`{"jsonrpc":"2.0","id":5,"result":{}}`, true, 5},
+		{`{"jsonrpc":"2.0","id":5,"error":{"code":-1,"message":"x"}}`, true, 5},
+		{`{"jsonrpc":"2.0","id":5,"method":"ping"}`, false, 0},
+		{`{"jsonrpc":"2.0","method":"notifications/initialized"}`, false, 0},
+		{`{"jsonrpc":"2.0","id":5}`, false, 0},
+		{`not json`, false, 0},
+	}
+	for _, c := range cases {
+		id, ok := isJSONRPCResponse([]byte(c.raw))
+		if ok != c.wantOK {
+			t.Errorf("isJSONRPCResponse(%q) ok = %v, want %v", c.raw, ok, c.wantOK)
+			continue
+		}
+		if ok && id != c.wantID {
+			t.Errorf("isJSONRPCResponse(%q) id = %d, want %d", c.raw, id, c.wantID)
+		}
+	}
+}
+
+func TestRunServerPing_RecordsLatencyForAnsweredPing(t *testing.T) {
+	withTestServerPing(t, 10*time.Millisecond, 0)
+
+	sent := make(chan []byte, 10)
+	write := func(b []byte) error {
+		sent <- b
+		return nil
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go runServerPing("sess-ping-answered", write, stop, func(reason string) {
+		t.Errorf("unexpected eviction: %s", reason)
+	})
+
+	var id int64
+	select {
+	case b := <-sent:
+		var req map[string]any
+		if err := json.Unmarshal(b, &req); err != nil {
+			t.Fatalf("unmarshaling sent ping: %v", err)
+		}
+		if req["method"] != "ping" {
+			t.Fatalf("method = %v, want ping", req["method"])
+		}
+		id = int64(req["id"].(float64))
+	case <-time.After(time.Second):
+		t.Fatal("no ping sent within 1s")
+	}
+
+	reply, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": id, "result": map[string]any{}})
+	if !tryHandlePingResponse("sess-ping-answered", reply) {
+		t.Fatal("expected the reply to be recognized as a ping response")
+	}
+
+	stats := getPingStats("sess-ping-answered")
+	if stats == nil {
+		t.Fatal("expected ping stats to exist while the goroutine is still running")
+	}
+	snap := stats.snapshot()
+	if snap.PingCount != 1 {
+		t.Errorf("PingCount = %d, want 1", snap.PingCount)
+	}
+	if snap.ConsecutiveMissed != 0 {
+		t.Errorf("ConsecutiveMissed = %d, want 0 after a timely reply", snap.ConsecutiveMissed)
+	}
+}
+
+func TestRunServerPing_EvictsAfterConsecutiveMissedPings(t *testing.T) {
+	withTestServerPing(t, 5*time.Millisecond, 2)
+
+	write := func(b []byte) error { return nil } // never answered
+	stop := make(chan struct{})
+	defer close(stop)
+
+	evicted := make(chan string, 1)
+	go runServerPing("sess-ping-missed", write, stop, func(reason string) {
+		evicted <- reason
+	})
+
+	select {
+	case reason := <-evicted:
+		if reason == "" {
+			t.Error("expected a non-empty eviction reason")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected eviction within 1s after 2 consecutive missed pings")
+	}
+}
+
+func TestServerPingInterval_DefaultsToZero(t *testing.T) {
+	if serverPingInterval != 0 {
+		t.Errorf("serverPingInterval = %v, want 0 (main's -ping-interval default mirrors this)", serverPingInterval)
+	}
+}
+
+func TestHandleConnectionStatsTool_NoPingsYetReturnsZeroes(t *testing.T) {
+	res, err := handleConnectionStatsTool(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text, ok := res.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("Content[0] = %T, want mcp.TextContent", res.Content[0])
+	}
+	var snap pingStatsSnapshot
+	if err := json.Unmarshal([]byte(text.Text), &snap); err != nil {
+		t.Fatalf("unmarshaling connection_stats output: %v", err)
+	}
+	if snap.PingCount != 0 || snap.LastLatencyMS != 0 || snap.AverageLatencyMS != 0 || snap.ConsecutiveMissed != 0 {
+		t.Errorf("snapshot = %+v, want all zero with no ping sent yet", snap)
+	}
+}
+
+// TestServeWebSocketConn_ScriptedClientAnswersSomePingsAndIsEvictedForIgnoringOthers
+// exercises the real websocket dispatch path end to end: a scripted client
+// answers the connection's first server ping with a real JSON-RPC result
+// (so connection_stats would report a recorded latency) and then goes
+// silent, exercising the --ping-max-missed eviction path.
+func TestServeWebSocketConn_ScriptedClientAnswersSomePingsAndIsEvictedForIgnoringOthers(t *testing.T) {
+	withTestServerPing(t, 20*time.Millisecond, 2)
+
+	srv := httptest.NewServer(newWebSocketHandler(context.Background(), newTestServer()))
+	defer srv.Close()
+
+	conn := dialTestWebSocket(t, srv)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	answered := false
+	for i := 0; i < 10; i++ {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if answered {
+				return // evicted after the planned silence, as expected
+			}
+			t.Fatalf("connection closed before a single ping was answered: %v", err)
+		}
+
+		var msg map[string]any
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("unmarshaling server message: %v", err)
+		}
+		if msg["method"] != "ping" {
+			continue
+		}
+
+		if !answered {
+			reply, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": msg["id"], "result": map[string]any{}})
+			if err := conn.WriteMessage(websocket.TextMessage, reply); err != nil {
+				t.Fatalf("answering first ping: %v", err)
+			}
+			answered = true
+			continue
+		}
+		// Every later ping is ignored on purpose, to exercise eviction.
+	}
+
+	t.Fatal("expected the connection to be closed after repeated ignored pings")
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// reportProgress emits a notifications/progress message on whatever
+// transport ctx's session is attached to. It is a no-op when token is
+// nil, so handlers can call it unconditionally — see
+// progressTokenFromRequest, which reports false when the client didn't
+// send a progressToken in the request's _meta.
+func reportProgress(ctx context.Context, token mcp.ProgressToken, progress, total float64) {
+	if token == nil {
+		return
+	}
+
+	srv := server.ServerFromContext(ctx)
+	if srv == nil {
+		return
+	}
+
+	srv.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+		"progressToken": token,
+		"progress":      progress,
+		"total":         total,
+	})
+}
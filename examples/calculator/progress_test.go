@@ -0,0 +1,12 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestReportProgress_NoOpWithoutToken(t *testing.T) {
+	// Nil token: reportProgress must not panic or attempt to reach a
+	// transport.
+	reportProgress(context.Background(), nil, 1, 10)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerPrompts wires up the calculator's prompts. code_review is a
+// generic example used to exercise prompts/list and prompts/get before
+// explain_calculation (added alongside the fuller prompts capability) ties
+// a prompt to the calculator's own domain.
+func registerPrompts(s *server.MCPServer) {
+	s.AddPrompt(
+		mcp.NewPrompt("code_review",
+			mcp.WithPromptDescription("Ask the model to review a snippet of code"),
+			mcp.WithArgument("language", mcp.ArgumentDescription("Language the snippet is written in"), mcp.RequiredArgument()),
+			mcp.WithArgument("snippet", mcp.ArgumentDescription("The code to review"), mcp.RequiredArgument()),
+		),
+		recoverPromptHandler("code_review", newTracingPromptHandler("code_review", handleCodeReviewPrompt)),
+	)
+
+	s.AddPrompt(
+		mcp.NewPrompt("explain_calculation",
+			mcp.WithPromptDescription("Ask the model to explain an arithmetic computation step by step"),
+			mcp.WithArgument("operation", mcp.ArgumentDescription("add, subtract, multiply, divide, power, modulo, or sqrt"), mcp.RequiredArgument()),
+			mcp.WithArgument("x", mcp.ArgumentDescription("First number"), mcp.RequiredArgument()),
+			mcp.WithArgument("y", mcp.ArgumentDescription("Second number"), mcp.RequiredArgument()),
+		),
+		recoverPromptHandler("explain_calculation", handleExplainCalculationPrompt),
+	)
+}
+
+func handleCodeReviewPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	language, ok := request.Params.Arguments["language"]
+	if !ok || language == "" {
+		return nil, fmt.Errorf("missing required argument: language")
+	}
+
+	snippet, ok := request.Params.Arguments["snippet"]
+	if !ok || snippet == "" {
+		return nil, fmt.Errorf("missing required argument: snippet")
+	}
+
+	return &mcp.GetPromptResult{
+		Description: "Code review request",
+		Messages: []mcp.PromptMessage{
+			{
+				Role: mcp.RoleUser,
+				Content: mcp.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf("Please review the following %s code:\n\n%s", language, snippet),
+				},
+			},
+		},
+	}, nil
+}
+
+// promptFloatArg parses a required prompt string argument as a float64,
+// since prompts/get arguments always arrive as strings regardless of the
+// underlying value's logical type.
+func promptFloatArg(args map[string]string, name string) (float64, error) {
+	raw, ok := args[name]
+	if !ok || raw == "" {
+		return 0, fmt.Errorf("missing required argument: %s", name)
+	}
+
+	n, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("argument %s: %q is not a number", name, raw)
+	}
+	return n, nil
+}
+
+func handleExplainCalculationPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	op, ok := request.Params.Arguments["operation"]
+	if !ok || op == "" {
+		return nil, fmt.Errorf("missing required argument: operation")
+	}
+
+	x, err := promptFloatArg(request.Params.Arguments, "x")
+	if err != nil {
+		return nil, err
+	}
+	y, err := promptFloatArg(request.Params.Arguments, "y")
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := calculate(op, x, y)
+	if err != nil {
+		return nil, fmt.Errorf("invalid params: %w", err)
+	}
+
+	return &mcp.GetPromptResult{
+		Description: "Explain an arithmetic computation",
+		Messages: []mcp.PromptMessage{
+			{
+				Role: mcp.RoleUser,
+				Content: mcp.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf("Explain, step by step, how to %s %v and %v.", canonicalOperation(op), x, y),
+				},
+			},
+			{
+				Role: mcp.RoleAssistant,
+				Content: mcp.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf("%v", result),
+				},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleCodeReviewPrompt_Success(t *testing.T) {
+	req := mcp.GetPromptRequest{}
+	req.Params.Arguments = map[string]string{
+		"language": "go",
+		"snippet":  "func main() {}",
+	}
+
+	res, err := handleCodeReviewPrompt(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Messages) != 1 {
+		t.Fatalf("expected exactly one message, got %d", len(res.Messages))
+	}
+}
+
+func TestHandleCodeReviewPrompt_MissingArgument(t *testing.T) {
+	req := mcp.GetPromptRequest{}
+	req.Params.Arguments = map[string]string{
+		"language": "go",
+	}
+
+	if _, err := handleCodeReviewPrompt(context.Background(), req); err == nil {
+		t.Fatal("expected an error for the missing snippet argument")
+	}
+}
+
+func TestHandleExplainCalculationPrompt_Success(t *testing.T) {
+	req := mcp.GetPromptRequest{}
+	req.Params.Arguments = map[string]string{
+		"operation": "add",
+		"x":         "2",
+		"y":         "3",
+	}
+
+	res, err := handleExplainCalculationPrompt(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Messages) != 2 {
+		t.Fatalf("expected a user and an assistant message, got %d", len(res.Messages))
+	}
+
+	answer := res.Messages[1].Content.(mcp.TextContent).Text
+	if answer != "5" {
+		t.Errorf("assistant answer = %q, want %q", answer, "5")
+	}
+}
+
+func TestHandleExplainCalculationPrompt_MissingArgument(t *testing.T) {
+	req := mcp.GetPromptRequest{}
+	req.Params.Arguments = map[string]string{
+		"operation": "add",
+		"x":         "2",
+	}
+
+	if _, err := handleExplainCalculationPrompt(context.Background(), req); err == nil {
+		t.Fatal("expected an error for the missing y argument")
+	}
+}
+
+func TestHandleExplainCalculationPrompt_InvalidNumber(t *testing.T) {
+	req := mcp.GetPromptRequest{}
+	req.Params.Arguments = map[string]string{
+		"operation": "add",
+		"x":         "not-a-number",
+		"y":         "3",
+	}
+
+	if _, err := handleExplainCalculationPrompt(context.Background(), req); err == nil {
+		t.Fatal("expected an error for a non-numeric x")
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+)
+
+// pinnedProtocolVersion, when set, forces the initialize response to
+// advertise exactly this version (instead of whatever mcp-go negotiates by
+// default) and disables any version-gated capability (one
+// versionCapabilities lists for some version) that it doesn't list for it. main wires this to --protocol-version and validates it against
+// knownProtocolVersions at startup, failing fast on an unrecognized value
+// the same way tlsConfig's cert/key mismatch does.
+var pinnedProtocolVersion string
+
+// versionCapabilities lists which of this fixture's capability names each
+// known protocol version supports. A version not listed here supports
+// none of them, which is only reachable if knownProtocolVersions and this
+// map are edited out of sync with each other.
+var versionCapabilities = map[string][]string{
+	"2024-11-05": {},
+	"2025-03-26": {"completions"},
+	"2025-06-18": {"completions", "structuredOutput"},
+}
+
+// versionGatedMethods maps a capability name to the methods that only
+// make sense when it's present. pinnedProtocolVersion's method-not-found
+// behavior for newer-only methods comes entirely from this table.
+var versionGatedMethods = map[string]string{
+	"completion/complete": "completions",
+}
+
+// methodAvailableAtPinnedVersion reports whether method should be
+// dispatched given pinnedProtocolVersion. An empty pin (the default)
+// always allows everything, since then mcp-go's own default version
+// applies unmodified.
+func methodAvailableAtPinnedVersion(method string) bool {
+	if pinnedProtocolVersion == "" {
+		return true
+	}
+	capability, gated := versionGatedMethods[method]
+	if !gated {
+		return true
+	}
+	for _, c := range versionCapabilities[pinnedProtocolVersion] {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteInitializeResponse overwrites an initialize result's
+// protocolVersion to pinnedProtocolVersion and strips any version-gated
+// capability key versionCapabilities doesn't list for it (capabilities no
+// version lists, like tools, are left alone), so a client sees exactly the
+// surface that version actually had. raw is the unmodified response
+// s.HandleMessage produced; returns raw unchanged if there's no pin, the
+// response isn't a successful initialize result, or it doesn't parse as
+// JSON object shaped the way one is.
+func rewriteInitializeResponse(raw []byte) []byte {
+	if pinnedProtocolVersion == "" {
+		return raw
+	}
+
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return raw
+	}
+	resultRaw, ok := envelope["result"]
+	if !ok {
+		return raw
+	}
+
+	var result map[string]json.RawMessage
+	if err := json.Unmarshal(resultRaw, &result); err != nil {
+		return raw
+	}
+
+	versionJSON, err := json.Marshal(pinnedProtocolVersion)
+	if err != nil {
+		return raw
+	}
+	result["protocolVersion"] = versionJSON
+
+	if capsRaw, ok := result["capabilities"]; ok {
+		var caps map[string]json.RawMessage
+		if err := json.Unmarshal(capsRaw, &caps); err == nil {
+			allowed := versionCapabilities[pinnedProtocolVersion]
+			gated := map[string]bool{}
+			for _, names := range versionCapabilities {
+				for _, n := range names {
+					gated[n] = true
+				}
+			}
+			for name := range caps {
+				if gated[name] && !containsString(allowed, name) {
+					delete(caps, name)
+				}
+			}
+			if b, err := json.Marshal(caps); err == nil {
+				result["capabilities"] = b
+			}
+		}
+	}
+
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		return raw
+	}
+	envelope["result"] = resultBytes
+
+	out, err := json.Marshal(envelope)
+	if err != nil {
+		return raw
+	}
+	return out
+}
+
+func containsString(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
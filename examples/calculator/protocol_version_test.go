@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRewriteInitializeResponse_OverridesVersionAndStripsNewerCapabilities(t *testing.T) {
+	pinnedProtocolVersion = "2024-11-05"
+	defer func() { pinnedProtocolVersion = "" }()
+
+	raw := []byte(`{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2025-06-18","capabilities":{"tools":{},"completions":{},"structuredOutput":{}},"serverInfo":{"name":"Calculator"}}}`)
+
+	out := rewriteInitializeResponse(raw)
+
+	var parsed struct {
+		Result struct {
+			ProtocolVersion string                     `json:"protocolVersion"`
+			Capabilities    map[string]json.RawMessage `json:"capabilities"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(out, &parsed); err != nil {
+		t.Fatalf("unmarshaling rewritten response: %v", err)
+	}
+
+	if parsed.Result.ProtocolVersion != "2024-11-05" {
+		t.Errorf("protocolVersion = %q, want 2024-11-05", parsed.Result.ProtocolVersion)
+	}
+	if _, ok := parsed.Result.Capabilities["completions"]; ok {
+		t.Error("expected completions to be stripped at 2024-11-05")
+	}
+	if _, ok := parsed.Result.Capabilities["tools"]; !ok {
+		t.Error("expected tools capability to survive (not version-gated)")
+	}
+}
+
+func TestRewriteInitializeResponse_NoPinLeavesResponseUnchanged(t *testing.T) {
+	pinnedProtocolVersion = ""
+	raw := []byte(`{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2025-06-18"}}`)
+	out := rewriteInitializeResponse(raw)
+	if string(out) != string(raw) {
+		t.Errorf("got %s, want unchanged %s", out, raw)
+	}
+}
+
+func TestMethodAvailableAtPinnedVersion_GatesNewerOnlyMethods(t *testing.T) {
+	pinnedProtocolVersion = "2024-11-05"
+	defer func() { pinnedProtocolVersion = "" }()
+
+	if methodAvailableAtPinnedVersion("completion/complete") {
+		t.Error("expected completion/complete to be unavailable at 2024-11-05")
+	}
+	if !methodAvailableAtPinnedVersion("tools/call") {
+		t.Error("expected tools/call to remain available at 2024-11-05")
+	}
+}
+
+func TestMethodAvailableAtPinnedVersion_NoPinAllowsEverything(t *testing.T) {
+	pinnedProtocolVersion = ""
+	if !methodAvailableAtPinnedVersion("completion/complete") {
+		t.Error("expected no pin to allow every method")
+	}
+}
+
+func TestCheckProtocolPreconditions_MethodNotFoundAtPinnedVersion(t *testing.T) {
+	pinnedProtocolVersion = "2024-11-05"
+	defer func() { pinnedProtocolVersion = "" }()
+
+	violation := checkProtocolPreconditions("sess-pinned", jsonrpcEnvelope{ID: float64(3), Method: "completion/complete"})
+	if violation == nil {
+		t.Fatal("expected a method-not-found violation")
+	}
+	if violation.Error.Code != -32601 {
+		t.Errorf("code = %d, want -32601", violation.Error.Code)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerRaiseErrorTool wires up raise_error, used to deterministically
+// exercise every error shape a client's error mapping needs to handle.
+func registerRaiseErrorTool(s *server.MCPServer) {
+	tool := mcp.NewTool("raise_error",
+		mcp.WithDescription("Fail in a configurable way, to test error handling"),
+		mcp.WithString("kind",
+			mcp.Required(),
+			mcp.Description("tool_error, protocol_error, invalid_params, or panic"),
+			mcp.Enum("tool_error", "protocol_error", "invalid_params", "panic"),
+		),
+		mcp.WithString("message", mcp.Description("Message to include in the error"), mcp.DefaultString("raise_error triggered")),
+	)
+	// recoverMiddleware is what turns the "panic" kind into an internal
+	// error instead of taking the process down; it must wrap this handler
+	// like every other tool's.
+	addToolWithMiddleware(s, tool, handleRaiseErrorTool, newDefaultsMiddleware(tool), recoverMiddleware)
+}
+
+func handleRaiseErrorTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	kind, _ := request.Params.Arguments["kind"].(string)
+	message, _ := request.Params.Arguments["message"].(string)
+
+	switch kind {
+	case "tool_error":
+		return toolResultErrorf("%s", message), nil
+	case "protocol_error":
+		return nil, fmt.Errorf("%s", message)
+	case "invalid_params":
+		return nil, invalidParamsf("%s", message)
+	case "panic":
+		panic(message)
+	default:
+		return nil, invalidParamsf("unknown kind %q", kind)
+	}
+}
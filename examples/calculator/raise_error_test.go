@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func raiseError(t *testing.T, kind string) (*mcp.CallToolResult, error) {
+	t.Helper()
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "raise_error"
+	req.Params.Arguments = map[string]any{"kind": kind, "message": "boom"}
+	return recoverMiddleware(handleRaiseErrorTool)(context.Background(), req)
+}
+
+func TestHandleRaiseErrorTool_ToolError(t *testing.T) {
+	res, err := raiseError(t, "tool_error")
+	if err != nil {
+		t.Fatalf("unexpected transport-level error: %v", err)
+	}
+	if !res.IsError {
+		t.Error("expected an isError result for kind=tool_error")
+	}
+}
+
+func TestHandleRaiseErrorTool_ProtocolError(t *testing.T) {
+	res, err := raiseError(t, "protocol_error")
+	if res != nil {
+		t.Errorf("result = %v, want nil", res)
+	}
+	if err == nil {
+		t.Fatal("expected a Go error for kind=protocol_error")
+	}
+	if _, ok := err.(*protocolError); ok {
+		t.Error("protocol_error should be a plain error, not a *protocolError with a specific code")
+	}
+}
+
+func TestHandleRaiseErrorTool_InvalidParams(t *testing.T) {
+	res, err := raiseError(t, "invalid_params")
+	if res != nil {
+		t.Errorf("result = %v, want nil", res)
+	}
+	pe, ok := err.(*protocolError)
+	if !ok {
+		t.Fatalf("err = %T, want *protocolError", err)
+	}
+	if pe.Code() != mcp.INVALID_PARAMS {
+		t.Errorf("code = %d, want %d", pe.Code(), mcp.INVALID_PARAMS)
+	}
+}
+
+func TestHandleRaiseErrorTool_Panic(t *testing.T) {
+	res, err := raiseError(t, "panic")
+	if res != nil {
+		t.Errorf("result = %v, want nil", res)
+	}
+	pe, ok := err.(*protocolError)
+	if !ok {
+		t.Fatalf("err = %T, want *protocolError", err)
+	}
+	if pe.Code() != mcp.INTERNAL_ERROR {
+		t.Errorf("code = %d, want %d", pe.Code(), mcp.INTERNAL_ERROR)
+	}
+}
+
+func TestHandleRaiseErrorTool_PanicDoesNotStopSubsequentCalls(t *testing.T) {
+	if _, err := raiseError(t, "panic"); err == nil {
+		t.Fatal("expected the panic path to return an error")
+	}
+
+	// The server (recoverMiddleware, standing in for it here) must keep
+	// serving requests after a panic; a successful call right after proves
+	// the process is still alive and the handler chain still works.
+	res, err := raiseError(t, "tool_error")
+	if err != nil {
+		t.Fatalf("unexpected error after a prior panic was recovered: %v", err)
+	}
+	if !res.IsError {
+		t.Error("expected an isError result for kind=tool_error")
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// rateLimitConfig is a token bucket's capacity and refill window: up to
+// Limit calls are allowed per Window, refilling continuously rather than
+// all at once at a window boundary. A zero Limit disables the bucket.
+type rateLimitConfig struct {
+	Limit  int
+	Window time.Duration
+}
+
+func (c rateLimitConfig) enabled() bool { return c.Limit > 0 && c.Window > 0 }
+
+// sessionRateLimit is the default bucket newRateLimitMiddleware checks for
+// every call, keyed on session id alone. main wires it to --rate-limit-
+// per-session and --rate-limit-window. Disabled (zero value) by default,
+// matching this fixture's other opt-in toggles.
+var sessionRateLimit rateLimitConfig
+
+// toolRateLimits overrides sessionRateLimit for specific tool names,
+// keyed on session id *and* tool name so one tool's limit never starves
+// another's budget for the same session. main wires calculate's override
+// to --rate-limit-tool-calculate-per-session, sharing --rate-limit-window;
+// empty by default.
+var toolRateLimits = map[string]rateLimitConfig{}
+
+// rateLimitExceededCode is the JSON-RPC error code a throttled call gets
+// back, distinct from every other code this fixture reports so a client
+// can tell "retry me later" apart from any other failure.
+const rateLimitExceededCode = -32005
+
+// tokenBucket is a concurrency-safe, continuously-refilling token bucket.
+// Buckets are created lazily per key and cleaned up when their owning
+// session disconnects (see registerRateLimitHooks), so memory doesn't
+// grow with churn of short-lived sessions.
+type tokenBucket struct {
+	mu              sync.Mutex
+	tokens          float64
+	capacity        float64
+	refillPerSecond float64
+	last            time.Time
+}
+
+func newTokenBucket(cfg rateLimitConfig) *tokenBucket {
+	return &tokenBucket{
+		tokens:          float64(cfg.Limit),
+		capacity:        float64(cfg.Limit),
+		refillPerSecond: float64(cfg.Limit) / cfg.Window.Seconds(),
+		last:            time.Now(),
+	}
+}
+
+// take reports whether a call may proceed right now. When it can't, it
+// also returns how long the caller should wait before the next token is
+// available.
+func (b *tokenBucket) take() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.refillPerSecond
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	deficit := 1 - b.tokens
+	return false, time.Duration(deficit / b.refillPerSecond * float64(time.Second))
+}
+
+// rateLimitBuckets holds every live tokenBucket, keyed by the string
+// rateLimitKey builds, following the same mutex-guarded map pattern as
+// subscriptions.go and counter.go.
+var rateLimitBuckets = struct {
+	mu   sync.Mutex
+	byID map[string]*tokenBucket
+}{byID: map[string]*tokenBucket{}}
+
+func rateLimitKey(sessionID, bucketName string) string {
+	return sessionID + "\x00" + bucketName
+}
+
+func takeRateLimitToken(sessionID, bucketName string, cfg rateLimitConfig) (bool, time.Duration) {
+	key := rateLimitKey(sessionID, bucketName)
+
+	rateLimitBuckets.mu.Lock()
+	bucket, ok := rateLimitBuckets.byID[key]
+	if !ok {
+		bucket = newTokenBucket(cfg)
+		rateLimitBuckets.byID[key] = bucket
+	}
+	rateLimitBuckets.mu.Unlock()
+
+	return bucket.take()
+}
+
+func deleteSessionRateLimitBuckets(sessionID string) {
+	rateLimitBuckets.mu.Lock()
+	defer rateLimitBuckets.mu.Unlock()
+
+	prefix := sessionID + "\x00"
+	for key := range rateLimitBuckets.byID {
+		if len(key) >= len(prefix) && key[:len(prefix)] == prefix {
+			delete(rateLimitBuckets.byID, key)
+		}
+	}
+}
+
+// registerRateLimitHooks drops every bucket belonging to a session once
+// it disconnects, the same cleanup registerCounterHooks does for
+// counters.
+func registerRateLimitHooks(hooks *server.Hooks) {
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		deleteSessionRateLimitBuckets(session.SessionID())
+	})
+}
+
+// newRateLimitMiddleware checks sessionRateLimit (keyed on session id
+// alone, bucket name "*") and, if toolRateLimits has an override for
+// this tool, that bucket too (keyed on session id and tool name). Either
+// bucket running dry throttles the call; both must allow it through.
+func newRateLimitMiddleware(toolName string) toolMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			sessionID := sessionOrStdioID(ctx)
+
+			if sessionRateLimit.enabled() {
+				if ok, retryAfter := takeRateLimitToken(sessionID, "*", sessionRateLimit); !ok {
+					return nil, rateLimitExceededError(retryAfter)
+				}
+			}
+
+			if cfg, ok := toolRateLimits[toolName]; ok && cfg.enabled() {
+				if ok, retryAfter := takeRateLimitToken(sessionID, toolName, cfg); !ok {
+					return nil, rateLimitExceededError(retryAfter)
+				}
+			}
+
+			return next(ctx, request)
+		}
+	}
+}
+
+func rateLimitExceededError(retryAfter time.Duration) *protocolError {
+	return &protocolError{
+		code:    rateLimitExceededCode,
+		message: fmt.Sprintf("rate limit exceeded, retry after %s", retryAfter),
+		data:    map[string]any{"retry_after_ms": retryAfter.Milliseconds()},
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func resetRateLimitState() {
+	sessionRateLimit = rateLimitConfig{}
+	toolRateLimits = map[string]rateLimitConfig{}
+	rateLimitBuckets.mu.Lock()
+	rateLimitBuckets.byID = map[string]*tokenBucket{}
+	rateLimitBuckets.mu.Unlock()
+}
+
+func TestTokenBucket_AllowsUpToLimitThenThrottles(t *testing.T) {
+	b := newTokenBucket(rateLimitConfig{Limit: 2, Window: time.Minute})
+
+	if ok, _ := b.take(); !ok {
+		t.Fatal("expected the first call to be allowed")
+	}
+	if ok, _ := b.take(); !ok {
+		t.Fatal("expected the second call to be allowed")
+	}
+	ok, retryAfter := b.take()
+	if ok {
+		t.Fatal("expected the third call to be throttled")
+	}
+	if retryAfter <= 0 {
+		t.Error("expected a positive retry-after for a throttled call")
+	}
+}
+
+func TestNewRateLimitMiddleware_EnforcesPerSessionLimit(t *testing.T) {
+	resetRateLimitState()
+	sessionRateLimit = rateLimitConfig{Limit: 1, Window: time.Minute}
+	defer resetRateLimitState()
+
+	handler := newRateLimitMiddleware("calculate")(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	ctx := context.Background()
+
+	if _, err := handler(ctx, mcp.CallToolRequest{}); err != nil {
+		t.Fatalf("first call: unexpected error: %v", err)
+	}
+	_, err := handler(ctx, mcp.CallToolRequest{})
+	if err == nil {
+		t.Fatal("expected the second call within the window to be throttled")
+	}
+	pe, ok := err.(*protocolError)
+	if !ok {
+		t.Fatalf("expected a *protocolError, got %T", err)
+	}
+	if pe.Code() != rateLimitExceededCode {
+		t.Errorf("code = %d, want %d", pe.Code(), rateLimitExceededCode)
+	}
+}
+
+func TestNewRateLimitMiddleware_PerToolOverrideIsIndependentOfSessionBucket(t *testing.T) {
+	resetRateLimitState()
+	sessionRateLimit = rateLimitConfig{Limit: 100, Window: time.Minute}
+	toolRateLimits["calculate"] = rateLimitConfig{Limit: 1, Window: time.Minute}
+	defer resetRateLimitState()
+
+	handler := newRateLimitMiddleware("calculate")(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	ctx := context.Background()
+
+	if _, err := handler(ctx, mcp.CallToolRequest{}); err != nil {
+		t.Fatalf("first call: unexpected error: %v", err)
+	}
+	if _, err := handler(ctx, mcp.CallToolRequest{}); err == nil {
+		t.Fatal("expected the tool-specific limit to throttle the second call despite headroom in the session bucket")
+	}
+}
+
+func TestNewRateLimitMiddleware_DisabledByDefault(t *testing.T) {
+	resetRateLimitState()
+	defer resetRateLimitState()
+
+	handler := newRateLimitMiddleware("calculate")(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		if _, err := handler(ctx, mcp.CallToolRequest{}); err != nil {
+			t.Fatalf("call %d: unexpected error with rate limiting disabled: %v", i, err)
+		}
+	}
+}
+
+func TestDeleteSessionRateLimitBuckets_RemovesOnlyThatSessionsBuckets(t *testing.T) {
+	resetRateLimitState()
+	defer resetRateLimitState()
+
+	takeRateLimitToken("sess-a", "*", rateLimitConfig{Limit: 1, Window: time.Minute})
+	takeRateLimitToken("sess-a", "calculate", rateLimitConfig{Limit: 1, Window: time.Minute})
+	takeRateLimitToken("sess-b", "*", rateLimitConfig{Limit: 1, Window: time.Minute})
+
+	deleteSessionRateLimitBuckets("sess-a")
+
+	rateLimitBuckets.mu.Lock()
+	defer rateLimitBuckets.mu.Unlock()
+	if _, ok := rateLimitBuckets.byID[rateLimitKey("sess-a", "*")]; ok {
+		t.Error("expected sess-a's session bucket to be removed")
+	}
+	if _, ok := rateLimitBuckets.byID[rateLimitKey("sess-a", "calculate")]; ok {
+		t.Error("expected sess-a's tool bucket to be removed")
+	}
+	if _, ok := rateLimitBuckets.byID[rateLimitKey("sess-b", "*")]; !ok {
+		t.Error("expected sess-b's bucket to survive")
+	}
+}
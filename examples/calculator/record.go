@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// recordPath is the destination --record writes a JSON Lines transcript
+// to; empty (the default) disables recording entirely. main wires this to
+// --record and opens recordFile for it before serve starts.
+var recordPath = ""
+
+// recordFile and recordMu guard the single transcript file every
+// transport writes to: recordMessage is called concurrently from
+// serveStdioTolerant's dispatch goroutines, serveWebSocketConn's, and
+// every sse/http/both request goroutine net/http spins up, so each append
+// is serialized under recordMu rather than relying on os.File's own
+// (platform-dependent) append-mode atomicity for anything past a single
+// Write call.
+var (
+	recordMu   sync.Mutex
+	recordFile *os.File
+)
+
+// recordedMessage is one JSON Lines entry in the transcript: Payload is
+// kept as json.RawMessage rather than re-marshaled from a parsed value,
+// so the recorded bytes are exactly what crossed the wire, not this
+// fixture's own idea of their canonical encoding.
+type recordedMessage struct {
+	Timestamp time.Time       `json:"timestamp"`
+	SessionID string          `json:"session_id"`
+	Direction string          `json:"direction"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// openRecordFile opens path for appending and points recordFile at it.
+// Append mode (rather than truncate) matches configureDebugLogging's own
+// choice for --log-file, for the same reason: a second run against the
+// same --record path shouldn't destroy the first run's transcript.
+func openRecordFile(path string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening --record file: %w", err)
+	}
+	recordFile = file
+	return nil
+}
+
+// closeRecordFile closes recordFile if --record was set; main defers this
+// (best-effort, like configureDebugLogging's returned io.Closer) so a
+// normal exit closes the descriptor; a killed process never runs it, but
+// every write already reached disk via Sync by then.
+func closeRecordFile() error {
+	if recordFile == nil {
+		return nil
+	}
+	return recordFile.Close()
+}
+
+// recordMessage appends one entry to the transcript, a no-op when
+// --record wasn't set. Each write is followed by Sync so a crashed run
+// still yields a file usable up to its last completed message, per the
+// request's own "flushed on each write" requirement — os.File.Write
+// already reaches the OS, but Sync is what forces it to stable storage.
+func recordMessage(sessionID, direction string, raw []byte) {
+	if recordFile == nil {
+		return
+	}
+
+	entry := recordedMessage{
+		Timestamp: time.Now(),
+		SessionID: sessionID,
+		Direction: direction,
+		Payload:   json.RawMessage(raw),
+	}
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	b = append(b, '\n')
+
+	recordMu.Lock()
+	defer recordMu.Unlock()
+	if _, err := recordFile.Write(b); err != nil {
+		return
+	}
+	_ = recordFile.Sync()
+}
+
+// recordingResponseWriter keeps a copy of everything written through it
+// while passing each write straight on to the real ResponseWriter (unlike
+// compressingResponseWriter and initializeResponseRewriter, which hold
+// the body back), so withRecording can hand the complete
+// response body (and, if the server just minted one, the session id from
+// its own response header) to recordMessage after the handler returns.
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	buf bytes.Buffer
+}
+
+func (w *recordingResponseWriter) Write(b []byte) (int, error) {
+	w.buf.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// withRecording wraps the sse/http/both transports' message-handling
+// endpoint, recording each POST's request body as one "in" entry and its
+// response body as one "out" entry. It sits inside withCompression (see
+// transport.go) so it always sees the plain JSON body, never gzip-encoded
+// bytes. GET (the open SSE event stream) passes through unrecorded, for
+// the same reason withCompression never buffers it: there's no "done" to
+// record until the connection closes, by which point recording it as a
+// single entry would defeat the point.
+func withRecording(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if recordFile == nil || r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		sessionID := r.Header.Get("Mcp-Session-Id")
+
+		var bodyBuf bytes.Buffer
+		if r.Body != nil {
+			if _, err := bodyBuf.ReadFrom(r.Body); err == nil {
+				r.Body = io.NopCloser(&bodyBuf)
+			}
+		}
+		if bodyBuf.Len() > 0 {
+			recordMessage(sessionID, "in", bodyBuf.Bytes())
+		}
+
+		rec := &recordingResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		if hdr := w.Header().Get("Mcp-Session-Id"); hdr != "" {
+			sessionID = hdr
+		}
+		if rec.buf.Len() > 0 {
+			recordMessage(sessionID, "out", rec.buf.Bytes())
+		}
+	})
+}
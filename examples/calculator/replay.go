@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// replayPath is the transcript --replay reads from; empty (the default)
+// leaves every transport dispatching to s.HandleMessage as usual. main
+// wires this to --replay and calls loadReplayFile before serve starts.
+var replayPath = ""
+
+// replayNotFoundCode is returned when an incoming request has no matching
+// recorded response left to hand back — a JSON-RPC server-error code, the
+// same range as requestTooLargeCode and its neighbors (maxrequest.go).
+const replayNotFoundCode = -32007
+
+// replayQueues holds, for each method+params-hash key built by replayKey,
+// the recorded response payloads in the order they were originally
+// recorded — so "same method called twice" pops its two answers in that
+// order, not in some indeterminate one. Reads and writes are both under
+// replayMu since lookupReplayResponse mutates the queue (popping its
+// front) on every call, and nothing else in this fixture's dispatch is
+// single-threaded enough to skip locking it.
+var (
+	replayMu     sync.Mutex
+	replayQueues = map[string][]json.RawMessage{}
+)
+
+// replayRequestPeek is enough of a request's shape to build its replay
+// key and to read the id lookupReplayResponse needs to stamp onto
+// whichever recorded response it returns — raw's own id, not the
+// recorded response's original one, is what a replaying client expects
+// back.
+type replayRequestPeek struct {
+	ID     any             `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// loadReplayFile reads a --record-produced transcript and indexes it for
+// replay: every recorded "in" entry is paired with the very next "out"
+// entry in the file, on the premise that a single scripted session (the
+// only thing --replay is meant to reproduce) records its in/out pairs in
+// strict alternation. An interleaved multi-session transcript would pair
+// entries across sessions incorrectly; that's a real limitation, not a
+// bug this file tries to paper over — --replay is documented for
+// reproducing one recorded session, not a live multi-client recording.
+// Likewise, a recorded JSON-RPC batch's "in" entry is its own single
+// payload (an array), so it indexes under its own array-shaped
+// replayKey rather than per-element; --replay has no per-request
+// matching inside a batch, only across separate top-level messages.
+func loadReplayFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening --replay file: %w", err)
+	}
+	defer file.Close()
+
+	queues := map[string][]json.RawMessage{}
+
+	var pendingKey string
+	var havePending bool
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry recordedMessage
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return fmt.Errorf("--replay file: invalid transcript line %q: %w", scanner.Text(), err)
+		}
+
+		switch entry.Direction {
+		case "in":
+			var req replayRequestPeek
+			_ = json.Unmarshal(entry.Payload, &req)
+			pendingKey = replayKey(req.Method, req.Params)
+			havePending = true
+		case "out":
+			if havePending {
+				queues[pendingKey] = append(queues[pendingKey], entry.Payload)
+				havePending = false
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading --replay file: %w", err)
+	}
+
+	replayMu.Lock()
+	replayQueues = queues
+	replayMu.Unlock()
+	return nil
+}
+
+// replayKey identifies a recorded exchange by method and, when params is
+// present, a hash of its exact bytes — the "optionally a params hash"
+// this fixture's --replay matching uses to tell two calls to the same
+// method apart. Two requests with byte-identical params (down to key
+// order) hash identically; anything looser would need a canonicalized
+// params encoding this fixture doesn't have a confirmed need for yet.
+func replayKey(method string, params json.RawMessage) string {
+	if len(params) == 0 {
+		return method
+	}
+	sum := sha256.Sum256(params)
+	return method + "#" + hex.EncodeToString(sum[:])
+}
+
+// lookupReplayResponse returns the next recorded response for raw's
+// method+params key, with its id rewritten to raw's own id, or an error
+// naming the method when --replay has nothing left recorded for it.
+func lookupReplayResponse(raw []byte) ([]byte, error) {
+	var req replayRequestPeek
+	_ = json.Unmarshal(raw, &req)
+	key := replayKey(req.Method, req.Params)
+
+	replayMu.Lock()
+	queue := replayQueues[key]
+	var response json.RawMessage
+	if len(queue) > 0 {
+		response = queue[0]
+		replayQueues[key] = queue[1:]
+	}
+	replayMu.Unlock()
+
+	if response == nil {
+		return nil, fmt.Errorf("--replay: no recorded response left for method %q", req.Method)
+	}
+
+	var patched map[string]any
+	if err := json.Unmarshal(response, &patched); err != nil {
+		return nil, fmt.Errorf("--replay: recorded response for method %q is not valid JSON: %w", req.Method, err)
+	}
+	patched["id"] = req.ID
+	return json.Marshal(patched)
+}
+
+// newReplayNotFound builds the JSON-RPC error serveStdioTolerant and
+// serveWebSocketConn send back when lookupReplayResponse can't satisfy a
+// request, echoing the request's own id rather than stdioErrorResponse's
+// usual id: null (see validateStdioLine's callers), since by this point
+// the request has already been parsed enough to have one.
+func newReplayNotFound(id any, err error) stdioErrorResponse {
+	return stdioErrorResponse{JSONRPC: "2.0", ID: id, Error: stdioErrorBody{Code: replayNotFoundCode, Message: err.Error()}}
+}
+
+// withReplay wraps the sse/http/both transports' message endpoint,
+// answering every POST directly from lookupReplayResponse instead of
+// forwarding to next, when --replay is set. It sits where withRecording
+// does (inside withCompression, see transport.go), for the same reason:
+// whatever it writes should still get gzip-encoded like a normal
+// response, and recording a replayed session back out via --record
+// (unusual, but not disallowed) should see the same plain JSON
+// withRecording always has.
+func withReplay(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if replayPath == "" || r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var buf bytes.Buffer
+		if _, err := buf.ReadFrom(r.Body); err != nil {
+			http.Error(w, "reading request body", http.StatusBadRequest)
+			return
+		}
+
+		var req replayRequestPeek
+		_ = json.Unmarshal(buf.Bytes(), &req)
+
+		response, err := lookupReplayResponse(buf.Bytes())
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(newReplayNotFound(req.ID, err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(response)
+	})
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func resetReplayQueues(t *testing.T) {
+	t.Helper()
+	replayMu.Lock()
+	replayQueues = map[string][]json.RawMessage{}
+	replayMu.Unlock()
+	t.Cleanup(func() {
+		replayPath = ""
+		replayMu.Lock()
+		replayQueues = map[string][]json.RawMessage{}
+		replayMu.Unlock()
+	})
+}
+
+// TestReplay_RecordThenReplayAgainstAFreshClientReproducesTheSameResults is
+// skipped: mcp-go v0.23.1 has no Streamable HTTP server at all
+// (server.NewStreamableHTTPServer and server.WithEndpointPath don't exist
+// in this version — it ships SSE only, see serveHTTP's doc comment in
+// transport.go).
+func TestReplay_RecordThenReplayAgainstAFreshClientReproducesTheSameResults(t *testing.T) {
+	t.Skip("mcp-go v0.23.1 has no Streamable HTTP server; see serveHTTP's doc comment in transport.go")
+}
+
+// TestLookupReplayResponse_SameMethodTwiceReturnsAnswersInOrder confirms
+// sequential matching: two recorded responses queued under the same
+// method+params key are returned in the order they were recorded, not
+// both collapsed to the first or last one.
+func TestLookupReplayResponse_SameMethodTwiceReturnsAnswersInOrder(t *testing.T) {
+	resetReplayQueues(t)
+
+	req := []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	key := replayKey("ping", nil)
+
+	replayMu.Lock()
+	replayQueues[key] = []json.RawMessage{
+		json.RawMessage(`{"jsonrpc":"2.0","id":99,"result":"first"}`),
+		json.RawMessage(`{"jsonrpc":"2.0","id":99,"result":"second"}`),
+	}
+	replayMu.Unlock()
+
+	first, err := lookupReplayResponse(req)
+	if err != nil {
+		t.Fatalf("first lookupReplayResponse: %v", err)
+	}
+	second, err := lookupReplayResponse(req)
+	if err != nil {
+		t.Fatalf("second lookupReplayResponse: %v", err)
+	}
+
+	if !strings.Contains(string(first), `"result":"first"`) {
+		t.Errorf("first response = %s, want it to contain the first recorded result", first)
+	}
+	if !strings.Contains(string(second), `"result":"second"`) {
+		t.Errorf("second response = %s, want it to contain the second recorded result", second)
+	}
+
+	if _, err := lookupReplayResponse(req); err == nil {
+		t.Error("expected an error once the recorded queue for this method is exhausted")
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// parseByteRange extracts a "range=START-END" query parameter from a
+// resource URI, reusing the HTTP Range header's inclusive byte-range-spec
+// convention. There's nowhere else to carry it: mcp.ReadResourceRequest's
+// params are just {uri} per the spec, with no header bag or separate
+// range field, so the query string on the URI itself is the only
+// per-request channel a client and this fixture can agree on without a
+// confirmed extension to ReadResourceRequest. ok is false when no range
+// parameter is present, in which case the caller should return contents
+// unchanged.
+func parseByteRange(rawURI string) (start, end int, ok bool, err error) {
+	u, perr := url.Parse(rawURI)
+	if perr != nil {
+		return 0, 0, false, nil
+	}
+	spec := u.Query().Get("range")
+	if spec == "" {
+		return 0, 0, false, nil
+	}
+
+	parts := strings.SplitN(spec, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, false, fmt.Errorf("invalid range %q, want START-END", spec)
+	}
+	start, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("invalid range start %q: %w", parts[0], err)
+	}
+	end, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("invalid range end %q: %w", parts[1], err)
+	}
+	if start < 0 || end < start {
+		return 0, 0, false, fmt.Errorf("invalid range %q: end must be >= start >= 0", spec)
+	}
+	return start, end, true, nil
+}
+
+// clampRange turns an inclusive [start, end] byte-range-spec into Go
+// slice bounds against a buffer of length, clamping both ends so an
+// out-of-bounds range (the common "give me past EOF" probe) comes back
+// as a shorter-than-requested slice instead of a panic or an error.
+func clampRange(start, end, length int) (int, int) {
+	if start > length {
+		start = length
+	}
+	end++ // end is inclusive in the byte-range-spec convention
+	if end > length {
+		end = length
+	}
+	if end < start {
+		end = start
+	}
+	return start, end
+}
+
+// sliceResourceContents applies [start, end] to each of contents' text (by
+// UTF-8 byte offset) or blob (by decoded byte offset), leaving any other
+// ResourceContents implementation untouched.
+//
+// This is the documented fallback for a client that can't stream: the
+// handler that produced contents already built the whole thing in
+// memory exactly as it would have without a range, and only the
+// requested slice is sent back afterward. There's no lower-memory,
+// genuinely chunked path available from here — the MCP spec's
+// resources/read result is a single contents array, not a byte stream
+// or a sequence of partial-result messages, and mcp-go has no
+// alternative ReadResourceResult framing we could confirm either. A
+// handler that wants to avoid buffering the unwanted part of a large
+// resource has to know the range itself (see parseByteRange) and only
+// produce that slice in the first place, rather than relying on this
+// wrapper — withByteRange exists for handlers like handleRangeDemoResource
+// that are cheap to regenerate in full and don't need to bother.
+func sliceResourceContents(contents []mcp.ResourceContents, start, end int) ([]mcp.ResourceContents, error) {
+	sliced := make([]mcp.ResourceContents, 0, len(contents))
+	for _, c := range contents {
+		switch v := c.(type) {
+		case mcp.TextResourceContents:
+			b := []byte(v.Text)
+			lo, hi := clampRange(start, end, len(b))
+			v.Text = string(b[lo:hi])
+			sliced = append(sliced, v)
+		case mcp.BlobResourceContents:
+			raw, err := base64.StdEncoding.DecodeString(v.Blob)
+			if err != nil {
+				return nil, fmt.Errorf("decoding blob to apply range: %w", err)
+			}
+			lo, hi := clampRange(start, end, len(raw))
+			v.Blob = base64.StdEncoding.EncodeToString(raw[lo:hi])
+			sliced = append(sliced, v)
+		default:
+			sliced = append(sliced, c)
+		}
+	}
+	return sliced, nil
+}
+
+// withByteRange wraps a resource handler so a "range=START-END" query
+// parameter on the requested URI slices the handler's output before it's
+// returned, supporting partial reads of a resource without the client
+// needing the whole thing. See sliceResourceContents for why this can't
+// be a true streamed/chunked transfer over either HTTP or stdio.
+func withByteRange(next func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error)) func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		contents, err := next(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		start, end, ok, err := parseByteRange(request.Params.URI)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return contents, nil
+		}
+		return sliceResourceContents(contents, start, end)
+	}
+}
+
+// rangeDemoResourceText is a deterministic, multi-kilobyte payload that
+// exists purely so -range query parameters (see parseByteRange) have
+// something non-trivial to act on in tests and manual probing, standing
+// in for "a 2GB resource" at a size this fixture can actually hold in a
+// test assertion.
+var rangeDemoResourceText = strings.Repeat("0123456789abcdef", 512) // 8KB
+
+// handleRangeDemoResource returns rangeDemoResourceText, sliced by
+// withByteRange if the client asked for a range. It doesn't report
+// progress the way handleSlowAddTool does: mcp.ReadResourceRequest has no
+// _meta field at all in the pinned mcp-go, so a resources/read request
+// has nowhere to carry a progressToken for this handler to read in the
+// first place.
+func handleRangeDemoResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "text/plain",
+			Text:     rangeDemoResourceText,
+		},
+	}, nil
+}
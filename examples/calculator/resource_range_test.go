@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestParseByteRange_NoRangeParamIsNotOK(t *testing.T) {
+	_, _, ok, err := parseByteRange("calc://range-demo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Error("expected ok=false with no range query parameter")
+	}
+}
+
+func TestParseByteRange_ParsesStartAndEnd(t *testing.T) {
+	start, end, ok, err := parseByteRange("calc://range-demo?range=10-19")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected ok=true")
+	}
+	if start != 10 || end != 19 {
+		t.Errorf("got [%d, %d], want [10, 19]", start, end)
+	}
+}
+
+func TestParseByteRange_MalformedRangeIsAnError(t *testing.T) {
+	if _, _, _, err := parseByteRange("calc://range-demo?range=nope"); err == nil {
+		t.Error("expected an error for a malformed range")
+	}
+	if _, _, _, err := parseByteRange("calc://range-demo?range=10-5"); err == nil {
+		t.Error("expected an error when end < start")
+	}
+}
+
+func TestSliceResourceContents_TextIsSlicedByByteOffset(t *testing.T) {
+	contents := []mcp.ResourceContents{
+		mcp.TextResourceContents{URI: "u", MIMEType: "text/plain", Text: "0123456789"},
+	}
+	sliced, err := sliceResourceContents(contents, 2, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := sliced[0].(mcp.TextResourceContents)
+	if text.Text != "2345" {
+		t.Errorf("Text = %q, want %q", text.Text, "2345")
+	}
+}
+
+func TestSliceResourceContents_RangePastEndIsClamped(t *testing.T) {
+	contents := []mcp.ResourceContents{
+		mcp.TextResourceContents{URI: "u", MIMEType: "text/plain", Text: "0123456789"},
+	}
+	sliced, err := sliceResourceContents(contents, 8, 1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := sliced[0].(mcp.TextResourceContents)
+	if text.Text != "89" {
+		t.Errorf("Text = %q, want %q", text.Text, "89")
+	}
+}
+
+func TestWithByteRange_NoRangeReturnsFullContents(t *testing.T) {
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = "calc://range-demo"
+
+	contents, err := withByteRange(handleRangeDemoResource)(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := contents[0].(mcp.TextResourceContents)
+	if text.Text != rangeDemoResourceText {
+		t.Errorf("expected the full resource with no range requested, got %d bytes", len(text.Text))
+	}
+}
+
+func TestWithByteRange_RangeSlicesContents(t *testing.T) {
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = "calc://range-demo?range=0-9"
+
+	contents, err := withByteRange(handleRangeDemoResource)(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := contents[0].(mcp.TextResourceContents)
+	if text.Text != rangeDemoResourceText[0:10] {
+		t.Errorf("Text = %q, want the first 10 bytes", text.Text)
+	}
+}
+
+func TestHandleRangeDemoResource_ReturnsFullTextWithNoRange(t *testing.T) {
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = "calc://range-demo"
+
+	contents, err := handleRangeDemoResource(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := contents[0].(mcp.TextResourceContents)
+	if text.Text != rangeDemoResourceText {
+		t.Error("expected the full rangeDemoResourceText with no range query parameter")
+	}
+}
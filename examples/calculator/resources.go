@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerResources wires up the calculator's static resources, including
+// the calculation history log kept in history.go.
+func registerResources(s *server.MCPServer) {
+	s.AddResource(
+		mcp.NewResource("config:///calculator", "Calculator Config", mcp.WithMIMEType("application/json")),
+		recoverResourceHandler("config:///calculator", newTracingResourceHandler("config:///calculator", handleConfigResource)),
+	)
+
+	s.AddResource(
+		mcp.NewResource("calc://history/latest", "Latest calculation", mcp.WithMIMEType("application/json")),
+		recoverResourceHandler("calc://history/latest", handleHistoryLatestResource),
+	)
+
+	s.AddResource(
+		mcp.NewResource("calc://range-demo", "Byte-range read demo", mcp.WithMIMEType("text/plain")),
+		recoverResourceHandler("calc://range-demo", newTracingResourceHandler("calc://range-demo", withByteRange(handleRangeDemoResource))),
+	)
+}
+
+func handleHistoryLatestResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	n := historyLen()
+	if n == 0 {
+		return nil, fmt.Errorf("no calculations recorded yet")
+	}
+
+	return readHistoryEntry(request.Params.URI, n)
+}
+
+// registerHistoryResource adds a resource for a newly recorded calculation
+// so it immediately shows up in resources/list.
+func registerHistoryResource(s *server.MCPServer, index int) {
+	uri := historyURI(index)
+	s.AddResource(
+		mcp.NewResource(uri, fmt.Sprintf("Calculation #%d", index), mcp.WithMIMEType("application/json")),
+		recoverResourceHandler(uri, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return readHistoryEntry(uri, index)
+		}),
+	)
+}
+
+func readHistoryEntry(uri string, index int) ([]mcp.ResourceContents, error) {
+	entry, ok := historyEntry(index)
+	if !ok {
+		return nil, fmt.Errorf("no calculation at index %d", index)
+	}
+
+	text, err := marshalEntry(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     text,
+		},
+	}, nil
+}
+
+func handleConfigResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     `{"operations":["add","subtract","multiply","divide"]}`,
+		},
+	}, nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleConfigResource(t *testing.T) {
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = "config:///calculator"
+
+	contents, err := handleConfigResource(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(contents) != 1 {
+		t.Fatalf("expected exactly one content item, got %d", len(contents))
+	}
+
+	text, ok := contents[0].(mcp.TextResourceContents)
+	if !ok {
+		t.Fatalf("expected TextResourceContents, got %T", contents[0])
+	}
+
+	if text.URI != req.Params.URI {
+		t.Errorf("URI = %q, want %q", text.URI, req.Params.URI)
+	}
+	if text.MIMEType != "application/json" {
+		t.Errorf("MIMEType = %q, want application/json", text.MIMEType)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerRootsTool wires up list_roots. The tool never reaches the
+// client: fetchRootURIs always fails (see its doc comment), so every call
+// returns a tool-domain error and no roots are returned.
+// notifications/roots/list_changed is handled separately, by
+// registerRootsListChangedHook, which only logs that same failure.
+func registerRootsTool(s *server.MCPServer) {
+	tool := mcp.NewTool("list_roots",
+		mcp.WithDescription("Fetch the connected client's roots via roots/list"),
+	)
+	addToolWithMiddleware(s, tool, handleListRootsTool, recoverMiddleware)
+}
+
+func handleListRootsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if server.ServerFromContext(ctx) == nil {
+		return toolResultErrorf("no server session available to request roots from"), nil
+	}
+
+	_, err := fetchRootURIs(ctx)
+	if err != nil {
+		// The client may not have advertised the roots capability; that's a
+		// tool-domain failure, not a malformed request.
+		return toolResultErrorf("roots/list failed: %v", err), nil
+	}
+
+	return nil, nil
+}
+
+// fetchRootURIs always fails: mcp-go v0.23.1's *server.MCPServer has no
+// server-initiated request capability at all — grepping server/*.go for
+// "roots" or "sampling" turns up nothing outside type/notification
+// definitions, confirming srv.RequestRoots was never a real method. The
+// same gap blocks handleAskModelTool's sampling/createMessage call (see
+// sampling.go's doc comment for why raw JSON-RPC plumbing isn't a
+// realistic substitute here either), so this is a deliberate dropped
+// feature pending a real server-initiated-request primitive upstream.
+func fetchRootURIs(ctx context.Context) ([]string, error) {
+	return nil, fmt.Errorf("roots/list is not available: mcp-go v0.23.1's *server.MCPServer has no server-initiated request capability")
+}
+
+// registerRootsListChangedHook would re-fetch and log the client's roots
+// whenever it sends notifications/roots/list_changed, so a server that
+// cares about roots doesn't have to poll for them — but fetchRootURIs
+// always fails (see its doc comment), so this only logs the error.
+func registerRootsListChangedHook(hooks *server.Hooks) {
+	hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+		if method != "notifications/roots/list_changed" {
+			return
+		}
+		if _, err := fetchRootURIs(ctx); err != nil {
+			log.Printf("roots/list after list_changed notification: %v", err)
+		}
+	})
+}
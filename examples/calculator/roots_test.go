@@ -0,0 +1,18 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleListRootsTool_NoServerSessionIsToolDomainError(t *testing.T) {
+	res, err := handleListRootsTool(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsError {
+		t.Error("expected an isError result when no server session is available")
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const askModelDefaultTimeout = 10 * time.Second
+
+// registerAskModelTool wires up ask_model, meant to call back into the
+// client via sampling/createMessage so the Anubis client's sampling
+// capability has something to exercise against. It can't actually do
+// that against this mcp-go version: see handleAskModelTool's doc comment.
+// The tool stays registered, with its full intended argument set, so a
+// client can still see it offered and get back a clear domain error
+// rather than the tool silently not existing.
+func registerAskModelTool(s *server.MCPServer) {
+	tool := mcp.NewTool("ask_model",
+		mcp.WithDescription("Ask the connected client's model a question via sampling/createMessage"),
+		mcp.WithString("question", mcp.Required(), mcp.Description("The prompt to send to the client's model")),
+		mcp.WithString("system_prompt", mcp.Description("Optional system prompt to steer the client's model")),
+		mcp.WithString("preferred_model", mcp.Description("Optional model name hint, passed as ModelPreferences.Hints")),
+		mcp.WithNumber("max_tokens", mcp.Description("Maximum tokens the client's model should generate"), mcp.Min(1)),
+		mcp.WithNumber("timeout_ms", mcp.Description("How long to wait for the client's response before giving up"), mcp.Min(0)),
+	)
+	addToolWithMiddleware(s, tool, handleAskModelTool, recoverMiddleware)
+}
+
+const askModelDefaultMaxTokens = 512
+
+type askModelArgs struct {
+	Question       string  `json:"question"`
+	SystemPrompt   string  `json:"system_prompt" mcp:"optional"`
+	PreferredModel string  `json:"preferred_model" mcp:"optional"`
+	MaxTokens      float64 `json:"max_tokens" mcp:"optional"`
+	TimeoutMs      float64 `json:"timeout_ms" mcp:"optional"`
+}
+
+// handleAskModelTool always returns a tool-domain error: mcp-go v0.23.1's
+// *server.MCPServer has no server-initiated request capability at all.
+// There's no RequestSampling method, no mcp.CreateMessageParams type (the
+// real shape is the anonymous CreateMessageRequest.Params struct), and no
+// confirmed way to add either ourselves — ClientSession's
+// NotificationChannel is notification-only (fire-and-forget, see
+// session_registry.go), with no seam to correlate an eventual client
+// response back to a specific outgoing call. Implementing that would mean
+// intercepting a session's raw transport traffic ahead of mcp-go's own
+// dispatch, which this fixture has no confirmed way to do safely. So this
+// is a deliberate dropped feature, not a renamed one: args are still
+// parsed and validated (so a malformed call fails the way every other
+// tool's does), timeout/cancellation plumbing stays unused pending a real
+// server-initiated-request primitive in the pinned dependency.
+func handleAskModelTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var args askModelArgs
+	if err := bindArguments(request, &args); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if server.ServerFromContext(ctx) == nil {
+		return toolResultErrorf("no server session available to sample from"), nil
+	}
+
+	return toolResultErrorf("sampling/createMessage is not available: mcp-go v0.23.1's *server.MCPServer has no server-initiated request capability"), nil
+}
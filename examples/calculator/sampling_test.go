@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleAskModelTool_MissingQuestionIsToolDomainError(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{}
+
+	res, err := handleAskModelTool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsError {
+		t.Error("expected an isError result for a missing question argument")
+	}
+}
+
+func TestHandleAskModelTool_WithSystemPromptAndModelPreferencesStillRequiresASession(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"question":        "what is 2+2?",
+		"system_prompt":   "Answer tersely.",
+		"preferred_model": "claude",
+		"max_tokens":      128.0,
+	}
+
+	res, err := handleAskModelTool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsError {
+		t.Error("expected an isError result when no server session is available")
+	}
+}
+
+func TestHandleAskModelTool_NoServerSessionIsToolDomainError(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"question": "what is 2+2?"}
+
+	// context.Background() carries no *server.MCPServer, mirroring a client
+	// that never completed initialize — there's nothing to sample from.
+	res, err := handleAskModelTool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsError {
+		t.Error("expected an isError result when no server session is available")
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import "github.com/mark3labs/mcp-go/mcp"
+
+// withArray and withObject are this fixture's own array and object
+// property builders, written while the module was still on mcp-go v0.8.5
+// (which only ships WithString/WithNumber/WithBoolean); the module now
+// pins v0.23.1 and keeps using these. They follow the same mcp.ToolOption
+// shape as the upstream builders so call sites read identically to
+// mcp.WithString/mcp.WithNumber.
+func withArray(name string, schema map[string]any, opts ...propertyOption) mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		prop := map[string]any{
+			"type":  "array",
+			"items": schema,
+		}
+		for _, opt := range opts {
+			opt(name, prop)
+		}
+		t.InputSchema.Properties[name] = prop
+	}
+}
+
+func withObject(name string, properties map[string]any, opts ...propertyOption) mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		prop := map[string]any{
+			"type":       "object",
+			"properties": properties,
+		}
+		for _, opt := range opts {
+			opt(name, prop)
+		}
+		t.InputSchema.Properties[name] = prop
+	}
+}
+
+// propertyOption mutates a property's JSON Schema fragment and, if needed,
+// marks the named property required on the enclosing tool.
+type propertyOption func(name string, prop map[string]any)
+
+// required marks the property as required on the tool's inputSchema,
+// mirroring mcp.Required()'s effect on WithString/WithNumber.
+func required() propertyOption {
+	return func(name string, prop map[string]any) {
+		prop["__required__"] = true
+	}
+}
+
+// items describes the JSON Schema for an array property's elements.
+func items(schema map[string]any) map[string]any {
+	return schema
+}
+
+// stringSchema is a minimal JSON Schema fragment for a string element type,
+// for use with withArray's Items.
+func stringSchema() map[string]any {
+	return map[string]any{"type": "string"}
+}
+
+// numberSchema is a minimal JSON Schema fragment for a number element type.
+func numberSchema() map[string]any {
+	return map[string]any{"type": "number"}
+}
+
+// finalizeRequired scans a tool's properties for the internal
+// "__required__" marker left by required(), moves the affected property
+// names onto t.InputSchema.Required, and strips the marker. Call this once
+// after all withArray/withObject options have run, since ToolOptions apply
+// in order and Required is a property of the tool, not of a single
+// property's schema fragment.
+func finalizeRequired(t *mcp.Tool) {
+	for name, raw := range t.InputSchema.Properties {
+		prop, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		if _, marked := prop["__required__"]; marked {
+			delete(prop, "__required__")
+			t.InputSchema.Required = append(t.InputSchema.Required, name)
+		}
+	}
+}
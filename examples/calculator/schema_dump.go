@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ToolSchemas returns the input/output JSON Schema for every tool
+// registered on s, keyed by tool name. It drives itself through
+// s.HandleMessage exactly the way a real tools/list round trip would —
+// the same entry point serveStdioTolerant dispatches through — paging
+// through every cursor, so the result is guaranteed to match what a
+// connected client would actually see from tools/list: there's no
+// separate schema-construction path that could drift out of sync with it.
+func ToolSchemas(ctx context.Context, s *server.MCPServer) (map[string]json.RawMessage, error) {
+	schemas := map[string]json.RawMessage{}
+	cursor := ""
+
+	for {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		req := map[string]any{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"method":  "tools/list",
+			"params":  params,
+		}
+		raw, err := json.Marshal(req)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling tools/list request: %w", err)
+		}
+
+		respBytes, err := json.Marshal(s.HandleMessage(ctx, raw))
+		if err != nil {
+			return nil, fmt.Errorf("marshaling tools/list response: %w", err)
+		}
+
+		var parsed struct {
+			Result struct {
+				Tools []struct {
+					Name         string          `json:"name"`
+					InputSchema  json.RawMessage `json:"inputSchema"`
+					OutputSchema json.RawMessage `json:"outputSchema,omitempty"`
+				} `json:"tools"`
+				NextCursor string `json:"nextCursor"`
+			} `json:"result"`
+			Error *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(respBytes, &parsed); err != nil {
+			return nil, fmt.Errorf("unmarshaling tools/list response: %w", err)
+		}
+		if parsed.Error != nil {
+			return nil, fmt.Errorf("tools/list: %s", parsed.Error.Message)
+		}
+
+		for _, tool := range parsed.Result.Tools {
+			entry := map[string]json.RawMessage{"inputSchema": tool.InputSchema}
+			if len(tool.OutputSchema) > 0 {
+				entry["outputSchema"] = tool.OutputSchema
+			}
+			b, err := json.Marshal(entry)
+			if err != nil {
+				return nil, fmt.Errorf("marshaling schema for %s: %w", tool.Name, err)
+			}
+			schemas[tool.Name] = b
+		}
+
+		if parsed.Result.NextCursor == "" {
+			break
+		}
+		cursor = parsed.Result.NextCursor
+	}
+
+	return schemas, nil
+}
+
+// DumpToolSchemas writes ToolSchemas' result to w as indented JSON, for
+// committing as a golden file or feeding to client-side codegen. main
+// wires this to --dump-tool-schema.
+func DumpToolSchemas(ctx context.Context, s *server.MCPServer, w io.Writer) error {
+	schemas, err := ToolSchemas(ctx, s)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(schemas)
+}
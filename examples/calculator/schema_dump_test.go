@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestToolSchemas_MatchesToolsListInputSchema(t *testing.T) {
+	srv := newTestServer()
+
+	schemas, err := ToolSchemas(context.Background(), srv)
+	if err != nil {
+		t.Fatalf("ToolSchemas: %v", err)
+	}
+
+	entryBytes, ok := schemas["calculate"]
+	if !ok {
+		t.Fatal("expected a schema entry for calculate")
+	}
+
+	var entry struct {
+		InputSchema json.RawMessage `json:"inputSchema"`
+	}
+	if err := json.Unmarshal(entryBytes, &entry); err != nil {
+		t.Fatalf("unmarshaling entry: %v", err)
+	}
+
+	req := map[string]any{"jsonrpc": "2.0", "id": 99, "method": "tools/list", "params": map[string]any{}}
+	raw, _ := json.Marshal(req)
+	listResp, err := json.Marshal(srv.HandleMessage(context.Background(), raw))
+	if err != nil {
+		t.Fatalf("marshaling tools/list response: %v", err)
+	}
+
+	var parsed struct {
+		Result struct {
+			Tools []struct {
+				Name        string          `json:"name"`
+				InputSchema json.RawMessage `json:"inputSchema"`
+			} `json:"tools"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(listResp, &parsed); err != nil {
+		t.Fatalf("unmarshaling tools/list: %v", err)
+	}
+
+	var wantSchema json.RawMessage
+	for _, tool := range parsed.Result.Tools {
+		if tool.Name == "calculate" {
+			wantSchema = tool.InputSchema
+		}
+	}
+	if wantSchema == nil {
+		t.Fatal("tools/list didn't return calculate")
+	}
+
+	if !bytes.Equal(normalizeJSON(t, entry.InputSchema), normalizeJSON(t, wantSchema)) {
+		t.Errorf("ToolSchemas inputSchema = %s, want %s", entry.InputSchema, wantSchema)
+	}
+}
+
+func TestDumpToolSchemas_ProducesValidJSON(t *testing.T) {
+	srv := newTestServer()
+
+	var buf bytes.Buffer
+	if err := DumpToolSchemas(context.Background(), srv, &buf); err != nil {
+		t.Fatalf("DumpToolSchemas: %v", err)
+	}
+
+	var out map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshaling dump output: %v", err)
+	}
+	if _, ok := out["calculate"]; !ok {
+		t.Error("expected calculate in the dumped schema map")
+	}
+}
+
+func normalizeJSON(t *testing.T, raw json.RawMessage) []byte {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		t.Fatalf("normalizing json: %v", err)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("re-marshaling json: %v", err)
+	}
+	return b
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestWithArray_BuildsItemsSchema(t *testing.T) {
+	tool := mcp.NewTool("batch",
+		withArray("operations", stringSchema(), required()),
+	)
+	finalizeRequired(&tool)
+
+	prop, ok := tool.InputSchema.Properties["operations"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected operations property to be present")
+	}
+
+	if prop["type"] != "array" {
+		t.Errorf("type = %v, want array", prop["type"])
+	}
+
+	items, ok := prop["items"].(map[string]any)
+	if !ok || items["type"] != "string" {
+		t.Errorf("items = %v, want {type: string}", prop["items"])
+	}
+
+	if len(tool.InputSchema.Required) != 1 || tool.InputSchema.Required[0] != "operations" {
+		t.Errorf("required = %v, want [operations]", tool.InputSchema.Required)
+	}
+}
+
+func TestWithObject_BuildsPropertiesSchema(t *testing.T) {
+	tool := mcp.NewTool("filter",
+		withObject("filter", map[string]any{
+			"min": numberSchema(),
+			"max": numberSchema(),
+		}),
+	)
+
+	prop, ok := tool.InputSchema.Properties["filter"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected filter property to be present")
+	}
+
+	if prop["type"] != "object" {
+		t.Errorf("type = %v, want object", prop["type"])
+	}
+}
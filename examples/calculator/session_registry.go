@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// sessionRegistry tracks every currently-registered ClientSession by ID,
+// for code that needs to push a notification to a specific session from
+// outside that session's own request context: sse_keepalive.go's
+// background ticker, and notifyResourceUpdated's fan-out to subscribers
+// of a resource that may not be the session making the current request.
+// *server.MCPServer has no such by-ID addressing itself —
+// SendNotificationToClient only reaches the session already embedded in
+// the ctx it's given, and SendNotificationToAllClients reaches every
+// session, with nothing in between — so this fixture keeps its own
+// registry, populated via Hooks.AddOnRegisterSession/AddOnUnregisterSession.
+var sessionRegistry = struct {
+	mu   sync.Mutex
+	byID map[string]server.ClientSession
+}{byID: map[string]server.ClientSession{}}
+
+// registerSessionRegistryHooks must run before any hook that calls
+// sendNotificationToSession, so main wires it in first.
+func registerSessionRegistryHooks(hooks *server.Hooks) {
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		sessionRegistry.mu.Lock()
+		defer sessionRegistry.mu.Unlock()
+		sessionRegistry.byID[session.SessionID()] = session
+	})
+
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		sessionRegistry.mu.Lock()
+		defer sessionRegistry.mu.Unlock()
+		delete(sessionRegistry.byID, session.SessionID())
+	})
+}
+
+// sendNotificationToSession delivers method/params to sessionID's
+// notification channel, building the same mcp.JSONRPCNotification shape
+// *server.MCPServer's own SendNotificationToClient/SendNotificationToAllClients
+// do. It's a no-op, not an error, when sessionID isn't registered, hasn't
+// finished initializing, or its channel is full — the same
+// fire-and-forget contract those two already have.
+func sendNotificationToSession(sessionID, method string, params map[string]any) {
+	sessionRegistry.mu.Lock()
+	session, ok := sessionRegistry.byID[sessionID]
+	sessionRegistry.mu.Unlock()
+	if !ok || !session.Initialized() {
+		return
+	}
+
+	notification := mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: method,
+			Params: mcp.NotificationParams{AdditionalFields: params},
+		},
+	}
+	select {
+	case session.NotificationChannel() <- notification:
+	default:
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import "sync"
+
+// SessionStore abstracts where this fixture's own per-session state
+// lives — today that's counter.go's per-session counter, migrated onto
+// this interface as the worked example below, and fault.go's
+// per-session response counter (faultCounterKey). A horizontally-scaled
+// deployment with multiple replicas behind a load balancer could swap
+// sessionStore for a shared backend (Redis, say) so a counter_get on
+// replica B sees the value a counter_increment wrote on replica A.
+//
+// This interface only covers state this fixture itself defined and
+// owns. It does NOT cover mcp-go's own session registry (the
+// session id -> session in-process bookkeeping its SSE transport does
+// internally) or resource subscriptions
+// (subscriptions.go) — neither is exposed by server.MCPServer as
+// something swappable from outside the library, so routing a session's
+// requests to the replica that holds its mcp-go-internal state, and
+// fanning notifications/resources/updated out to every replica with a
+// subscriber for a URI, both still require either a sticky load
+// balancer or a change inside mcp-go itself; no amount of wrapping from
+// this fixture can make that cross-replica-consistent on its own.
+// Anyone implementing a shared SessionStore backend should keep that
+// boundary in mind: it closes the gap for state declared here, not for
+// the transport's own session affinity.
+type SessionStore interface {
+	// Get returns key's value for sessionID, and whether it was set.
+	Get(sessionID, key string) (string, bool)
+	// Set stores value under key for sessionID, creating the session's
+	// entry if this is its first key.
+	Set(sessionID, key, value string)
+	// DeleteSession drops every key stored for sessionID. Called when a
+	// session disconnects, the same place registerCounterHooks used to
+	// call deleteCounter directly.
+	DeleteSession(sessionID string)
+}
+
+// sessionStore is the SessionStore every per-session fixture feature
+// should read and write through. Defaults to an in-memory implementation
+// matching this fixture's prior behavior exactly; a future Redis-backed
+// implementation would be swapped in here, before any session state has
+// been written, since this package-level var isn't safe to reassign
+// concurrently with use.
+var sessionStore SessionStore = newInMemorySessionStore()
+
+// inMemorySessionStore is SessionStore's default implementation: a
+// mutex-guarded nested map, the same pattern counter.go and
+// subscriptions.go already used before this abstraction existed.
+type inMemorySessionStore struct {
+	mu   sync.Mutex
+	byID map[string]map[string]string
+}
+
+func newInMemorySessionStore() *inMemorySessionStore {
+	return &inMemorySessionStore{byID: map[string]map[string]string{}}
+}
+
+func (s *inMemorySessionStore) Get(sessionID, key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.byID[sessionID][key]
+	return value, ok
+}
+
+func (s *inMemorySessionStore) Set(sessionID, key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.byID[sessionID] == nil {
+		s.byID[sessionID] = map[string]string{}
+	}
+	s.byID[sessionID][key] = value
+}
+
+func (s *inMemorySessionStore) DeleteSession(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.byID, sessionID)
+}
@@ -0,0 +1,64 @@
+package main
+
+import "testing"
+
+func TestInMemorySessionStore_GetSetRoundTrips(t *testing.T) {
+	s := newInMemorySessionStore()
+
+	if _, ok := s.Get("sess-1", "k"); ok {
+		t.Fatal("expected no value before Set")
+	}
+	s.Set("sess-1", "k", "v")
+	got, ok := s.Get("sess-1", "k")
+	if !ok || got != "v" {
+		t.Errorf("Get() = (%q, %v), want (%q, true)", got, ok, "v")
+	}
+}
+
+func TestInMemorySessionStore_KeysAreScopedPerSession(t *testing.T) {
+	s := newInMemorySessionStore()
+
+	s.Set("sess-a", "k", "a")
+	s.Set("sess-b", "k", "b")
+
+	got, _ := s.Get("sess-a", "k")
+	if got != "a" {
+		t.Errorf("sess-a's k = %q, want %q", got, "a")
+	}
+	got, _ = s.Get("sess-b", "k")
+	if got != "b" {
+		t.Errorf("sess-b's k = %q, want %q", got, "b")
+	}
+}
+
+func TestInMemorySessionStore_DeleteSessionDropsEveryKey(t *testing.T) {
+	s := newInMemorySessionStore()
+
+	s.Set("sess-1", "a", "1")
+	s.Set("sess-1", "b", "2")
+	s.DeleteSession("sess-1")
+
+	if _, ok := s.Get("sess-1", "a"); ok {
+		t.Error("expected key a to be gone after DeleteSession")
+	}
+	if _, ok := s.Get("sess-1", "b"); ok {
+		t.Error("expected key b to be gone after DeleteSession")
+	}
+}
+
+func TestCounter_GoesThroughSessionStore(t *testing.T) {
+	const session = "counter-sessionstore-1"
+	t.Cleanup(func() { deleteCounter(session) })
+
+	if incrementCounter(session) != 1 {
+		t.Fatal("expected the first increment to return 1")
+	}
+	if incrementCounter(session) != 2 {
+		t.Fatal("expected the second increment to return 2")
+	}
+
+	raw, ok := sessionStore.Get(session, counterKey)
+	if !ok || raw != "2" {
+		t.Errorf("sessionStore.Get(counterKey) = (%q, %v), want (\"2\", true)", raw, ok)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRunHTTPServer_AlreadyCancelledContextReturnsImmediately(t *testing.T) {
+	cfg := transportConfig{Host: "127.0.0.1", Port: 0}
+	ln, err := listen(cfg)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- runHTTPServer(ctx, ln, http.NewServeMux(), cfg) }()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("expected runHTTPServer to report the context's cancellation")
+		}
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("runHTTPServer did not return promptly for an already-cancelled context")
+	}
+}
+
+func TestRunHTTPServer_ShutsDownOnCancellation(t *testing.T) {
+	cfg := transportConfig{Host: "127.0.0.1", Port: 0}
+	ln, err := listen(cfg)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() { done <- runHTTPServer(ctx, ln, http.NewServeMux(), cfg) }()
+
+	// Give the server a moment to start accepting before we stop it.
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error from a clean shutdown: %v", err)
+		}
+	case <-time.After(shutdownGrace + time.Second):
+		t.Fatal("runHTTPServer did not shut down within the grace period")
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ssePingInterval is how often runSSEKeepAlive sends a keep-alive
+// notification to an open SSE session, so an idle
+// connection never goes quiet long enough for an intermediary (this
+// fixture was built against an nginx ingress that cut idle connections
+// at 60s) to cut it. Zero disables the feature entirely: no goroutine
+// runs, and no session gets one. main wires this to --keepalive-interval,
+// default 25s.
+//
+// There's no confirmed way to write directly into mcp-go's own SSE
+// response stream from outside it — server.NewSSEServer owns that
+// http.ResponseWriter end to end, the same gap keepAliveInterval's doc
+// comment describes for the silent-session reaper below it — so this
+// sends a real notification over sendNotificationToSession (see
+// session_registry.go), the same fire-and-forget primitive
+// notifyResourceUpdated uses, rather than a raw SSE comment line.
+// sseKeepAliveMethod isn't an MCP spec method; per JSON-RPC 2.0 a
+// notification with a method the receiver doesn't recognize is simply
+// ignored, so it's safe to send to any client, including ones that
+// predate this feature.
+var ssePingInterval = 25 * time.Second
+
+const sseKeepAliveMethod = "notifications/keepalive"
+
+// sseKeepAliveStops tracks one stop channel per session with a running
+// keep-alive goroutine, the same mutex-guarded map convention
+// subscriptions.go and sessionstore.go use for per-session state.
+var sseKeepAliveStops = struct {
+	mu   sync.Mutex
+	byID map[string]chan struct{}
+}{byID: map[string]chan struct{}{}}
+
+// registerSSEKeepAliveHooks starts a session's keep-alive goroutine the
+// first time traffic from it is seen, and stops it once mcp-go reports
+// the session gone — one goroutine per connection, tied to its lifecycle,
+// and none left running after disconnect.
+func registerSSEKeepAliveHooks(hooks *server.Hooks) {
+	hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+		if ssePingInterval <= 0 {
+			return
+		}
+		sessionID := sessionIDFromContext(ctx)
+		if sessionID == "" {
+			return
+		}
+		startSSEKeepAlive(sessionID)
+	})
+
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		stopSSEKeepAlive(session.SessionID())
+	})
+}
+
+// startSSEKeepAlive is idempotent: a session that's already got a running
+// goroutine (every request after its first) is left alone.
+func startSSEKeepAlive(sessionID string) {
+	sseKeepAliveStops.mu.Lock()
+	defer sseKeepAliveStops.mu.Unlock()
+	if _, running := sseKeepAliveStops.byID[sessionID]; running {
+		return
+	}
+	stop := make(chan struct{})
+	sseKeepAliveStops.byID[sessionID] = stop
+	go runSSEKeepAlive(sessionID, stop)
+}
+
+func runSSEKeepAlive(sessionID string, stop chan struct{}) {
+	ticker := time.NewTicker(ssePingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			sendNotificationToSession(sessionID, sseKeepAliveMethod, map[string]any{})
+		}
+	}
+}
+
+func stopSSEKeepAlive(sessionID string) {
+	sseKeepAliveStops.mu.Lock()
+	stop, running := sseKeepAliveStops.byID[sessionID]
+	if running {
+		delete(sseKeepAliveStops.byID, sessionID)
+	}
+	sseKeepAliveStops.mu.Unlock()
+
+	if running {
+		close(stop)
+	}
+}
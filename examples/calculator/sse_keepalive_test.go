@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRunSSEKeepAlive_SendsAtLeastTwoNotificationsThenStopsOnClose(t *testing.T) {
+	srv := newTestServer()
+	session := &fakeClientSession{sessionID: "sess-keepalive", notifChan: make(chan mcp.JSONRPCNotification, 10)}
+	session.Initialize()
+	if err := srv.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("RegisterSession: %v", err)
+	}
+
+	stop := make(chan struct{})
+	origInterval := ssePingInterval
+	ssePingInterval = 10 * time.Millisecond
+	defer func() { ssePingInterval = origInterval }()
+
+	go runSSEKeepAlive(session.SessionID(), stop)
+
+	seen := 0
+	deadline := time.After(time.Second)
+	for seen < 2 {
+		select {
+		case notif := <-session.notifChan:
+			if notif.Method != sseKeepAliveMethod {
+				t.Fatalf("Method = %q, want %q", notif.Method, sseKeepAliveMethod)
+			}
+			seen++
+		case <-deadline:
+			t.Fatalf("only saw %d keep-alive notifications within 1s, want at least 2", seen)
+		}
+	}
+
+	close(stop)
+}
+
+func TestStartStopSSEKeepAlive_OneGoroutinePerSessionNoneAfterStop(t *testing.T) {
+	origInterval := ssePingInterval
+	ssePingInterval = 10 * time.Millisecond
+	defer func() { ssePingInterval = origInterval }()
+
+	baseline := runtime.NumGoroutine()
+
+	startSSEKeepAlive("sess-gr")
+	startSSEKeepAlive("sess-gr") // idempotent: must not start a second goroutine
+
+	afterStart := waitForGoroutineCount(t, baseline+1)
+	if afterStart != baseline+1 {
+		t.Fatalf("goroutine count = %d, want exactly %d (baseline+1)", afterStart, baseline+1)
+	}
+
+	stopSSEKeepAlive("sess-gr")
+	stopSSEKeepAlive("sess-gr") // idempotent: must not panic on a second stop
+
+	afterStop := waitForGoroutineCount(t, baseline)
+	if afterStop != baseline {
+		t.Fatalf("goroutine count after stop = %d, want baseline %d", afterStop, baseline)
+	}
+}
+
+// waitForGoroutineCount polls runtime.NumGoroutine up to a second,
+// returning as soon as it matches want (or the last observed value on
+// timeout), since goroutine teardown isn't instantaneous.
+func waitForGoroutineCount(t *testing.T, want int) int {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	var got int
+	for time.Now().Before(deadline) {
+		got = runtime.NumGoroutine()
+		if got == want {
+			return got
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return got
+}
+
+func TestSSEPingInterval_DefaultsToTwentyFiveSeconds(t *testing.T) {
+	if ssePingInterval != 25*time.Second {
+		t.Fatalf("ssePingInterval = %v, want 25s (main's -keepalive-interval default mirrors this)", ssePingInterval)
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// serveStdioTolerant stands in for server.ServeStdio on the stdio
+// transport. The one behavior this fixture needs to guarantee for itself —
+// that a framing bug on the client side (truncated JSON, a stray
+// non-object value) gets a JSON-RPC error response instead of taking the
+// process down — isn't something we can confirm or patch on ServeStdio
+// from here, so this loop owns stdin/stdout directly and validates each
+// line itself before ever handing it to s.HandleMessage, the same
+// per-message entry point mcp-go's SSE transport is built on. A line that parses as a JSON object gets exactly the dispatch
+// handling stdio always had; only EOF or an unrecoverable read error ends
+// the loop.
+func serveStdioTolerant(ctx context.Context, s *server.MCPServer) error {
+	reader := bufio.NewReaderSize(os.Stdin, stdioBufferSize)
+
+	var writeMu sync.Mutex
+	writeRaw := func(b []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		if _, err := os.Stdout.Write(append(b, '\n')); err != nil {
+			return fmt.Errorf("writing response: %w", err)
+		}
+		return nil
+	}
+	writeResponse := func(v any) error {
+		if v == nil {
+			return nil
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("marshaling response: %w", err)
+		}
+		return writeRaw(b)
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	pingStop := make(chan struct{})
+	defer close(pingStop)
+	if serverPingInterval > 0 {
+		go runServerPing(sessionOrStdioID(ctx), writeRaw, pingStop, func(reason string) {
+			log.Printf("stdio: %s; closing stdin to end the session", reason)
+			// Best-effort: closing the fd out from under a blocked Read is
+			// how you unblock it in Go, but there's no portable guarantee
+			// it wakes the read promptly on every OS. A silent stdio
+			// client was always going to leave this process idle either
+			// way; this just logs why before trying to end it.
+			_ = os.Stdin.Close()
+		})
+	}
+
+	for {
+		line, err := readStdioMessage(reader)
+
+		if errors.Is(err, errContentLengthTooLarge) {
+			if werr := writeResponse(newStdioRequestTooLarge()); werr != nil {
+				return werr
+			}
+			continue
+		}
+
+		if line != "" {
+			if maxRequestBytes > 0 && len(line) > maxRequestBytes {
+				if werr := writeResponse(newStdioRequestTooLarge()); werr != nil {
+					return werr
+				}
+				if err != nil {
+					return normalizeStdioErr(fmt.Errorf("reading stdin: %w", err))
+				}
+				continue
+			}
+
+			switch validated, kind := validateStdioLine(line); kind {
+			case stdioLineParseError:
+				if werr := writeResponse(newStdioParseError()); werr != nil {
+					return werr
+				}
+			case stdioLineInvalidRequest:
+				if werr := writeResponse(newStdioInvalidRequest()); werr != nil {
+					return werr
+				}
+			case stdioLineOK:
+				if tryHandlePingResponse(sessionOrStdioID(ctx), validated) {
+					break
+				}
+				var env jsonrpcEnvelope
+				_ = json.Unmarshal(validated, &env)
+				if violation := checkProtocolPreconditions(sessionOrStdioID(ctx), env); violation != nil {
+					if werr := writeResponse(violation); werr != nil {
+						return werr
+					}
+					break
+				}
+
+				logDebugMessage(ctx, "in", validated, 0)
+				recordMessage(sessionOrStdioID(ctx), "in", validated)
+
+				wg.Add(1)
+				go func(raw []byte, method string) {
+					defer wg.Done()
+					start := time.Now()
+
+					var b []byte
+					if replayPath != "" {
+						replayed, err := lookupReplayResponse(raw)
+						if err != nil {
+							b, _ = json.Marshal(newReplayNotFound(env.ID, err))
+						} else {
+							b = replayed
+						}
+					} else {
+						response := s.HandleMessage(ctx, raw)
+						if response == nil {
+							return
+						}
+						marshaled, err := json.Marshal(response)
+						if err != nil {
+							log.Printf("stdio: marshaling response: %v", err)
+							return
+						}
+						if method == "initialize" {
+							marshaled = rewriteInitializeResponse(marshaled)
+						}
+						b = marshaled
+					}
+
+					logDebugMessage(ctx, "out", b, time.Since(start))
+					recordMessage(sessionOrStdioID(ctx), "out", b)
+					b, ok := faultOutboundResponse(sessionOrStdioID(ctx), method, b)
+					if !ok {
+						return
+					}
+					if werr := writeRaw(b); werr != nil {
+						log.Printf("stdio: %v", werr)
+					}
+				}(validated, env.Method)
+			case stdioLineBatch:
+				var elements []json.RawMessage
+				_ = json.Unmarshal(validated, &elements)
+
+				logDebugMessage(ctx, "in", validated, 0)
+				recordMessage(sessionOrStdioID(ctx), "in", validated)
+
+				wg.Add(1)
+				go func(elements []json.RawMessage) {
+					defer wg.Done()
+					start := time.Now()
+					b := dispatchJSONRPCBatch(ctx, s, sessionOrStdioID(ctx), elements)
+					if b == nil {
+						return
+					}
+					logDebugMessage(ctx, "out", b, time.Since(start))
+					recordMessage(sessionOrStdioID(ctx), "out", b)
+					if werr := writeRaw(b); werr != nil {
+						log.Printf("stdio: %v", werr)
+					}
+				}(elements)
+			}
+		}
+
+		if err != nil {
+			return normalizeStdioErr(fmt.Errorf("reading stdin: %w", err))
+		}
+	}
+}
+
+type stdioLineKind int
+
+const (
+	stdioLineOK stdioLineKind = iota
+	stdioLineParseError
+	stdioLineInvalidRequest
+	// stdioLineBatch marks a line that parsed as a non-empty JSON array —
+	// a JSON-RPC batch (see jsonrpc_batch.go) rather than a single
+	// request or notification.
+	stdioLineBatch
+)
+
+// validateStdioLine classifies one line of input before it's trusted
+// enough to hand to s.HandleMessage: malformed JSON is a parse error
+// (-32700); JSON that parses but is neither an object nor a non-empty
+// array (a bare string, number, bool, null, or an empty array) is an
+// invalid request (-32600); a non-empty array is a batch, returned
+// unparsed for dispatchJSONRPCBatch to unmarshal into its elements.
+// Neither a single malformed value nor an empty batch is something
+// HandleMessage should ever see.
+func validateStdioLine(line string) ([]byte, stdioLineKind) {
+	raw := []byte(line)
+
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, stdioLineParseError
+	}
+	switch t := v.(type) {
+	case map[string]any:
+		return raw, stdioLineOK
+	case []any:
+		if len(t) == 0 {
+			return nil, stdioLineInvalidRequest
+		}
+		return raw, stdioLineBatch
+	default:
+		return nil, stdioLineInvalidRequest
+	}
+}
+
+// stdioParseErrorResponse and stdioInvalidRequestResponse are hand-built
+// JSON-RPC error envelopes rather than mcp.JSONRPCError values: both cases
+// arise before there's any request id to echo back (a malformed line has
+// no reliable id to extract), so the response is always id: null, exactly
+// as JSON-RPC 2.0 specifies for this situation.
+type stdioErrorResponse struct {
+	JSONRPC string         `json:"jsonrpc"`
+	ID      any            `json:"id"`
+	Error   stdioErrorBody `json:"error"`
+}
+
+type stdioErrorBody struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func newStdioParseError() stdioErrorResponse {
+	return stdioErrorResponse{JSONRPC: "2.0", ID: nil, Error: stdioErrorBody{Code: -32700, Message: "Parse error"}}
+}
+
+func newStdioInvalidRequest() stdioErrorResponse {
+	return stdioErrorResponse{JSONRPC: "2.0", ID: nil, Error: stdioErrorBody{Code: -32600, Message: "Invalid Request"}}
+}
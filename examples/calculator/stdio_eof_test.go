@@ -0,0 +1,34 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestNormalizeStdioErr_EOFBecomesNil(t *testing.T) {
+	if err := normalizeStdioErr(io.EOF); err != nil {
+		t.Errorf("normalizeStdioErr(io.EOF) = %v, want nil", err)
+	}
+}
+
+func TestNormalizeStdioErr_WrappedEOFBecomesNil(t *testing.T) {
+	wrapped := fmt.Errorf("reading stdin: %w", io.EOF)
+	if err := normalizeStdioErr(wrapped); err != nil {
+		t.Errorf("normalizeStdioErr(wrapped EOF) = %v, want nil", err)
+	}
+}
+
+func TestNormalizeStdioErr_OtherErrorPassesThrough(t *testing.T) {
+	want := errors.New("boom")
+	if err := normalizeStdioErr(want); err != want {
+		t.Errorf("normalizeStdioErr(boom) = %v, want %v", err, want)
+	}
+}
+
+func TestNormalizeStdioErr_NilStaysNil(t *testing.T) {
+	if err := normalizeStdioErr(nil); err != nil {
+		t.Errorf("normalizeStdioErr(nil) = %v, want nil", err)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// stdioBufferSize sizes serveStdioTolerant's bufio.Reader. It's this
+// fixture's own knob, not a real server.WithStdioBufferSize: there's no
+// confirmed mcp-go option by that name, and this fixture doesn't call
+// server.ServeStdio at all (see serveStdioTolerant's own doc comment), so
+// there'd be nothing for such an option to configure even if it existed.
+// What's actually true about large single-line messages on this transport:
+// reader.ReadString('\n') (unlike bufio.Scanner's fixed token buffer) grows
+// past stdioBufferSize automatically as it accumulates a line, so a message
+// bigger than this buffer is never dropped or truncated — stdioBufferSize
+// only tunes how many bytes each underlying Read(2) call asks the kernel
+// for, which matters for throughput on very large messages, not
+// correctness. main wires this to --stdio-buffer-size.
+var stdioBufferSize = 64 * 1024
+
+// stdioFramingLines and stdioFramingContentLength are the two values
+// --stdio-framing accepts. Lines is the default: one compact JSON value
+// per line, exactly how this transport has always worked. Content-length
+// is the LSP base protocol's framing (one or more "Name: value" headers,
+// a blank line, then exactly Content-Length bytes of body) and is this
+// fixture's answer to a client that pretty-prints its JSON across
+// multiple lines, which line framing has no way to accommodate: it would
+// dispatch each embedded newline's worth as its own (invalid) message.
+const (
+	stdioFramingLines         = "lines"
+	stdioFramingContentLength = "content-length"
+)
+
+// stdioFramingMode selects how serveStdioTolerant delimits one message
+// from the next. main wires this to --stdio-framing and rejects any value
+// that isn't one of the two constants above before serve ever starts.
+var stdioFramingMode = stdioFramingLines
+
+// errContentLengthTooLarge is returned by readContentLengthFramedMessage
+// when a declared Content-Length exceeds maxRequestBytes, distinctly from
+// an ordinary read error so serveStdioTolerant's loop can respond with a
+// JSON-RPC error and keep the session open instead of tearing it down the
+// way an actual I/O failure does.
+var errContentLengthTooLarge = errors.New("content-length exceeds the configured max-request-bytes")
+
+// readStdioMessage returns the next complete message from reader as a
+// trimmed string, using whichever framing stdioFramingMode selects. err is
+// io.EOF (or a wrapped read error) once the client closes its end;
+// errContentLengthTooLarge signals a declared size violation under
+// content-length framing specifically.
+func readStdioMessage(reader *bufio.Reader) (string, error) {
+	if stdioFramingMode == stdioFramingContentLength {
+		return readContentLengthFramedMessage(reader)
+	}
+	line, err := reader.ReadString('\n')
+	return strings.TrimSpace(line), err
+}
+
+// readContentLengthFramedMessage reads one LSP-style framed message: a run
+// of "Name: value" header lines (only Content-Length is inspected; any
+// other header, e.g. Content-Type, is skipped) terminated by a blank line,
+// followed by exactly the declared number of body bytes.
+func readContentLengthFramedMessage(reader *bufio.Reader) (string, error) {
+	contentLength := -1
+
+	for {
+		headerLine, err := reader.ReadString('\n')
+		trimmed := strings.TrimRight(headerLine, "\r\n")
+		if trimmed == "" {
+			if err != nil {
+				return "", err
+			}
+			break
+		}
+
+		if name, value, ok := strings.Cut(trimmed, ":"); ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			n, convErr := strconv.Atoi(strings.TrimSpace(value))
+			if convErr == nil {
+				contentLength = n
+			}
+		}
+
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if contentLength < 0 {
+		return "", fmt.Errorf("content-length framing: message is missing a Content-Length header")
+	}
+	if maxRequestBytes > 0 && contentLength > maxRequestBytes {
+		return "", errContentLengthTooLarge
+	}
+
+	body := make([]byte, contentLength)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return "", fmt.Errorf("content-length framing: reading %d byte body: %w", contentLength, err)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func withTestStdioFraming(t *testing.T, mode string, bufferSize, maxBytes int) {
+	t.Helper()
+	origMode, origBuf, origMax := stdioFramingMode, stdioBufferSize, maxRequestBytes
+	stdioFramingMode, stdioBufferSize, maxRequestBytes = mode, bufferSize, maxBytes
+	t.Cleanup(func() {
+		stdioFramingMode, stdioBufferSize, maxRequestBytes = origMode, origBuf, origMax
+	})
+}
+
+// TestServeStdioTolerant_LargeSingleLineMessageIsNotTruncated sends a
+// ~1MB single-line tools/call request through the default line framing,
+// with a small bufio buffer, and asserts the full argument round-trips
+// intact: reader.ReadString('\n') has no bufio.Scanner-style token-size
+// ceiling, so nothing here should be dropped or truncated.
+func TestServeStdioTolerant_LargeSingleLineMessageIsNotTruncated(t *testing.T) {
+	withTestStdioFraming(t, stdioFramingLines, 4096, 4*1024*1024)
+
+	origStdin, origStdout := os.Stdin, os.Stdout
+	defer func() { os.Stdin, os.Stdout = origStdin, origStdout }()
+
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdin): %v", err)
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdout): %v", err)
+	}
+	os.Stdin, os.Stdout = stdinR, stdoutW
+
+	srv := newTestServer()
+	done := make(chan error, 1)
+	go func() { done <- serveStdioTolerant(context.Background(), srv) }()
+
+	padding := strings.Repeat("x", 1024*1024)
+	writeLine(t, stdinW, fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`))
+	writeLine(t, stdinW, fmt.Sprintf(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"calculate","arguments":{"operation":"add","x":1,"y":2,"padding":%q}}}`, padding))
+
+	scanner := bufio.NewScanner(stdoutR)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	if !scanner.Scan() {
+		t.Fatalf("expected an initialize response, scan error: %v", scanner.Err())
+	}
+	if !scanner.Scan() {
+		t.Fatalf("expected a tools/call response, scan error: %v", scanner.Err())
+	}
+	var resp map[string]any
+	if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshaling tools/call response: %v", err)
+	}
+	if _, ok := resp["result"]; !ok {
+		t.Fatalf("expected a result in the tools/call response, got %v", resp)
+	}
+
+	stdinW.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serveStdioTolerant returned %v after EOF, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("serveStdioTolerant did not return after stdin closed")
+	}
+	stdoutW.Close()
+}
+
+// TestServeStdioTolerant_ContentLengthFramingHandlesMultiLineBody exercises
+// --stdio-framing=content-length against a pretty-printed (multi-line)
+// initialize request, which line framing has no way to read correctly.
+func TestServeStdioTolerant_ContentLengthFramingHandlesMultiLineBody(t *testing.T) {
+	withTestStdioFraming(t, stdioFramingContentLength, 4096, 4*1024*1024)
+
+	origStdin, origStdout := os.Stdin, os.Stdout
+	defer func() { os.Stdin, os.Stdout = origStdin, origStdout }()
+
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdin): %v", err)
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdout): %v", err)
+	}
+	os.Stdin, os.Stdout = stdinR, stdoutW
+
+	srv := newTestServer()
+	done := make(chan error, 1)
+	go func() { done <- serveStdioTolerant(context.Background(), srv) }()
+
+	body := "{\n  \"jsonrpc\": \"2.0\",\n  \"id\": 1,\n  \"method\": \"initialize\",\n  \"params\": {\n    \"protocolVersion\": \"2024-11-05\",\n    \"capabilities\": {},\n    \"clientInfo\": {\"name\": \"test\", \"version\": \"1.0\"}\n  }\n}"
+	writeContentLengthFramed(t, stdinW, body)
+
+	scanner := bufio.NewScanner(stdoutR)
+	if !scanner.Scan() {
+		t.Fatalf("expected an initialize response, scan error: %v", scanner.Err())
+	}
+	var resp map[string]any
+	if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshaling initialize response: %v", err)
+	}
+	if _, ok := resp["result"]; !ok {
+		t.Fatalf("expected a result in the initialize response, got %v", resp)
+	}
+
+	stdinW.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serveStdioTolerant returned %v after EOF, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("serveStdioTolerant did not return after stdin closed")
+	}
+	stdoutW.Close()
+}
+
+// TestServeStdioTolerant_ContentLengthOverMaxRequestBytesIsRejected checks
+// that a declared Content-Length over maxRequestBytes gets the same
+// requestTooLargeCode response as an over-limit line, and the session
+// stays open afterward.
+func TestServeStdioTolerant_ContentLengthOverMaxRequestBytesIsRejected(t *testing.T) {
+	withTestStdioFraming(t, stdioFramingContentLength, 4096, 16)
+
+	origStdin, origStdout := os.Stdin, os.Stdout
+	defer func() { os.Stdin, os.Stdout = origStdin, origStdout }()
+
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdin): %v", err)
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdout): %v", err)
+	}
+	os.Stdin, os.Stdout = stdinR, stdoutW
+
+	srv := newTestServer()
+	done := make(chan error, 1)
+	go func() { done <- serveStdioTolerant(context.Background(), srv) }()
+
+	writeContentLengthFramed(t, stdinW, `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+
+	scanner := bufio.NewScanner(stdoutR)
+	if !scanner.Scan() {
+		t.Fatalf("expected a too-large response, scan error: %v", scanner.Err())
+	}
+	var resp stdioErrorResponse
+	if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshaling %q: %v", scanner.Text(), err)
+	}
+	if resp.Error.Code != requestTooLargeCode {
+		t.Errorf("code = %d, want %d", resp.Error.Code, requestTooLargeCode)
+	}
+
+	stdinW.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serveStdioTolerant returned %v after EOF, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("serveStdioTolerant did not return after stdin closed")
+	}
+	stdoutW.Close()
+}
+
+func writeContentLengthFramed(t *testing.T, w *os.File, body string) {
+	t.Helper()
+	framed := fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(body), body)
+	if _, err := w.WriteString(framed); err != nil {
+		t.Fatalf("writing content-length framed message: %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestServeStdioTolerant_RecoversFromGarbageAndHandshakes pipes a line of
+// garbage bytes, a bare JSON string, and then a valid initialize request
+// through serveStdioTolerant, asserting it reports -32700 and -32600 for
+// the first two and still completes the handshake for the third, instead
+// of exiting after the framing errors.
+func TestServeStdioTolerant_RecoversFromGarbageAndHandshakes(t *testing.T) {
+	origStdin, origStdout := os.Stdin, os.Stdout
+	defer func() { os.Stdin, os.Stdout = origStdin, origStdout }()
+
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdin): %v", err)
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe (stdout): %v", err)
+	}
+	os.Stdin, os.Stdout = stdinR, stdoutW
+
+	srv := newTestServer()
+	done := make(chan error, 1)
+	go func() { done <- serveStdioTolerant(context.Background(), srv) }()
+
+	writeLine(t, stdinW, "{not valid json")
+	writeLine(t, stdinW, `"just a string"`)
+	writeLine(t, stdinW, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`)
+
+	scanner := bufio.NewScanner(stdoutR)
+
+	assertErrorCode(t, scanner, -32700)
+	assertErrorCode(t, scanner, -32600)
+
+	if !scanner.Scan() {
+		t.Fatalf("expected an initialize response, scan error: %v", scanner.Err())
+	}
+	var resp map[string]any
+	if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshaling initialize response: %v", err)
+	}
+	if _, ok := resp["result"]; !ok {
+		t.Fatalf("expected a result in the initialize response, got %v", resp)
+	}
+
+	stdinW.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serveStdioTolerant returned %v after EOF, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("serveStdioTolerant did not return after stdin closed")
+	}
+
+	stdoutW.Close()
+}
+
+func writeLine(t *testing.T, w *os.File, line string) {
+	t.Helper()
+	if _, err := w.WriteString(line + "\n"); err != nil {
+		t.Fatalf("writing %q: %v", line, err)
+	}
+}
+
+func assertErrorCode(t *testing.T, scanner *bufio.Scanner, wantCode int) {
+	t.Helper()
+	if !scanner.Scan() {
+		t.Fatalf("expected a response line, scan error: %v", scanner.Err())
+	}
+	var resp stdioErrorResponse
+	if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshaling %q: %v", scanner.Text(), err)
+	}
+	if resp.Error.Code != wantCode {
+		t.Errorf("code = %d, want %d (line: %q)", resp.Error.Code, wantCode, scanner.Text())
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// strictModeEnabled gates requireStrictProtocol and serveStdioTolerant's
+// equivalent stdio check. Default behavior stays lenient (answers whatever
+// comes in, in whatever order) since that's what this fixture has always
+// done and most clients never exercise the initialize-ordering edge case;
+// main wires this to --strict for the clients that do. See
+// checkProtocolPreconditions for the actual rule.
+var strictModeEnabled = false
+
+// knownProtocolVersions is the whitelist checkProtocolPreconditions
+// validates an incoming initialize request's protocolVersion against, and
+// the same list main validates the --protocol-version flag value against
+// (via isKnownProtocolVersion) at startup.
+var knownProtocolVersions = []string{"2024-11-05", "2025-03-26", "2025-06-18"}
+
+// sessionInitialized tracks, per session, whether notifications/initialized
+// has been observed yet. Follows the same mutex-guarded map pattern as
+// subscriptions.go and sessionstore.go.
+var sessionInitialized = struct {
+	mu   sync.Mutex
+	byID map[string]bool
+}{byID: map[string]bool{}}
+
+func markSessionInitialized(sessionID string) {
+	sessionInitialized.mu.Lock()
+	defer sessionInitialized.mu.Unlock()
+	sessionInitialized.byID[sessionID] = true
+}
+
+func isSessionInitialized(sessionID string) bool {
+	sessionInitialized.mu.Lock()
+	defer sessionInitialized.mu.Unlock()
+	return sessionInitialized.byID[sessionID]
+}
+
+func forgetSessionInitialized(sessionID string) {
+	sessionInitialized.mu.Lock()
+	defer sessionInitialized.mu.Unlock()
+	delete(sessionInitialized.byID, sessionID)
+}
+
+// registerStrictModeHooks marks a session initialized once it sends
+// notifications/initialized, and forgets it on disconnect so a reused
+// session id (unlikely, but not this fixture's business to assume away)
+// starts the gate over.
+func registerStrictModeHooks(hooks *server.Hooks) {
+	hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+		if method == "notifications/initialized" {
+			markSessionInitialized(sessionOrStdioID(ctx))
+		}
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		forgetSessionInitialized(session.SessionID())
+	})
+}
+
+// jsonrpcEnvelope is enough of a JSON-RPC request's shape to classify it
+// before it's handed to s.HandleMessage. It's deliberately not
+// mcp.JSONRPCRequest: we need this to decode even for methods/shapes that
+// type wouldn't, since the whole point is inspecting a message before
+// trusting it's well-formed.
+type jsonrpcEnvelope struct {
+	ID     any    `json:"id"`
+	Method string `json:"method"`
+	Params struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	} `json:"params"`
+}
+
+// checkProtocolPreconditions is the single rule both serveStdioTolerant and
+// requireStrictProtocol enforce before a message reaches s.HandleMessage:
+//
+//   - an initialize request naming a protocolVersion outside
+//     knownProtocolVersions is rejected with the supported list in the
+//     error's data, regardless of strictModeEnabled (negotiating a version
+//     the server doesn't speak is never something to answer leniently);
+//   - a method versionGatedMethods ties to a capability that
+//     pinnedProtocolVersion doesn't list is rejected with method-not-found,
+//     regardless of strictModeEnabled;
+//   - a method capabilityGatedMethods ties to a disabled capabilitiesEnabled
+//     toggle (--no-tools/--no-resources/--no-prompts/--no-logging) is
+//     rejected with method-not-found, regardless of strictModeEnabled;
+//   - with strictModeEnabled, anything other than initialize, ping, or
+//     notifications/initialized arriving before the session has sent
+//     notifications/initialized is rejected with "server not initialized".
+//
+// Returns nil when the message should be let through unchanged.
+func checkProtocolPreconditions(sessionID string, env jsonrpcEnvelope) *stdioErrorResponse {
+	if env.Method == "initialize" {
+		if env.Params.ProtocolVersion != "" && !isKnownProtocolVersion(env.Params.ProtocolVersion) {
+			return &stdioErrorResponse{
+				JSONRPC: "2.0",
+				ID:      env.ID,
+				Error: stdioErrorBody{
+					Code:    mcp.INVALID_PARAMS,
+					Message: fmt.Sprintf("unsupported protocolVersion %q, supported: %v", env.Params.ProtocolVersion, knownProtocolVersions),
+				},
+			}
+		}
+		return nil
+	}
+
+	if !methodAvailableAtPinnedVersion(env.Method) {
+		return &stdioErrorResponse{
+			JSONRPC: "2.0",
+			ID:      env.ID,
+			Error:   stdioErrorBody{Code: mcp.METHOD_NOT_FOUND, Message: fmt.Sprintf("method %q not available at protocol version %q", env.Method, pinnedProtocolVersion)},
+		}
+	}
+
+	if !methodAvailableForEnabledCapabilities(env.Method) {
+		return &stdioErrorResponse{
+			JSONRPC: "2.0",
+			ID:      env.ID,
+			Error:   stdioErrorBody{Code: mcp.METHOD_NOT_FOUND, Message: fmt.Sprintf("method %q not found: its capability is disabled (%s)", env.Method, effectiveCapabilitySet())},
+		}
+	}
+
+	if !strictModeEnabled {
+		return nil
+	}
+	if env.Method == "ping" || env.Method == "notifications/initialized" {
+		return nil
+	}
+	if isSessionInitialized(sessionID) {
+		return nil
+	}
+	return &stdioErrorResponse{
+		JSONRPC: "2.0",
+		ID:      env.ID,
+		Error:   stdioErrorBody{Code: notInitializedCode, Message: "server not initialized"},
+	}
+}
+
+// notInitializedCode is the JSON-RPC error code for a request arriving
+// before the client has completed the initialize handshake.
+const notInitializedCode = -32002
+
+func isKnownProtocolVersion(v string) bool {
+	for _, known := range knownProtocolVersions {
+		if known == v {
+			return true
+		}
+	}
+	return false
+}
+
+// requireStrictProtocol wraps next for the sse/http/both transports,
+// peeking each POSTed JSON-RPC body and applying checkProtocolPreconditions
+// before it ever reaches the mcp-go handler, since server.Hooks' Before/
+// After callbacks can't block dispatch (see newTracingMiddleware's doc
+// comment for why). GET requests (the SSE event stream, health checks)
+// pass straight through — there's no JSON-RPC body to inspect.
+func requireStrictProtocol(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var env jsonrpcEnvelope
+		if err := json.Unmarshal(body, &env); err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		sessionID := r.Header.Get("Mcp-Session-Id")
+		if violation := checkProtocolPreconditions(sessionID, env); violation != nil {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(violation)
+			return
+		}
+
+		if env.Method == "initialize" && pinnedProtocolVersion != "" {
+			rec := &initializeResponseRewriter{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+			rec.flush()
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// initializeResponseRewriter buffers an initialize response so
+// rewriteInitializeResponse can run on the whole body before anything
+// reaches the real client, the same way serveStdioTolerant rewrites its
+// own initialize response before writing it to stdout.
+type initializeResponseRewriter struct {
+	http.ResponseWriter
+	buf bytes.Buffer
+}
+
+func (r *initializeResponseRewriter) Write(b []byte) (int, error) {
+	return r.buf.Write(b)
+}
+
+func (r *initializeResponseRewriter) flush() {
+	rewritten := rewriteInitializeResponse(r.buf.Bytes())
+	_, _ = r.ResponseWriter.Write(rewritten)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCheckProtocolPreconditions_RejectsEarlyRequestInStrictMode(t *testing.T) {
+	strictModeEnabled = true
+	defer func() { strictModeEnabled = false }()
+	forgetSessionInitialized("sess-early")
+
+	env := jsonrpcEnvelope{ID: float64(1), Method: "tools/list"}
+	violation := checkProtocolPreconditions("sess-early", env)
+	if violation == nil {
+		t.Fatal("expected a violation for tools/list before notifications/initialized")
+	}
+	if violation.Error.Code != notInitializedCode {
+		t.Errorf("code = %d, want %d", violation.Error.Code, notInitializedCode)
+	}
+}
+
+func TestCheckProtocolPreconditions_AcceptsCorrectOrdering(t *testing.T) {
+	strictModeEnabled = true
+	defer func() { strictModeEnabled = false }()
+	forgetSessionInitialized("sess-ordered")
+
+	if v := checkProtocolPreconditions("sess-ordered", jsonrpcEnvelope{Method: "initialize"}); v != nil {
+		t.Fatalf("initialize rejected: %+v", v)
+	}
+	if v := checkProtocolPreconditions("sess-ordered", jsonrpcEnvelope{Method: "notifications/initialized"}); v != nil {
+		t.Fatalf("notifications/initialized rejected: %+v", v)
+	}
+	markSessionInitialized("sess-ordered")
+	defer forgetSessionInitialized("sess-ordered")
+
+	if v := checkProtocolPreconditions("sess-ordered", jsonrpcEnvelope{Method: "tools/list"}); v != nil {
+		t.Fatalf("tools/list rejected after correct ordering: %+v", v)
+	}
+}
+
+func TestCheckProtocolPreconditions_LenientByDefault(t *testing.T) {
+	if strictModeEnabled {
+		t.Fatal("expected strictModeEnabled to default to false")
+	}
+	if v := checkProtocolPreconditions("sess-lenient", jsonrpcEnvelope{Method: "tools/list"}); v != nil {
+		t.Fatalf("expected no violation when strict mode is off, got %+v", v)
+	}
+}
+
+func TestCheckProtocolPreconditions_UnsupportedProtocolVersionRejectedRegardlessOfStrictMode(t *testing.T) {
+	env := jsonrpcEnvelope{ID: float64(7), Method: "initialize"}
+	env.Params.ProtocolVersion = "1999-01-01"
+
+	violation := checkProtocolPreconditions("sess-version", env)
+	if violation == nil {
+		t.Fatal("expected a violation for an unsupported protocolVersion")
+	}
+	if violation.Error.Code != mcp.INVALID_PARAMS {
+		t.Errorf("code = %d, want INVALID_PARAMS", violation.Error.Code)
+	}
+}
+
+func TestRequireStrictProtocol_RejectsBeforeReachingTheInnerHandler(t *testing.T) {
+	strictModeEnabled = true
+	defer func() { strictModeEnabled = false }()
+	forgetSessionInitialized("sess-http")
+
+	reached := false
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { reached = true })
+
+	body, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": "tools/list"})
+	req := httptest.NewRequest(http.MethodPost, "/mcp", bytes.NewReader(body))
+	req.Header.Set("Mcp-Session-Id", "sess-http")
+	rec := httptest.NewRecorder()
+
+	requireStrictProtocol(inner).ServeHTTP(rec, req)
+
+	if reached {
+		t.Error("expected the inner handler not to be reached")
+	}
+
+	var resp stdioErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshaling response: %v", err)
+	}
+	if resp.Error.Code != notInitializedCode {
+		t.Errorf("code = %d, want %d", resp.Error.Code, notInitializedCode)
+	}
+}
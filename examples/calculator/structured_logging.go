@@ -0,0 +1,23 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+)
+
+// structuredLogger backs loggingMiddleware. It defaults to a text handler
+// on stderr; main swaps it for a JSON handler when --log-format=json is
+// requested, so operators who pipe this fixture's output into a log
+// aggregator aren't stuck parsing log.Printf lines.
+var structuredLogger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+// newSlogHandler builds the handler main installs into structuredLogger
+// for the requested --log-format. An unrecognized format falls back to
+// text rather than erroring, since a typo'd flag shouldn't be fatal for a
+// test fixture.
+func newSlogHandler(format string) slog.Handler {
+	if format == "json" {
+		return slog.NewJSONHandler(os.Stderr, nil)
+	}
+	return slog.NewTextHandler(os.Stderr, nil)
+}
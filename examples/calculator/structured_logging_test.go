@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestNewSlogHandler_JSONProducesParseableOutput(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	origStderr := os.Stderr
+	os.Stderr = w
+	logger := slog.New(newSlogHandler("json"))
+	os.Stderr = origStderr
+
+	logger.Info("test message", slog.String("tool", "calculate"))
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("reading handler output: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatalf("expected valid JSON, got %q: %v", out, err)
+	}
+	if decoded["tool"] != "calculate" {
+		t.Errorf("tool = %v, want \"calculate\"", decoded["tool"])
+	}
+}
+
+func TestNewSlogHandler_UnrecognizedFormatFallsBackToText(t *testing.T) {
+	if _, ok := newSlogHandler("bogus").(*slog.TextHandler); !ok {
+		t.Errorf("newSlogHandler(%q) = %T, want *slog.TextHandler", "bogus", newSlogHandler("bogus"))
+	}
+	if _, ok := newSlogHandler("json").(*slog.JSONHandler); !ok {
+		t.Errorf("newSlogHandler(%q) = %T, want *slog.JSONHandler", "json", newSlogHandler("json"))
+	}
+}
+
+func TestLoggingMiddleware_LogsSuccessAtInfo(t *testing.T) {
+	old := structuredLogger
+	defer func() { structuredLogger = old }()
+
+	var buf bytes.Buffer
+	structuredLogger = slog.New(slog.NewJSONHandler(&buf, nil))
+
+	handler := loggingMiddleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "calculate"
+	if _, err := handler(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("expected valid JSON, got %q: %v", buf.String(), err)
+	}
+	if decoded["level"] != "INFO" {
+		t.Errorf("level = %v, want INFO", decoded["level"])
+	}
+	if decoded["tool"] != "calculate" {
+		t.Errorf("tool = %v, want \"calculate\"", decoded["tool"])
+	}
+}
+
+func TestLoggingMiddleware_LogsFailureAtError(t *testing.T) {
+	old := structuredLogger
+	defer func() { structuredLogger = old }()
+
+	var buf bytes.Buffer
+	structuredLogger = slog.New(slog.NewJSONHandler(&buf, nil))
+
+	boom := invalidParamsf("boom")
+	handler := loggingMiddleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, boom
+	})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "calculate"
+	if _, err := handler(context.Background(), req); err != boom {
+		t.Fatalf("expected the original error to pass through, got %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("expected valid JSON, got %q: %v", buf.String(), err)
+	}
+	if decoded["level"] != "ERROR" {
+		t.Errorf("level = %v, want ERROR", decoded["level"])
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleCalculateTool_StructuredContentMatchesOutputSchema(t *testing.T) {
+	res := callCalculate(t, "multiply", 4, 3)
+	if res.IsError {
+		t.Fatalf("unexpected error result")
+	}
+	if len(res.Content) != 2 {
+		t.Fatalf("len(Content) = %d, want 2 (text result, then structured JSON)", len(res.Content))
+	}
+
+	text, ok := res.Content[1].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("Content[1] = %T, want mcp.TextContent", res.Content[1])
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(text.Text), &decoded); err != nil {
+		t.Fatalf("structured content did not decode as JSON: %v", err)
+	}
+
+	for _, field := range []string{"operation", "x", "y", "result"} {
+		if _, ok := decoded[field]; !ok {
+			t.Errorf("StructuredContent missing field %q", field)
+		}
+	}
+
+	if decoded["operation"] != "multiply" {
+		t.Errorf("operation = %v, want %q", decoded["operation"], "multiply")
+	}
+	if decoded["result"] != 12.0 {
+		t.Errorf("result = %v, want %v", decoded["result"], 12.0)
+	}
+}
+
+func TestHandleCalculateTool_DivideByZeroHasNoStructuredContent(t *testing.T) {
+	res := callCalculate(t, "divide", 1, 0)
+	if !res.IsError {
+		t.Fatalf("expected divide by zero to return an error result")
+	}
+	if len(res.Content) != 1 {
+		t.Errorf("len(Content) = %d, want 1 (no structured content on an isError result)", len(res.Content))
+	}
+}
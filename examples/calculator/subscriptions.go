@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// subscriptionsEnabled gates whether resources/subscribe and
+// resources/unsubscribe actually track anything, and whether main
+// advertises the subscribe sub-capability. main wires it to
+// --no-subscriptions.
+var subscriptionsEnabled = true
+
+// registerSubscriptionHooks wires resources/subscribe and
+// resources/unsubscribe into our own session/uri tracking, and cleans a
+// session's subscriptions up when it disconnects so we don't leak entries
+// (or send notifications into the void) for SSE clients that went away.
+func registerSubscriptionHooks(hooks *server.Hooks) {
+	hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+		if !subscriptionsEnabled {
+			return
+		}
+
+		sessionID := sessionIDFromContext(ctx)
+		if sessionID == "" {
+			return
+		}
+
+		params, ok := message.(map[string]any)
+		if !ok {
+			return
+		}
+		uri, _ := params["uri"].(string)
+		if uri == "" {
+			return
+		}
+
+		switch method {
+		case "resources/subscribe":
+			subscribe(sessionID, uri)
+		case "resources/unsubscribe":
+			unsubscribe(sessionID, uri)
+		}
+	})
+
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		unsubscribeAll(session.SessionID())
+	})
+}
+
+func sessionIDFromContext(ctx context.Context) string {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return ""
+	}
+	return session.SessionID()
+}
+
+// subscriptions tracks which sessions are subscribed to which resource
+// URIs, so a calculation can fan out notifications/resources/updated only
+// to sessions that asked for them.
+var subscriptions = struct {
+	mu    sync.Mutex
+	byURI map[string]map[string]struct{} // uri -> set of session IDs
+}{byURI: map[string]map[string]struct{}{}}
+
+func subscribe(sessionID, uri string) {
+	subscriptions.mu.Lock()
+	defer subscriptions.mu.Unlock()
+
+	if subscriptions.byURI[uri] == nil {
+		subscriptions.byURI[uri] = map[string]struct{}{}
+	}
+	subscriptions.byURI[uri][sessionID] = struct{}{}
+}
+
+func unsubscribe(sessionID, uri string) {
+	subscriptions.mu.Lock()
+	defer subscriptions.mu.Unlock()
+
+	delete(subscriptions.byURI[uri], sessionID)
+}
+
+// unsubscribeAll drops every subscription held by sessionID, called when an
+// SSE session disconnects so we don't keep notifying (or leak references
+// to) a session that's gone.
+func unsubscribeAll(sessionID string) {
+	subscriptions.mu.Lock()
+	defer subscriptions.mu.Unlock()
+
+	for uri, sessions := range subscriptions.byURI {
+		delete(sessions, sessionID)
+		if len(sessions) == 0 {
+			delete(subscriptions.byURI, uri)
+		}
+	}
+}
+
+func subscribersOf(uri string) []string {
+	subscriptions.mu.Lock()
+	defer subscriptions.mu.Unlock()
+
+	ids := make([]string, 0, len(subscriptions.byURI[uri]))
+	for id := range subscriptions.byURI[uri] {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// notifyResourceUpdated sends notifications/resources/updated to every
+// session subscribed to uri, via sessionRegistry since a subscriber may
+// be a different session than the one whose request triggered the
+// mutation. Handlers that mutate a subscribable resource (e.g.
+// handle_calculate_tool updating calc://history/latest) call this after
+// the mutation has landed.
+func notifyResourceUpdated(ctx context.Context, srv *server.MCPServer, uri string) {
+	for _, sessionID := range subscribersOf(uri) {
+		sendNotificationToSession(sessionID, "notifications/resources/updated", map[string]any{
+			"uri": uri,
+		})
+	}
+}
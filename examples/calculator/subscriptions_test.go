@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// fakeClientSession is a minimal server.ClientSession for exercising
+// notifyResourceUpdated without a real transport, so the test can assert on
+// exactly what landed on the session's notification channel.
+type fakeClientSession struct {
+	sessionID   string
+	notifChan   chan mcp.JSONRPCNotification
+	initialized bool
+}
+
+func (f *fakeClientSession) SessionID() string { return f.sessionID }
+func (f *fakeClientSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return f.notifChan
+}
+func (f *fakeClientSession) Initialize()          { f.initialized = true }
+func (f *fakeClientSession) Initialized() bool    { return f.initialized }
+
+func TestSubscribeAndUnsubscribe(t *testing.T) {
+	subscribe("session-a", "calc://history/latest")
+	subscribe("session-b", "calc://history/latest")
+
+	ids := subscribersOf("calc://history/latest")
+	if len(ids) != 2 {
+		t.Fatalf("expected 2 subscribers, got %d", len(ids))
+	}
+
+	unsubscribe("session-a", "calc://history/latest")
+	ids = subscribersOf("calc://history/latest")
+	if len(ids) != 1 || ids[0] != "session-b" {
+		t.Fatalf("expected only session-b to remain, got %v", ids)
+	}
+}
+
+func TestUnsubscribeAll(t *testing.T) {
+	subscribe("session-c", "calc://history/latest")
+	subscribe("session-c", "config:///calculator")
+
+	unsubscribeAll("session-c")
+
+	if len(subscribersOf("calc://history/latest")) != 0 {
+		t.Error("expected session-c to be removed from calc://history/latest")
+	}
+	if len(subscribersOf("config:///calculator")) != 0 {
+		t.Error("expected session-c to be removed from config:///calculator")
+	}
+}
+
+func TestNotifyResourceUpdated_DeliversExactlyOneNotificationToASubscriber(t *testing.T) {
+	srv := newTestServer()
+	session := &fakeClientSession{sessionID: "sess-notify", notifChan: make(chan mcp.JSONRPCNotification, 10)}
+	session.Initialize()
+	if err := srv.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("RegisterSession: %v", err)
+	}
+
+	subscribe(session.SessionID(), "calc://history/latest")
+	defer unsubscribeAll(session.SessionID())
+
+	notifyResourceUpdated(context.Background(), srv, "calc://history/latest")
+
+	select {
+	case notif := <-session.notifChan:
+		if notif.Method != "notifications/resources/updated" {
+			t.Errorf("Method = %q, want notifications/resources/updated", notif.Method)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a notification within 1s")
+	}
+
+	select {
+	case extra := <-session.notifChan:
+		t.Fatalf("expected exactly one notification, got a second: %+v", extra)
+	default:
+	}
+}
+
+func TestNotifyResourceUpdated_SkipsSessionsNotSubscribed(t *testing.T) {
+	srv := newTestServer()
+	session := &fakeClientSession{sessionID: "sess-unsubscribed", notifChan: make(chan mcp.JSONRPCNotification, 10)}
+	session.Initialize()
+	if err := srv.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("RegisterSession: %v", err)
+	}
+
+	notifyResourceUpdated(context.Background(), srv, "calc://history/latest")
+
+	select {
+	case notif := <-session.notifChan:
+		t.Fatalf("expected no notification for an unsubscribed session, got %+v", notif)
+	default:
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const dbRowsTemplate = "db://tables/{table}/rows/{id}"
+
+// registerResourceTemplates wires up the calculator's parameterized
+// resources. dbRowsTemplate is a toy example exercising template expansion
+// and matching; calc://history/{index} (registered in history.go's
+// template counterpart) is the one the Anubis client test suite actually
+// drives.
+func registerResourceTemplates(s *server.MCPServer) {
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate(dbRowsTemplate, "DB row lookup", mcp.WithTemplateMIMEType("application/json")),
+		handleDBRowsTemplate,
+	)
+}
+
+func handleDBRowsTemplate(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	vars, ok := matchURITemplate(dbRowsTemplate, request.Params.URI)
+	if !ok {
+		return nil, fmt.Errorf("URI %q does not match template %q", request.Params.URI, dbRowsTemplate)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     fmt.Sprintf(`{"table":%q,"id":%q}`, vars["table"], vars["id"]),
+		},
+	}, nil
+}
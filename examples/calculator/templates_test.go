@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestMatchURITemplate(t *testing.T) {
+	vars, ok := matchURITemplate(dbRowsTemplate, "db://tables/users/rows/42")
+	if !ok {
+		t.Fatal("expected the template to match")
+	}
+	if vars["table"] != "users" || vars["id"] != "42" {
+		t.Errorf("vars = %v, want {table: users, id: 42}", vars)
+	}
+
+	if _, ok := matchURITemplate(dbRowsTemplate, "db://tables/users"); ok {
+		t.Error("expected a URI with too few segments not to match")
+	}
+}
+
+func TestHandleDBRowsTemplate(t *testing.T) {
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = "db://tables/users/rows/42"
+
+	contents, err := handleDBRowsTemplate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text := contents[0].(mcp.TextResourceContents)
+	if text.Text != `{"table":"users","id":"42"}` {
+		t.Errorf("text = %q", text.Text)
+	}
+}
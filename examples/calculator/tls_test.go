@@ -0,0 +1,131 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSelfSignedCA generates a throwaway self-signed CA certificate and
+// writes its PEM encoding to a file under dir, returning the path. It
+// exists purely so the mTLS branch of tlsConfig has a real PEM to parse,
+// without shipping a fixture cert into the repo.
+func writeSelfSignedCA(t *testing.T, dir string) string {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating CA key: %v", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "calculator-fixture-ca"},
+		NotBefore:             time.Unix(0, 0),
+		NotAfter:              time.Unix(0, 0).Add(100 * 365 * 24 * time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("creating CA certificate: %v", err)
+	}
+
+	path := filepath.Join(dir, "ca.pem")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("creating %s: %v", path, err)
+	}
+	defer f.Close()
+
+	if err := pem.Encode(f, &pem.Block{Type: "CERTIFICATE", Bytes: der}); err != nil {
+		t.Fatalf("encoding CA certificate: %v", err)
+	}
+
+	return path
+}
+
+func TestValidateTLSFlags_BothEmptyOrBothSetIsFine(t *testing.T) {
+	if err := validateTLSFlags("", ""); err != nil {
+		t.Errorf("both empty: unexpected error: %v", err)
+	}
+	if err := validateTLSFlags("cert.pem", "key.pem"); err != nil {
+		t.Errorf("both set: unexpected error: %v", err)
+	}
+}
+
+func TestValidateTLSFlags_OnlyOneSetFailsFast(t *testing.T) {
+	if err := validateTLSFlags("cert.pem", ""); err == nil {
+		t.Error("expected an error when only -cert-file is set")
+	}
+	if err := validateTLSFlags("", "key.pem"); err == nil {
+		t.Error("expected an error when only -key-file is set")
+	}
+}
+
+func TestTLSConfig_NoCertOrKeyReturnsNil(t *testing.T) {
+	cfg, err := tlsConfig(transportConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg != nil {
+		t.Errorf("cfg = %v, want nil", cfg)
+	}
+}
+
+func TestTLSConfig_CertAndKeyWithoutClientCAHasNoClientAuth(t *testing.T) {
+	cfg, err := tlsConfig(transportConfig{CertFile: "cert.pem", KeyFile: "key.pem"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg == nil {
+		t.Fatal("cfg = nil, want a non-nil *tls.Config")
+	}
+	if cfg.ClientAuth != 0 {
+		t.Errorf("ClientAuth = %v, want unset (no mutual TLS)", cfg.ClientAuth)
+	}
+}
+
+func TestTLSConfig_ClientCARequiresAndVerifiesClientCerts(t *testing.T) {
+	caPath := writeSelfSignedCA(t, t.TempDir())
+
+	cfg, err := tlsConfig(transportConfig{CertFile: "cert.pem", KeyFile: "key.pem", ClientCAFile: caPath})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ClientCAs == nil {
+		t.Fatal("ClientCAs = nil, want the parsed CA pool")
+	}
+	if cfg.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Fatalf("ClientAuth = %v, want RequireAndVerifyClientCert", cfg.ClientAuth)
+	}
+}
+
+func TestTLSConfig_MissingClientCAFileIsAnError(t *testing.T) {
+	_, err := tlsConfig(transportConfig{CertFile: "cert.pem", KeyFile: "key.pem", ClientCAFile: "/nonexistent/ca.pem"})
+	if err == nil {
+		t.Fatal("expected an error for an unreadable client CA file")
+	}
+}
+
+func TestTLSConfig_UnparseableClientCAFileIsAnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("writing bogus CA file: %v", err)
+	}
+
+	_, err := tlsConfig(transportConfig{CertFile: "cert.pem", KeyFile: "key.pem", ClientCAFile: path})
+	if err == nil {
+		t.Fatal("expected an error for a CA file with no parseable certificates")
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerSlowAddTool wires up slow_add, used to exercise progress
+// notifications and cancellation handling in the Anubis client.
+func registerSlowAddTool(s *server.MCPServer) {
+	tool := mcp.NewTool("slow_add",
+		mcp.WithDescription("Add two numbers slowly, reporting progress along the way"),
+		mcp.WithNumber("x", mcp.Required()),
+		mcp.WithNumber("y", mcp.Required()),
+		mcp.WithNumber("duration_ms",
+			mcp.Description("Total time to spend before returning, spread over 10 increments"),
+			mcp.Min(0),
+			mcp.Max(60000),
+			mcp.MultipleOf(100),
+			mcp.DefaultNumber(1000),
+		),
+		mcp.WithString("label",
+			mcp.Description("Optional tag included in the server's progress log lines"),
+			mcp.MinLength(0),
+			mcp.MaxLength(32),
+			mcp.Pattern("^[a-zA-Z0-9 _-]*$"),
+			mcp.DefaultString(""),
+		),
+		mcp.WithBoolean("verbose",
+			mcp.Description("Log every increment, not just the final one"),
+			mcp.DefaultBool(false),
+		),
+	)
+	addToolWithMiddleware(s, tool, handleSlowAddTool, newTimeoutMiddleware(toolTimeout), newDefaultsMiddleware(tool), recoverMiddleware, newConcurrencyMiddleware())
+}
+
+type slowAddArgs struct {
+	X          float64 `json:"x"`
+	Y          float64 `json:"y"`
+	DurationMs float64 `json:"duration_ms" mcp:"optional"`
+	Label      string  `json:"label" mcp:"optional"`
+	Verbose    bool    `json:"verbose" mcp:"optional"`
+}
+
+const slowAddIncrements = 10
+
+func handleSlowAddTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var args slowAddArgs
+	if err := bindArguments(request, &args); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	step := time.Duration(args.DurationMs/float64(slowAddIncrements)) * time.Millisecond
+	token, _ := progressTokenFromRequest(request)
+
+	for i := 1; i <= slowAddIncrements; i++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(step):
+		}
+		if args.Verbose && args.Label != "" {
+			log.Printf("slow_add[%s]: %d/%d", args.Label, i, slowAddIncrements)
+		}
+		reportProgress(ctx, token, float64(i), float64(slowAddIncrements))
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("%v", args.X+args.Y)), nil
+}
+
+// registerEchoTool wires up echo, used to verify a client's argument
+// encoding round-trips through the server unchanged.
+func registerEchoTool(s *server.MCPServer) {
+	tool := mcp.NewTool("echo",
+		mcp.WithDescription("Return the payload argument exactly as received, serialized back as JSON"),
+		withObject("payload", nil, required()),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Echo",
+			ReadOnlyHint:    true,
+			DestructiveHint: false,
+			IdempotentHint:  true,
+		}),
+	)
+	finalizeRequired(&tool)
+	addToolWithMiddleware(s, tool, handleEchoTool, recoverMiddleware)
+}
+
+// handleEchoTool re-serializes the payload argument with encoding/json.
+// Maps, slices, strings (including unicode/emoji/surrogate pairs), and
+// explicit nulls all round-trip unchanged. Numbers do not fully round-trip
+// byte-for-byte: the JSON-RPC layer decodes all numbers into float64
+// before handlers ever see them, so an integer-valued float like 3.0
+// arrives indistinguishable from 3 and is echoed back as "3", and
+// integers wider than 2^53 lose precision. Clients that need exact integer
+// fidelity should pass such values as strings.
+func handleEchoTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	payload, ok := request.Params.Arguments["payload"]
+	if !ok {
+		return mcp.NewToolResultError("missing required argument: payload"), nil
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(payload); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("payload is not serializable: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(bytes.TrimRight(buf.Bytes(), "\n"))), nil
+}
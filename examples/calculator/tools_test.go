@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleSlowAddTool_Completes(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"x": 2.0, "y": 3.0, "duration_ms": 20.0}
+
+	res, err := handleSlowAddTool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text := res.Content[0].(mcp.TextContent).Text
+	if text != "5" {
+		t.Errorf("result = %q, want %q", text, "5")
+	}
+}
+
+func TestHandleSlowAddTool_CancellationStopsPromptly(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"x": 1.0, "y": 1.0, "duration_ms": 5000.0}
+
+	done := make(chan struct{})
+	go func() {
+		handleSlowAddTool(ctx, req)
+		close(done)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("handleSlowAddTool did not return promptly after cancellation")
+	}
+}
+
+func TestHandleEchoTool_DeepNestedDocument(t *testing.T) {
+	payload := map[string]any{
+		"greeting": "héllo 👋 𝔘𝔫𝔦𝔠𝔬𝔡𝔢",
+		"nested": map[string]any{
+			"list": []any{1.0, "two", nil, true},
+		},
+		"big": 9007199254740993.0,
+		"huge_string": func() string {
+			s := make([]byte, 10000)
+			for i := range s {
+				s[i] = 'x'
+			}
+			return string(s)
+		}(),
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"payload": payload}
+
+	res, err := handleEchoTool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(res.Content[0].(mcp.TextContent).Text), &decoded); err != nil {
+		t.Fatalf("echoed payload did not round-trip as JSON: %v", err)
+	}
+
+	if decoded["greeting"] != payload["greeting"] {
+		t.Errorf("greeting = %q, want %q", decoded["greeting"], payload["greeting"])
+	}
+	if len(decoded["huge_string"].(string)) != 10000 {
+		t.Errorf("huge_string length = %d, want 10000", len(decoded["huge_string"].(string)))
+	}
+}
+
+func TestHandleEchoTool_IntegerValuedFloatLosesTrailingZero(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"payload": 3.0}
+
+	res, err := handleEchoTool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := res.Content[0].(mcp.TextContent).Text; got != "3" {
+		t.Errorf("echoed 3.0 as %q, want %q (documented float64 normalization)", got, "3")
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerProvider is where newTracingMiddleware and its resource/prompt
+// counterparts get their tracer from. It defaults to a no-op provider, so
+// tracing costs nothing until an operator wires a real one; this fixture
+// doesn't configure an exporter itself (that's deployment config, not
+// fixture behavior), it only exposes the extension point.
+var tracerProvider trace.TracerProvider = trace.NewNoopTracerProvider()
+
+const tracerName = "calculator"
+
+// newTracingMiddleware starts a span named "tool <name>" around a call,
+// recording the session id as an attribute and an error status when the
+// handler fails or returns a CallToolResult with IsError. Like
+// newValidationMiddleware, it's applied explicitly per tool at that tool's
+// own addToolWithMiddleware call site rather than globally; main wires it
+// onto calculate as the demonstration.
+//
+// Two pieces of the request this doesn't cover, and why:
+//
+//   - A span around the outer JSON-RPC request (independent of whether it
+//     reaches a tool/resource/prompt handler at all, e.g. tools/list)
+//     isn't created, because server.Hooks' Before/After callbacks are
+//     observer-only (see registerSubscriptionHooks, registerLatencyHook —
+//     none of them return a replacement context) and can't inject a span
+//     into the context the dispatcher later hands to the handler. A span
+//     started in a hook would never actually be this span's parent.
+//   - Continuing a trace from a W3C traceparent in the incoming request's
+//     _meta isn't wired: mcp.CallToolRequest's Params.Meta only exposes
+//     the fields this library defines (e.g. ProgressToken), and this
+//     fixture has no confirmed way to pull an arbitrary carrier map out of
+//     it.
+func newTracingMiddleware(name string) toolMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			tracer := tracerProvider.Tracer(tracerName)
+			ctx, span := tracer.Start(ctx, "tool "+name)
+			defer span.End()
+
+			span.SetAttributes(
+				attribute.String("mcp.tool.name", name),
+				attribute.String("mcp.session.id", sessionIDFromContext(ctx)),
+			)
+
+			result, err := next(ctx, request)
+			recordSpanOutcome(span, result, err)
+			return result, err
+		}
+	}
+}
+
+// newTracingResourceHandler and newTracingPromptHandler are
+// newTracingMiddleware's counterparts for resources/read and prompts/get,
+// which don't go through the toolMiddleware chain — the same split
+// recoverResourceHandler/recoverPromptHandler already draw.
+func newTracingResourceHandler(uri string, next func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error)) func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		tracer := tracerProvider.Tracer(tracerName)
+		ctx, span := tracer.Start(ctx, "resource "+uri)
+		defer span.End()
+
+		span.SetAttributes(
+			attribute.String("mcp.resource.uri", uri),
+			attribute.String("mcp.session.id", sessionIDFromContext(ctx)),
+		)
+
+		contents, err := next(ctx, request)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+		return contents, err
+	}
+}
+
+func newTracingPromptHandler(name string, next func(context.Context, mcp.GetPromptRequest) (*mcp.GetPromptResult, error)) func(context.Context, mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		tracer := tracerProvider.Tracer(tracerName)
+		ctx, span := tracer.Start(ctx, "prompt "+name)
+		defer span.End()
+
+		span.SetAttributes(
+			attribute.String("mcp.prompt.name", name),
+			attribute.String("mcp.session.id", sessionIDFromContext(ctx)),
+		)
+
+		result, err := next(ctx, request)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+		return result, err
+	}
+}
+
+func recordSpanOutcome(span trace.Span, result *mcp.CallToolResult, err error) {
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return
+	}
+	if result != nil && result.IsError {
+		span.SetStatus(codes.Error, "tool returned an error result")
+	}
+}
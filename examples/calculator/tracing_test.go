@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func withTestTracerProvider(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	orig := tracerProvider
+	tracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { tracerProvider = orig })
+	return exporter
+}
+
+func TestNewTracingMiddleware_RecordsASpanNamedForTheTool(t *testing.T) {
+	exporter := withTestTracerProvider(t)
+
+	ok := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("fine"), nil
+	}
+
+	if _, err := newTracingMiddleware("calculate")(ok)(context.Background(), mcp.CallToolRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Name != "tool calculate" {
+		t.Errorf("Name = %q, want %q", spans[0].Name, "tool calculate")
+	}
+}
+
+func TestNewTracingMiddleware_HandlerErrorSetsErrorStatus(t *testing.T) {
+	exporter := withTestTracerProvider(t)
+
+	failing := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, invalidParamsf("boom")
+	}
+
+	newTracingMiddleware("calculate")(failing)(context.Background(), mcp.CallToolRequest{})
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Status.Code != codes.Error {
+		t.Errorf("status code = %v, want Error", spans[0].Status.Code)
+	}
+}
+
+func TestNewTracingMiddleware_ToolResultIsErrorSetsErrorStatus(t *testing.T) {
+	exporter := withTestTracerProvider(t)
+
+	domainError := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return toolResultErrorf("domain failure"), nil
+	}
+
+	newTracingMiddleware("calculate")(domainError)(context.Background(), mcp.CallToolRequest{})
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Status.Code != codes.Error {
+		t.Errorf("status code = %v, want Error", spans[0].Status.Code)
+	}
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// transportConfig holds everything needed to stand up the SSE and
+// websocket transports. Host, port, and the SSE sub-paths are all
+// overridable so CI can run multiple fixture servers side by side without
+// port collisions.
+type transportConfig struct {
+	Host        string
+	Port        int
+	SSEPath     string
+	MessagePath string
+
+	// WebSocketPath is the path the websocket transport listens for
+	// connections on.
+	WebSocketPath string
+
+	// CertFile and KeyFile, if both set, serve TLS instead of plaintext.
+	CertFile string
+	KeyFile  string
+	// ClientCAFile, if set alongside CertFile/KeyFile, enables mutual TLS:
+	// client certificates are required and verified against this CA pool.
+	ClientCAFile string
+
+	// AuthToken, if non-empty, requires every request on the sse/http/both
+	// transports to carry a matching "Authorization: Bearer <AuthToken>"
+	// header. stdio is unaffected.
+	AuthToken string
+
+	// CORS, if non-nil, answers preflight requests and sets
+	// Access-Control-Allow-* headers on the sse/http/both transports.
+	CORS *corsConfig
+
+	// Origin, if non-nil, rejects sse/http/both requests whose Origin
+	// header is present but not allowed, guarding against DNS-rebinding
+	// attacks per the MCP spec's recommendation.
+	Origin *originConfig
+}
+
+// validateTLSFlags fails fast when exactly one of certFile/keyFile is
+// set: that combination can't serve TLS and can't be silently treated as
+// "no TLS requested" either, since it almost always means a typo'd or
+// missing flag rather than a deliberate choice. main calls this before
+// tlsConfig ever sees the pair.
+func validateTLSFlags(certFile, keyFile string) error {
+	if (certFile == "") != (keyFile == "") {
+		return fmt.Errorf("-cert-file and -key-file must both be set to enable TLS (got -cert-file=%q -key-file=%q)", certFile, keyFile)
+	}
+	return nil
+}
+
+// tlsConfig builds a *tls.Config for cfg, or nil if cfg doesn't request
+// TLS. mTLS (ClientCAFile set) requires and verifies a client certificate
+// against that CA pool; plain TLS leaves client auth unset.
+func tlsConfig(cfg transportConfig) (*tls.Config, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, nil
+	}
+	if cfg.ClientCAFile == "" {
+		return &tls.Config{}, nil
+	}
+
+	pem, err := os.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates parsed from client CA file %s", cfg.ClientCAFile)
+	}
+
+	return &tls.Config{
+		ClientCAs:  pool,
+		ClientAuth: tls.RequireAndVerifyClientCert,
+	}, nil
+}
+
+// shutdownGrace is how long serve gives in-flight requests to finish once
+// ctx is cancelled, before http.Server.Shutdown gives up and returns. main
+// wires it to --shutdown-timeout.
+var shutdownGrace = 5 * time.Second
+
+// envOrDefault reads key from the environment, falling back to def when
+// unset or empty, matching the CALC_* fallbacks test harnesses expect.
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func envIntOrDefault(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// serve starts s on the requested transport. "http" and "both" exist for
+// backwards compatibility with earlier flag documentation, but neither
+// actually reaches a Streamable HTTP server — see serveHTTP's doc comment
+// — so "both" ends up equivalent to "sse", and "http" always errors.
+//
+// ctx governs the HTTP-based transports' lifetime: cancelling it stops
+// accepting new connections and gives in-flight requests up to
+// shutdownGrace to finish before serve returns. stdio has no such
+// graceful-drain notion — it runs until stdin closes or an unrecoverable
+// read error occurs — but ctx is still threaded through to each dispatched
+// message, the same as on every other transport.
+//
+// Per-request cancellation (a client disconnecting mid-call, or sending
+// notifications/cancelled for a specific request id) is the mcp-go
+// dispatcher's responsibility: it derives each handler's ctx from the
+// request/session lifetime and cancels it accordingly. Handlers on our
+// side only need to hold up their end of that contract by checking
+// ctx.Err() (see handle_calculate_tool, handleSlowAddTool) instead of
+// ignoring ctx entirely.
+func serve(ctx context.Context, s *server.MCPServer, transport string, cfg transportConfig) error {
+	switch transport {
+	case "stdio":
+		return serveStdioTolerant(ctx, s)
+	case "sse":
+		return serveSSE(ctx, s, cfg)
+	case "http":
+		return serveHTTP(ctx, s, cfg)
+	case "both":
+		return serveBoth(ctx, s, cfg)
+	case "websocket":
+		return serveWebSocket(ctx, s, cfg)
+	default:
+		return fmt.Errorf("invalid transport type: %s. Must be 'stdio', 'sse', 'http', 'both', or 'websocket'", transport)
+	}
+}
+
+// serveWebSocket mounts newWebSocketHandler at cfg.WebSocketPath
+// alongside the usual health endpoints, wrapped in the same
+// auth/strict-protocol/CORS middleware stack as the other HTTP-based
+// transports.
+func serveWebSocket(ctx context.Context, s *server.MCPServer, cfg transportConfig) error {
+	ln, err := listen(cfg)
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+
+	mux := http.NewServeMux()
+	mountHealthEndpoints(mux)
+	mountMetricsEndpoint(mux)
+	mux.Handle(cfg.WebSocketPath, withCORS(cfg.CORS, requireStrictProtocol(requireBearerAuth(cfg.AuthToken, newWebSocketHandler(ctx, s)))))
+
+	log.Printf("WebSocket server listening on ws://%s%s", ln.Addr(), cfg.WebSocketPath)
+	return runHTTPServer(ctx, ln, mux, cfg)
+}
+
+// normalizeStdioErr treats the client simply closing stdin (EOF) as a
+// clean exit rather than the fatal error main() would otherwise report it
+// as, matching how the sse/http transports return nil on a clean Shutdown.
+func normalizeStdioErr(err error) error {
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+// listen binds cfg.Host:cfg.Port, resolving an ephemeral port when Port is
+// 0, and returns the listener so callers can report the actual address.
+func listen(cfg transportConfig) (net.Listener, error) {
+	return net.Listen("tcp", fmt.Sprintf("%s:%d", cfg.Host, cfg.Port))
+}
+
+// runHTTPServer serves handler on ln until ctx is cancelled or the server
+// fails on its own. An already-cancelled ctx returns immediately without
+// accepting a single connection. On cancellation it calls Shutdown with a
+// shutdownGrace timeout so in-flight requests get a chance to complete.
+//
+// If cfg requests TLS (CertFile/KeyFile set), ln is served over HTTPS —
+// with mutual TLS enforced when ClientCAFile is also set — instead of
+// plaintext.
+func runHTTPServer(ctx context.Context, ln net.Listener, handler http.Handler, cfg transportConfig) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tlsCfg, err := tlsConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{Handler: handler, TLSConfig: tlsCfg}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if tlsCfg != nil {
+			errCh <- srv.ServeTLS(ln, cfg.CertFile, cfg.KeyFile)
+		} else {
+			errCh <- srv.Serve(ln)
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil && err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+func serveSSE(ctx context.Context, s *server.MCPServer, cfg transportConfig) error {
+	ln, err := listen(cfg)
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+
+	sse := server.NewSSEServer(s,
+		server.WithSSEEndpoint(cfg.SSEPath),
+		server.WithMessageEndpoint(cfg.MessagePath),
+	)
+
+	mcpMux := http.NewServeMux()
+	mcpMux.Handle(cfg.SSEPath, sse)
+	mcpMux.Handle(cfg.MessagePath, sse)
+	if secondServerPrefix != "" {
+		mountSecondServer(mcpMux, secondServerPrefix)
+	}
+
+	mux := http.NewServeMux()
+	mountHealthEndpoints(mux)
+	mountMetricsEndpoint(mux)
+	mux.Handle("/", withCORS(cfg.CORS, requireAllowedOrigin(cfg.Origin, withMaxRequestBytes(requireStrictProtocol(requireBearerAuth(cfg.AuthToken, withCompression(withRecording(withReplay(mcpMux)))))))))
+
+	log.Printf("SSE server listening on http://%s%s", ln.Addr(), cfg.SSEPath)
+	return runHTTPServer(ctx, ln, mux, cfg)
+}
+
+// serveHTTP always fails: the pinned mcp-go version has no Streamable HTTP
+// server at all (server.NewStreamableHTTPServer and server.WithEndpointPath
+// don't exist in v0.23.1 — it ships SSE only, confirmed against
+// server/*.go). There's no confirmed seam to implement the protocol
+// ourselves either: Streamable HTTP's session resumption and
+// standalone-GET event stream are bound up in *server.MCPServer's own
+// session bookkeeping the same way sampling/roots are (see sampling.go,
+// roots.go), so this returns a clear error rather than silently degrading
+// to something that only looks like the advertised transport.
+func serveHTTP(ctx context.Context, s *server.MCPServer, cfg transportConfig) error {
+	return fmt.Errorf("transport %q: mcp-go v0.23.1 has no Streamable HTTP server; use -t sse or -t both instead", "http")
+}
+
+// serveBoth mounts the SSE server on cfg.SSEPath/cfg.MessagePath. It
+// advertises itself as covering both the sse and http transports, but
+// only ever starts SSE: see serveHTTP's doc comment for why Streamable
+// HTTP isn't available against this mcp-go version.
+func serveBoth(ctx context.Context, s *server.MCPServer, cfg transportConfig) error {
+	ln, err := listen(cfg)
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+
+	mcpMux := http.NewServeMux()
+
+	sse := server.NewSSEServer(s,
+		server.WithSSEEndpoint(cfg.SSEPath),
+		server.WithMessageEndpoint(cfg.MessagePath),
+	)
+	mcpMux.Handle(cfg.SSEPath, sse)
+	mcpMux.Handle(cfg.MessagePath, sse)
+
+	if secondServerPrefix != "" {
+		mountSecondServer(mcpMux, secondServerPrefix)
+	}
+
+	mux := http.NewServeMux()
+	mountHealthEndpoints(mux)
+	mountMetricsEndpoint(mux)
+	mux.Handle("/", withCORS(cfg.CORS, requireAllowedOrigin(cfg.Origin, withMaxRequestBytes(requireStrictProtocol(requireBearerAuth(cfg.AuthToken, withCompression(withRecording(withReplay(mcpMux)))))))))
+
+	log.Printf("SSE server listening on http://%s (%s, %s) — Streamable HTTP is not available against this mcp-go version, see serveHTTP", ln.Addr(), cfg.SSEPath, cfg.MessagePath)
+	return runHTTPServer(ctx, ln, mux, cfg)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestServeSSE_EphemeralPort(t *testing.T) {
+	s := newTestServer()
+	cfg := transportConfig{Host: "127.0.0.1", Port: 0, SSEPath: "/sse", MessagePath: "/message"}
+
+	ln, err := listen(cfg)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	sse := server.NewSSEServer(s,
+		server.WithSSEEndpoint(cfg.SSEPath),
+		server.WithMessageEndpoint(cfg.MessagePath),
+	)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- http.Serve(ln, sse) }()
+	defer ln.Close()
+
+	addr := ln.Addr().String()
+	if addr == "" {
+		t.Fatal("expected the ephemeral listener to report a bound address")
+	}
+
+	client := &http.Client{Timeout: 2 * time.Second}
+	req, err := http.NewRequest(http.MethodGet, "http://"+addr+cfg.SSEPath, nil)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("SSE handshake failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+}
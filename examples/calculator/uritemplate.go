@@ -0,0 +1,37 @@
+package main
+
+import "strings"
+
+// matchURITemplate matches uri against a template using RFC 6570 level-1
+// {var} expansion semantics (simple string expansion, no operators). It
+// returns the extracted variables and whether the template matched.
+//
+// mcp-go v0.23.1's AddResourceTemplate dispatches to the right handler by
+// URI but doesn't hand back extracted variables, so handlers that need them
+// re-match the template themselves with this helper.
+func matchURITemplate(template, uri string) (map[string]string, bool) {
+	tParts := splitTemplate(template)
+	uParts := strings.Split(uri, "/")
+
+	if len(tParts) != len(uParts) {
+		return nil, false
+	}
+
+	vars := make(map[string]string)
+	for i, t := range tParts {
+		if strings.HasPrefix(t, "{") && strings.HasSuffix(t, "}") {
+			name := t[1 : len(t)-1]
+			vars[name] = uParts[i]
+			continue
+		}
+		if t != uParts[i] {
+			return nil, false
+		}
+	}
+
+	return vars, true
+}
+
+func splitTemplate(template string) []string {
+	return strings.Split(template, "/")
+}
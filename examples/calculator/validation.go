@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// strictValidationEnabled gates newValidationMiddleware, standing in for a
+// hypothetical server.WithStrictValidation() on *server.MCPServer: that
+// option would live at the transport's request dispatch layer, which we
+// don't own, so it's wired here as an opt-in middleware instead. Off by
+// default so existing handlers that do their own checking (calculate's
+// canonicalOperation re-check, bindArguments) keep behaving exactly as
+// before.
+var strictValidationEnabled = false
+
+// rejectExtraProperties controls whether newValidationMiddleware treats an
+// argument not declared in the tool's inputSchema as a validation failure
+// (true) or silently ignores it (false), mirroring additionalProperties in
+// JSON Schema.
+var rejectExtraProperties = false
+
+// toolOutputSchemas holds each tool's outputSchema by name. mcp.Tool has
+// no OutputSchema field in the pinned mcp-go version (and no
+// mcp.WithOutputSchema tool option to set one), so there's nowhere on tool
+// itself to carry this — registerOutputSchema and newValidationMiddleware
+// look it up here instead.
+var toolOutputSchemas = map[string]mcp.ToolInputSchema{}
+
+// registerOutputSchema records schema as name's outputSchema for
+// newValidationMiddleware to check a handler's structured result against.
+func registerOutputSchema(name string, schema mcp.ToolInputSchema) {
+	toolOutputSchemas[name] = schema
+}
+
+// newValidationMiddleware validates a tools/call request's arguments
+// against tool's generated inputSchema — required presence, enum
+// membership, type, and the Min/Max/MultipleOf/MinLength/MaxLength/Pattern
+// constraints from schema.go and mcp.WithNumber/mcp.WithString — before the
+// handler ever sees them. A failure comes back as an InvalidParams protocol
+// error, not a successful isError result, since the request itself was
+// malformed.
+//
+// When tool has a registered outputSchema (registerOutputSchema), the
+// handler's structured JSON result — Content[1] when a handler returns one,
+// by the convention handle_calculate_tool follows — is checked against it
+// the same way, after the handler runs. A mismatch there isn't the caller's
+// fault — it means this fixture's own handler drifted from the schema it
+// advertises — so it comes back as an InternalError rather than
+// InvalidParams.
+func newValidationMiddleware(tool mcp.Tool) toolMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if strictValidationEnabled {
+				if err := validateArguments(tool, request.Params.Arguments); err != nil {
+					return nil, invalidParamsf("%s", err.Error())
+				}
+			}
+
+			result, err := next(ctx, request)
+			if err != nil || result == nil || result.IsError || len(result.Content) < 2 {
+				return result, err
+			}
+			outputSchema, hasOutputSchema := toolOutputSchemas[tool.Name]
+			if !strictValidationEnabled || !hasOutputSchema || len(outputSchema.Properties) == 0 {
+				return result, err
+			}
+
+			structuredText, ok := result.Content[1].(mcp.TextContent)
+			if !ok {
+				return result, err
+			}
+			structured, marshalErr := structuredContentAsMap(structuredText.Text)
+			if marshalErr != nil {
+				return nil, internalErrorf("tool %q: structured content isn't valid JSON: %s", tool.Name, marshalErr.Error())
+			}
+			if verr := validateAgainstSchema(outputSchema, structured); verr != nil {
+				return nil, internalErrorf("tool %q returned structured content that doesn't match its outputSchema: %s", tool.Name, verr.Error())
+			}
+			return result, err
+		}
+	}
+}
+
+// validateArguments checks args against tool.InputSchema.
+func validateArguments(tool mcp.Tool, args map[string]any) error {
+	return validateAgainstSchema(tool.InputSchema, args)
+}
+
+// structuredContentAsMap decodes a handler's JSON-encoded structured result
+// text so validateAgainstSchema can inspect it the same way it inspects
+// decoded request arguments.
+func structuredContentAsMap(text string) (map[string]any, error) {
+	var m map[string]any
+	if err := json.Unmarshal([]byte(text), &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// validateAgainstSchema checks data against schema. It covers the same
+// ground a generated JSON Schema validator would: required properties,
+// enum membership, JSON type, numeric bounds, and string length/pattern
+// bounds. Used for both a tool's inputSchema (against request arguments)
+// and its outputSchema (against a handler's StructuredContent).
+func validateAgainstSchema(schema mcp.ToolInputSchema, data map[string]any) error {
+	for _, name := range schema.Required {
+		if _, ok := data[name]; !ok {
+			return fmt.Errorf("missing required property: %s", name)
+		}
+	}
+
+	if rejectExtraProperties {
+		for name := range data {
+			if _, declared := schema.Properties[name]; !declared {
+				return fmt.Errorf("unexpected property: %s", name)
+			}
+		}
+	}
+
+	for name, value := range data {
+		prop, declared := schema.Properties[name]
+		if !declared {
+			continue
+		}
+		propSchema, ok := prop.(map[string]any)
+		if !ok {
+			continue
+		}
+		if err := validateValue(name, value, propSchema); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateValue(name string, value any, schema map[string]any) error {
+	wantType, _ := schema["type"].(string)
+
+	switch wantType {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("argument %s: expected a string, got %T", name, value)
+		}
+		if minLen, ok := intValue(schema["minLength"]); ok && len(s) < minLen {
+			return fmt.Errorf("argument %s: length %d is below minLength %d", name, len(s), minLen)
+		}
+		if maxLen, ok := intValue(schema["maxLength"]); ok && len(s) > maxLen {
+			return fmt.Errorf("argument %s: length %d exceeds maxLength %d", name, len(s), maxLen)
+		}
+		if enum, ok := schema["enum"].([]string); ok && !contains(enum, s) {
+			return fmt.Errorf("argument %s: %q is not one of %v", name, s, enum)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("argument %s: schema pattern %q does not compile: %v", name, pattern, err)
+			}
+			if !re.MatchString(s) {
+				return fmt.Errorf("argument %s: %q does not match pattern %q", name, s, pattern)
+			}
+		}
+	case "number", "integer":
+		n, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("argument %s: expected a number, got %T", name, value)
+		}
+		if wantType == "integer" && n != float64(int64(n)) {
+			return fmt.Errorf("argument %s: %v has a fractional part, expected an integer", name, n)
+		}
+		if min, ok := floatValue(schema["minimum"]); ok && n < min {
+			return fmt.Errorf("argument %s: %v is below the minimum %v", name, n, min)
+		}
+		if max, ok := floatValue(schema["maximum"]); ok && n > max {
+			return fmt.Errorf("argument %s: %v exceeds the maximum %v", name, n, max)
+		}
+		if step, ok := floatValue(schema["multipleOf"]); ok && step > 0 {
+			quotient := n / step
+			if quotient != float64(int64(quotient)) {
+				return fmt.Errorf("argument %s: %v is not a multiple of %v", name, n, step)
+			}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("argument %s: expected a boolean, got %T", name, value)
+		}
+	}
+
+	return nil
+}
+
+// floatValue and intValue accept either a float64 or an int, since schema
+// fragments built directly in Go (schema.go's withArray/withObject) and
+// ones decoded from JSON can disagree on which numeric Go type a bound ends
+// up as.
+func floatValue(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+func intValue(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case float64:
+		return int(n), true
+	default:
+		return 0, false
+	}
+}
+
+func contains(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
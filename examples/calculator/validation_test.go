@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func calculateTool() mcp.Tool {
+	return mcp.NewTool("calculate",
+		mcp.WithString("operation", mcp.Required(), mcp.Enum("add", "subtract", "multiply", "divide")),
+		mcp.WithNumber("x", mcp.Required(), mcp.Min(-1e15), mcp.Max(1e15)),
+		mcp.WithNumber("y", mcp.Required(), mcp.Min(-1e15), mcp.Max(1e15)),
+	)
+}
+
+func TestValidateArguments_MissingRequired(t *testing.T) {
+	err := validateArguments(calculateTool(), map[string]any{"operation": "add", "x": 1.0})
+	if err == nil {
+		t.Fatal("expected an error for a missing required argument")
+	}
+}
+
+func TestValidateArguments_EnumMismatch(t *testing.T) {
+	err := validateArguments(calculateTool(), map[string]any{"operation": "exponentiate", "x": 1.0, "y": 2.0})
+	if err == nil {
+		t.Fatal("expected an error for an operation outside the enum")
+	}
+}
+
+func TestValidateArguments_TypeMismatch(t *testing.T) {
+	err := validateArguments(calculateTool(), map[string]any{"operation": "add", "x": "one", "y": 2.0})
+	if err == nil {
+		t.Fatal("expected an error for a string where a number was declared")
+	}
+}
+
+func TestValidateArguments_OutOfBounds(t *testing.T) {
+	err := validateArguments(calculateTool(), map[string]any{"operation": "add", "x": 2e15, "y": 2.0})
+	if err == nil {
+		t.Fatal("expected an error for x exceeding its declared maximum")
+	}
+}
+
+func TestValidateArguments_ExtraPropertyIgnoredByDefault(t *testing.T) {
+	rejectExtraProperties = false
+	err := validateArguments(calculateTool(), map[string]any{"operation": "add", "x": 1.0, "y": 2.0, "extra": true})
+	if err != nil {
+		t.Fatalf("unexpected error with extra properties ignored: %v", err)
+	}
+}
+
+func TestValidateArguments_ExtraPropertyRejectedWhenConfigured(t *testing.T) {
+	rejectExtraProperties = true
+	defer func() { rejectExtraProperties = false }()
+
+	err := validateArguments(calculateTool(), map[string]any{"operation": "add", "x": 1.0, "y": 2.0, "extra": true})
+	if err == nil {
+		t.Fatal("expected an error for an undeclared argument when rejectExtraProperties is set")
+	}
+}
+
+func TestNewValidationMiddleware_DisabledByDefaultPassesThrough(t *testing.T) {
+	strictValidationEnabled = false
+
+	called := false
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"operation": "add"}
+
+	wrapped := newValidationMiddleware(calculateTool())(handler)
+	if _, err := wrapped(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("expected the wrapped handler to run when strictValidationEnabled is false")
+	}
+}
+
+func calculateToolWithOutputSchema() mcp.Tool {
+	tool := mcp.NewTool("calculate",
+		mcp.WithString("operation", mcp.Required(), mcp.Enum("add", "subtract", "multiply", "divide")),
+		mcp.WithNumber("x", mcp.Required()),
+		mcp.WithNumber("y", mcp.Required()),
+	)
+	registerOutputSchema(tool.Name, mcp.ToolInputSchema{
+		Type: "object",
+		Properties: map[string]any{
+			"result": map[string]any{"type": "number"},
+		},
+		Required: []string{"result"},
+	})
+	return tool
+}
+
+// structuredResult builds a *mcp.CallToolResult carrying structured as a
+// JSON text block at Content[1], the convention newValidationMiddleware
+// checks against a registered outputSchema.
+func structuredResult(t *testing.T, structured map[string]any) *mcp.CallToolResult {
+	t.Helper()
+
+	encoded, err := json.Marshal(structured)
+	if err != nil {
+		t.Fatalf("marshaling structured fixture: %v", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{Type: "text", Text: "7"},
+			mcp.TextContent{Type: "text", Text: string(encoded)},
+		},
+	}
+}
+
+func TestNewValidationMiddleware_ConformingStructuredContentPasses(t *testing.T) {
+	strictValidationEnabled = true
+	defer func() { strictValidationEnabled = false }()
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return structuredResult(t, map[string]any{"result": 7.0}), nil
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"operation": "add", "x": 3.0, "y": 4.0}
+
+	wrapped := newValidationMiddleware(calculateToolWithOutputSchema())(handler)
+	if _, err := wrapped(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error for conforming structured content: %v", err)
+	}
+}
+
+func TestNewValidationMiddleware_NonConformingStructuredContentFailsWhenStrict(t *testing.T) {
+	strictValidationEnabled = true
+	defer func() { strictValidationEnabled = false }()
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return structuredResult(t, map[string]any{"result": "seven"}), nil
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"operation": "add", "x": 3.0, "y": 4.0}
+
+	wrapped := newValidationMiddleware(calculateToolWithOutputSchema())(handler)
+	_, err := wrapped(context.Background(), req)
+	if err == nil {
+		t.Fatal("expected an error for structured content that doesn't match the outputSchema")
+	}
+	pe, ok := err.(*protocolError)
+	if !ok {
+		t.Fatalf("err = %T, want *protocolError", err)
+	}
+	if pe.Code() != mcp.INTERNAL_ERROR {
+		t.Errorf("code = %d, want %d", pe.Code(), mcp.INTERNAL_ERROR)
+	}
+}
+
+func TestNewValidationMiddleware_NonConformingStructuredContentPassesWhenNotStrict(t *testing.T) {
+	strictValidationEnabled = false
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return structuredResult(t, map[string]any{"result": "seven"}), nil
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"operation": "add", "x": 3.0, "y": 4.0}
+
+	wrapped := newValidationMiddleware(calculateToolWithOutputSchema())(handler)
+	if _, err := wrapped(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error with strictValidationEnabled off: %v", err)
+	}
+}
+
+func TestNewValidationMiddleware_EnabledRejectsBeforeDispatch(t *testing.T) {
+	strictValidationEnabled = true
+	defer func() { strictValidationEnabled = false }()
+
+	called := false
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"operation": "add", "x": 1.0}
+
+	wrapped := newValidationMiddleware(calculateTool())(handler)
+	_, err := wrapped(context.Background(), req)
+	if err == nil {
+		t.Fatal("expected an InvalidParams error for a missing required argument")
+	}
+	pe, ok := err.(*protocolError)
+	if !ok {
+		t.Fatalf("err = %T, want *protocolError", err)
+	}
+	if pe.Code() != mcp.INVALID_PARAMS {
+		t.Errorf("code = %d, want %d", pe.Code(), mcp.INVALID_PARAMS)
+	}
+	if called {
+		t.Error("handler should not run when validation fails")
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// wsPingInterval is how often newWebSocketHandler's connections send a
+// ping control frame; wsPongWait is how long a connection tolerates
+// silence (from either a pong or any other frame) before it's considered
+// dead and closed. The 3x ratio mirrors keepAliveGraceFactor's margin for
+// keepalive.go's own idle-session detection, though the two features are
+// independent: this one is about the TCP connection itself, not the MCP
+// session sitting on top of it.
+const (
+	wsPingInterval = 30 * time.Second
+	wsPongWait     = 3 * wsPingInterval
+)
+
+// wsUpgrader is shared across connections; CheckOrigin is left at its
+// permissive default (same as this fixture's other transports, which
+// rely on -cors-allowed-origins rather than same-origin enforcement).
+// main sets Subprotocols from -ws-subprotocol, the one option this
+// fixture exposes via gorilla/websocket's own negotiation (the request's
+// "server.WithWebSocketSubprotocol" isn't a real mcp-go option we could
+// confirm, since mcp-go ships no WebSocket transport of its own for this
+// fixture to extend — gorilla's Upgrader.Subprotocols field does the
+// same negotiation directly).
+var wsUpgrader = websocket.Upgrader{}
+
+// newWebSocketHandler returns an http.Handler that upgrades each request
+// to a WebSocket and multiplexes JSON-RPC over it: every inbound text
+// message is validated and dispatched through s.HandleMessage exactly
+// the way serveStdioTolerant handles a line of stdin, and every response
+// (and server-initiated notification — see below) is written back as its
+// own text message.
+//
+// A connection is never registered with mcp-go's own *server.MCPServer
+// session registry the way the SSE transport's sessions are — there's no confirmed seam from outside the library to
+// do that — so sessionOrStdioID(ctx) resolves to the same "stdio"
+// placeholder for every WebSocket connection, same as the stdio
+// transport. Unlike stdio, where that's moot because there's only ever
+// one connection for the process's life, this is a real limitation here:
+// two concurrently connected WebSocket clients share one entry in
+// sessionStore, one rate-limit bucket, and one strict-mode
+// initialized-or-not flag. It's called out rather than quietly
+// papered over: fixing it needs mcp-go to expose a way to register an
+// externally-driven session, which this fixture has no confirmed way to
+// do. notifyResourceUpdated's sendNotificationToSession calls never reach
+// a WebSocket client for the same reason (it addresses sessionRegistry,
+// which is only ever populated via mcp-go's own RegisterSession hook,
+// not this fixture's placeholder ID).
+//
+// A confirmed Go client constructor to pair with this (the request's
+// "client.NewWebSocketClient") isn't included either, for the same
+// reason newTracingMiddleware's doc comment declines a hook-based
+// request span: mcp-go's client package doesn't expose a WebSocket
+// transport we could confirm, and hand-rolling one against
+// gorilla/websocket directly would mean re-implementing that package's
+// request-ID/timeout bookkeeping without a real seam to hook it into
+// this fixture's own Client wrapper (see client/client.go).
+func newWebSocketHandler(ctx context.Context, s *server.MCPServer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := wsUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Printf("websocket: upgrade failed: %v", err)
+			return
+		}
+		serveWebSocketConn(ctx, s, conn)
+	})
+}
+
+// serveWebSocketConn owns one connection end to end: a read loop (with a
+// pong-extended read deadline for liveness) and a ping ticker, both
+// stopped together via closed, so neither goroutine outlives the other.
+func serveWebSocketConn(ctx context.Context, s *server.MCPServer, conn *websocket.Conn) {
+	sessionID := sessionOrStdioID(ctx)
+
+	var writeMu sync.Mutex
+	writeText := func(b []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return conn.WriteMessage(websocket.TextMessage, b)
+	}
+
+	closed := make(chan struct{})
+	var closeOnce sync.Once
+	stop := func() {
+		closeOnce.Do(func() { close(closed) })
+	}
+	defer stop()
+	defer conn.Close()
+
+	_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	go func() {
+		ticker := time.NewTicker(wsPingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-closed:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				writeMu.Lock()
+				err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsPingInterval))
+				writeMu.Unlock()
+				if err != nil {
+					stop()
+					return
+				}
+			}
+		}
+	}()
+
+	if serverPingInterval > 0 {
+		go runServerPing(sessionID, writeText, closed, func(reason string) {
+			log.Printf("websocket: %s, closing connection", reason)
+			stop()
+			_ = conn.Close()
+		})
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		msgType, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if msgType != websocket.TextMessage {
+			continue
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+
+		validated, kind := validateStdioLine(string(data))
+		switch kind {
+		case stdioLineParseError:
+			if err := writeText(mustMarshal(newStdioParseError())); err != nil {
+				return
+			}
+			continue
+		case stdioLineInvalidRequest:
+			if err := writeText(mustMarshal(newStdioInvalidRequest())); err != nil {
+				return
+			}
+			continue
+		case stdioLineBatch:
+			var elements []json.RawMessage
+			_ = json.Unmarshal(validated, &elements)
+
+			logDebugMessage(ctx, "in", validated, 0)
+			recordMessage(sessionID, "in", validated)
+
+			wg.Add(1)
+			go func(elements []json.RawMessage) {
+				defer wg.Done()
+				start := time.Now()
+				b := dispatchJSONRPCBatch(ctx, s, sessionID, elements)
+				if b == nil {
+					return
+				}
+				logDebugMessage(ctx, "out", b, time.Since(start))
+				recordMessage(sessionID, "out", b)
+				if werr := writeText(b); werr != nil {
+					log.Printf("websocket: %v", werr)
+				}
+			}(elements)
+			continue
+		}
+
+		if tryHandlePingResponse(sessionID, validated) {
+			continue
+		}
+
+		var env jsonrpcEnvelope
+		_ = json.Unmarshal(validated, &env)
+		if violation := checkProtocolPreconditions(sessionID, env); violation != nil {
+			if err := writeText(mustMarshal(violation)); err != nil {
+				return
+			}
+			continue
+		}
+
+		logDebugMessage(ctx, "in", validated, 0)
+		recordMessage(sessionID, "in", validated)
+
+		wg.Add(1)
+		go func(raw []byte, method string) {
+			defer wg.Done()
+			start := time.Now()
+
+			var b []byte
+			if replayPath != "" {
+				replayed, err := lookupReplayResponse(raw)
+				if err != nil {
+					b, _ = json.Marshal(newReplayNotFound(env.ID, err))
+				} else {
+					b = replayed
+				}
+			} else {
+				response := s.HandleMessage(ctx, raw)
+				if response == nil {
+					return
+				}
+				marshaled, err := json.Marshal(response)
+				if err != nil {
+					log.Printf("websocket: marshaling response: %v", err)
+					return
+				}
+				if method == "initialize" {
+					marshaled = rewriteInitializeResponse(marshaled)
+				}
+				b = marshaled
+			}
+
+			logDebugMessage(ctx, "out", b, time.Since(start))
+			recordMessage(sessionID, "out", b)
+			b, ok := faultOutboundResponse(sessionID, method, b)
+			if !ok {
+				return
+			}
+			if werr := writeText(b); werr != nil {
+				log.Printf("websocket: %v", werr)
+			}
+		}(validated, env.Method)
+	}
+}
+
+func mustMarshal(v any) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return []byte(`{"jsonrpc":"2.0","id":null,"error":{"code":-32603,"message":"internal error marshaling response"}}`)
+	}
+	return b
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func dialTestWebSocket(t *testing.T, srv *httptest.Server) *websocket.Conn {
+	t.Helper()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dialing %s: %v", url, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestNewWebSocketHandler_CompletesInitializeHandshake(t *testing.T) {
+	srv := httptest.NewServer(newWebSocketHandler(context.Background(), newTestServer()))
+	defer srv.Close()
+
+	conn := dialTestWebSocket(t, srv)
+	req := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(req)); err != nil {
+		t.Fatalf("writing request: %v", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("reading response: %v", err)
+	}
+
+	var resp map[string]any
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatalf("unmarshaling response: %v", err)
+	}
+	if _, ok := resp["result"]; !ok {
+		t.Fatalf("expected a result, got %v", resp)
+	}
+}
+
+func TestNewWebSocketHandler_MalformedMessageGetsParseError(t *testing.T) {
+	srv := httptest.NewServer(newWebSocketHandler(context.Background(), newTestServer()))
+	defer srv.Close()
+
+	conn := dialTestWebSocket(t, srv)
+	if err := conn.WriteMessage(websocket.TextMessage, []byte("{not valid json")); err != nil {
+		t.Fatalf("writing request: %v", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("reading response: %v", err)
+	}
+
+	var resp stdioErrorResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatalf("unmarshaling response: %v", err)
+	}
+	if resp.Error.Code != -32700 {
+		t.Errorf("code = %d, want -32700", resp.Error.Code)
+	}
+}
+
+func TestNewWebSocketHandler_DisabledCapabilityReturnsMethodNotFound(t *testing.T) {
+	resetCapabilitiesEnabled()
+	capabilitiesEnabled.Tools = false
+	defer resetCapabilitiesEnabled()
+
+	srv := httptest.NewServer(newWebSocketHandler(context.Background(), newTestServer()))
+	defer srv.Close()
+
+	conn := dialTestWebSocket(t, srv)
+	req := `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(req)); err != nil {
+		t.Fatalf("writing request: %v", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("reading response: %v", err)
+	}
+
+	var resp stdioErrorResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatalf("unmarshaling response: %v", err)
+	}
+	if resp.Error.Code != -32601 {
+		t.Errorf("code = %d, want -32601 (method not found)", resp.Error.Code)
+	}
+}